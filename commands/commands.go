@@ -111,7 +111,10 @@ func (c *Command) ApplyTemplate(serviceStatus *svcstatus.Status) (command Comman
 
 	command = Command(make([]string, len(*c)))
 	copy(command, *c)
-	serviceInfo := util.ServiceInfo{LatestVersion: serviceStatus.LatestVersion()}
+	serviceInfo := util.ServiceInfo{
+		LatestVersion: serviceStatus.LatestVersion(),
+		Asset:         serviceStatus.LatestVersionAsset(),
+		Release:       serviceStatus.LatestVersionRelease()}
 	for i := range command {
 		command[i] = util.TemplateString(command[i], serviceInfo)
 	}
@@ -20,6 +20,7 @@ import (
 
 	dbtype "github.com/release-argus/Argus/db/types"
 	"github.com/release-argus/Argus/service"
+	"github.com/release-argus/Argus/service/scheduler"
 	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/util"
 	"gopkg.in/yaml.v3"
@@ -52,6 +53,8 @@ func (c *Config) Init() {
 	jLog.SetTimestamps(*c.Settings.LogTimestamps())
 	jLog.SetLevel(c.Settings.LogLevel())
 
+	scheduler.Default.SetMaxConcurrent(c.Settings.QueryMaxConcurrent())
+
 	i := 0
 	for _, name := range c.Order {
 		i++
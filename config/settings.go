@@ -26,14 +26,15 @@ import (
 
 // Export the flags.
 var (
-	LogLevel         = flag.String("log.level", "INFO", "ERROR, WARN, INFO, VERBOSE or DEBUG")
-	LogTimestamps    = flag.Bool("log.timestamps", false, "Enable timestamps in CLI output.")
-	DataDatabaseFile = flag.String("data.database-file", "data/argus.db", "Database file path.")
-	WebListenHost    = flag.String("web.listen-host", "0.0.0.0", "IP address to listen on for UI, API, and telemetry.")
-	WebListenPort    = flag.String("web.listen-port", "8080", "Port to listen on for UI, API, and telemetry.")
-	WebCertFile      = flag.String("web.cert-file", "", "HTTPS certificate file path.")
-	WebPKeyFile      = flag.String("web.pkey-file", "", "HTTPS private key file path.")
-	WebRoutePrefix   = flag.String("web.route-prefix", "/", "Prefix for web endpoints")
+	LogLevel           = flag.String("log.level", "INFO", "ERROR, WARN, INFO, VERBOSE or DEBUG")
+	LogTimestamps      = flag.Bool("log.timestamps", false, "Enable timestamps in CLI output.")
+	DataDatabaseFile   = flag.String("data.database-file", "data/argus.db", "Database file path.")
+	QueryMaxConcurrent = flag.Int("query.max-concurrent", 10, "Maximum number of version queries to run at once.")
+	WebListenHost      = flag.String("web.listen-host", "0.0.0.0", "IP address to listen on for UI, API, and telemetry.")
+	WebListenPort      = flag.String("web.listen-port", "8080", "Port to listen on for UI, API, and telemetry.")
+	WebCertFile        = flag.String("web.cert-file", "", "HTTPS certificate file path.")
+	WebPKeyFile        = flag.String("web.pkey-file", "", "HTTPS private key file path.")
+	WebRoutePrefix     = flag.String("web.route-prefix", "/", "Prefix for web endpoints")
 )
 
 // Settings for the binary.
@@ -57,9 +58,10 @@ func (s *Settings) String(prefix string) (str string) {
 //
 // (Used in Defaults)
 type SettingsBase struct {
-	Log  LogSettings  `yaml:"log,omitempty"`  // Log settings
-	Data DataSettings `yaml:"data,omitempty"` // Data settings
-	Web  WebSettings  `yaml:"web,omitempty"`  // Web settings
+	Log   LogSettings   `yaml:"log,omitempty"`   // Log settings
+	Data  DataSettings  `yaml:"data,omitempty"`  // Data settings
+	Query QuerySettings `yaml:"query,omitempty"` // Query settings
+	Web   WebSettings   `yaml:"web,omitempty"`   // Web settings
 }
 
 // MapEnvToStruct maps environment variables to this struct.
@@ -84,6 +86,11 @@ type DataSettings struct {
 	DatabaseFile *string `yaml:"database_file,omitempty"` // Database path
 }
 
+// QuerySettings for the binary.
+type QuerySettings struct {
+	MaxConcurrent *int `yaml:"max_concurrent,omitempty"` // Maximum number of queries to run at once
+}
+
 // WebSettings for the binary.
 type WebSettings struct {
 	ListenHost  *string               `yaml:"listen_host,omitempty"`  // Web listen host
@@ -110,6 +117,9 @@ func (s *Settings) NilUndefinedFlags(flagset *map[string]bool) {
 	if !(*flagset)["data.database-file"] {
 		DataDatabaseFile = nil
 	}
+	if !(*flagset)["query.max-concurrent"] {
+		QueryMaxConcurrent = nil
+	}
 	if !(*flagset)["web.listen-host"] {
 		WebListenHost = nil
 	}
@@ -154,6 +164,16 @@ func (s *Settings) SetDefaults() {
 	dataDatabaseFile := "data/argus.db"
 	s.HardDefaults.Data.DatabaseFile = &dataDatabaseFile
 
+	// #########
+	// # QUERY #
+	// #########
+	s.FromFlags.Query = QuerySettings{}
+
+	// MaxConcurrent
+	s.FromFlags.Query.MaxConcurrent = QueryMaxConcurrent
+	queryMaxConcurrent := 10
+	s.HardDefaults.Query.MaxConcurrent = &queryMaxConcurrent
+
 	// #######
 	// # WEB #
 	// #######
@@ -206,6 +226,14 @@ func (s *Settings) DataDatabaseFile() *string {
 		s.HardDefaults.Data.DatabaseFile)
 }
 
+// QueryMaxConcurrent.
+func (s *Settings) QueryMaxConcurrent() int {
+	return *util.FirstNonNilPtr(
+		s.FromFlags.Query.MaxConcurrent,
+		s.Query.MaxConcurrent,
+		s.HardDefaults.Query.MaxConcurrent)
+}
+
 // WebListenHost.
 func (s *Settings) WebListenHost() string {
 	return *util.FirstNonNilPtr(
@@ -224,6 +224,45 @@ func TestSettings_GetString(t *testing.T) {
 	}
 }
 
+func TestSettings_QueryMaxConcurrent(t *testing.T) {
+	// GIVEN query.max-concurrent set at different priority levels in Settings
+	tests := map[string]struct {
+		flagVal   *int
+		configVal *int
+		want      int
+	}{
+		"hard default": {
+			want: 10},
+		"config": {
+			configVal: intPtr(5),
+			want:      5},
+		"flag": {
+			flagVal:   intPtr(2),
+			configVal: intPtr(5),
+			want:      2},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+
+			settings := testSettings()
+			settings.Query.MaxConcurrent = tc.configVal
+			QueryMaxConcurrent = tc.flagVal
+
+			// WHEN SetDefaults is called on it
+			settings.SetDefaults()
+
+			// THEN QueryMaxConcurrent is taken from the highest priority level
+			if got := settings.QueryMaxConcurrent(); got != tc.want {
+				t.Errorf("%s:\nwant: %d\ngot:  %d",
+					name, tc.want, got)
+			}
+		})
+	}
+	QueryMaxConcurrent = nil
+}
+
 func TestSettings_MapEnvToStruct(t *testing.T) {
 	// GIVEN vars set for Settings vars
 	tests := map[string]struct {
@@ -22,6 +22,7 @@ import (
 	"strings"
 
 	"github.com/release-argus/Argus/config"
+	svcstatus "github.com/release-argus/Argus/service/status"
 	"github.com/release-argus/Argus/util"
 )
 
@@ -94,12 +95,56 @@ func (api *api) initialise() {
 			latest_version_timestamp DATETIME DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
 			deployed_version STRING DEFAULT '',
 			deployed_version_timestamp DATETIME DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
-			approved_version STRING DEFAULT ''
+			approved_version STRING DEFAULT '',
+			latest_version_cache STRING DEFAULT '',
+			deployed_version_cache STRING DEFAULT '',
+			pending_version STRING DEFAULT '',
+			pending_version_timestamp STRING DEFAULT ''
 		);`
 	_, err = db.Exec(sqlStmt)
 	jLog.Fatal(util.ErrorToString(err), *logFrom, err != nil)
 
 	api.db = db
+	api.addMissingColumns()
+}
+
+// addMissingColumns will add the columns that a status table created by an older version is missing.
+func (api *api) addMissingColumns() {
+	rows, err := api.db.Query(`PRAGMA table_info(status);`)
+	jLog.Fatal(err, *logFrom, err != nil)
+	columns := map[string]bool{}
+	for rows.Next() {
+		var (
+			cid          int
+			name         string
+			columnType   string
+			notNull      int
+			defaultValue sql.NullString
+			primaryKey   int
+		)
+		err = rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &primaryKey)
+		jLog.Fatal(
+			fmt.Sprintf("addMissingColumns row: %s", util.ErrorToString(err)),
+			*logFrom,
+			err != nil)
+		columns[name] = true
+	}
+	rows.Close()
+
+	for _, column := range []string{
+		"latest_version_cache", "deployed_version_cache",
+		"pending_version", "pending_version_timestamp"} {
+		if columns[column] {
+			continue
+		}
+		_, err = api.db.Exec(fmt.Sprintf(
+			"ALTER TABLE status ADD COLUMN %s STRING DEFAULT '';",
+			column))
+		jLog.Fatal(
+			fmt.Sprintf("addMissingColumns %q: %s", column, util.ErrorToString(err)),
+			*logFrom,
+			err != nil)
+	}
 }
 
 // removeUnknownServices will remove rows with an id not in config.Order
@@ -136,7 +181,11 @@ func (api *api) extractServiceStatus() {
 		latest_version_timestamp,
 		deployed_version,
 		deployed_version_timestamp,
-		approved_version
+		approved_version,
+		latest_version_cache,
+		deployed_version_cache,
+		pending_version,
+		pending_version_timestamp
 	FROM status;`)
 	jLog.Fatal(err, *logFrom, err != nil)
 	defer rows.Close()
@@ -151,8 +200,12 @@ func (api *api) extractServiceStatus() {
 			dv  string
 			dvt string
 			av  string
+			lvc string
+			dvc string
+			pv  string
+			pvt string
 		)
-		err = rows.Scan(&id, &lv, &lvt, &dv, &dvt, &av)
+		err = rows.Scan(&id, &lv, &lvt, &dv, &dvt, &av, &lvc, &dvc, &pv, &pvt)
 		jLog.Fatal(
 			fmt.Sprintf("extractServiceStatus row: %s", util.ErrorToString(err)),
 			*logFrom,
@@ -162,6 +215,9 @@ func (api *api) extractServiceStatus() {
 		api.config.Service[id].Status.SetDeployedVersion(dv, false)
 		api.config.Service[id].Status.SetDeployedVersionTimestamp(dvt)
 		api.config.Service[id].Status.SetApprovedVersion(av, false)
+		api.config.Service[id].Status.SetLatestVersionCache(svcstatus.ParseHTTPCache(lvc), false)
+		api.config.Service[id].Status.SetDeployedVersionCache(svcstatus.ParseHTTPCache(dvc), false)
+		api.config.Service[id].Status.SetPendingVersion(pv, pvt, false)
 	}
 	err = rows.Err()
 	jLog.Fatal(
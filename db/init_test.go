@@ -17,6 +17,7 @@
 package db
 
 import (
+	"database/sql"
 	"fmt"
 	"math/rand"
 	"os"
@@ -144,6 +145,68 @@ func TestAPI_Initialise(t *testing.T) {
 	os.Remove(*api.config.Settings.Data.DatabaseFile)
 }
 
+func TestAPI_AddMissingColumns(t *testing.T) {
+	// GIVEN a db with the status table of an older version
+	cfg := testConfig()
+	api := api{config: cfg}
+	*api.config.Settings.Data.DatabaseFile = "TestAddMissingColumns.db"
+	os.Remove(*api.config.Settings.Data.DatabaseFile)
+	defer os.Remove(*api.config.Settings.Data.DatabaseFile)
+	db, err := sql.Open("sqlite", *api.config.Settings.Data.DatabaseFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec(`
+	CREATE TABLE status
+		(
+			id STRING NOT NULL PRIMARY KEY,
+			latest_version STRING DEFAULT '',
+			latest_version_timestamp DATETIME DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+			deployed_version STRING DEFAULT '',
+			deployed_version_timestamp DATETIME DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ', 'now')),
+			approved_version STRING DEFAULT ''
+		);
+	INSERT INTO status (id, latest_version) VALUES ('keep0', '1.2.3');`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// WHEN the db is initialised
+	api.initialise()
+	defer api.db.Close()
+
+	// THEN the missing columns are added, keeping the existing rows
+	var (
+		lv  string
+		lvc string
+		dvc string
+		pv  string
+		pvt string
+	)
+	err = api.db.QueryRow(`
+		SELECT	latest_version,
+				latest_version_cache,
+				deployed_version_cache,
+				pending_version,
+				pending_version_timestamp
+		FROM status
+		WHERE id = 'keep0';`).Scan(&lv, &lvc, &dvc, &pv, &pvt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv != "1.2.3" || lvc != "" || dvc != "" || pv != "" || pvt != "" {
+		t.Errorf("unexpected row: %q, %q, %q, %q, %q",
+			lv, lvc, dvc, pv, pvt)
+	}
+
+	// WHEN it's initialised again
+	api.db.Close()
+	api.initialise()
+
+	// THEN it doesn't try to add the columns again (would Fatal)
+}
+
 func TestDBQueryService(t *testing.T) {
 	// GIVEN a blank DB
 	cfg := testConfig()
@@ -335,6 +398,7 @@ func TestAPI_extractServiceStatus(t *testing.T) {
 		wantStatus[index].SetDeployedVersion(fmt.Sprintf("%d.%d.%d", rand.Intn(10), rand.Intn(10), rand.Intn(10)), false)
 		wantStatus[index].SetDeployedVersionTimestamp(time.Now().UTC().Format(time.RFC3339))
 		wantStatus[index].SetApprovedVersion(fmt.Sprintf("%d.%d.%d", rand.Intn(10), rand.Intn(10), rand.Intn(10)), false)
+		wantStatus[index].SetPendingVersion(fmt.Sprintf("%d.%d.%d", rand.Intn(10), rand.Intn(10), rand.Intn(10)), time.Now().UTC().Format(time.RFC3339), false)
 
 		*cfg.DatabaseChannel <- dbtype.Message{
 			ServiceID: id,
@@ -344,7 +408,9 @@ func TestAPI_extractServiceStatus(t *testing.T) {
 				{Column: "latest_version_timestamp", Value: wantStatus[index].LatestVersionTimestamp()},
 				{Column: "deployed_version", Value: wantStatus[index].DeployedVersion()},
 				{Column: "deployed_version_timestamp", Value: wantStatus[index].DeployedVersionTimestamp()},
-				{Column: "approved_version", Value: wantStatus[index].ApprovedVersion()}}}
+				{Column: "approved_version", Value: wantStatus[index].ApprovedVersion()},
+				{Column: "pending_version", Value: wantStatus[index].PendingVersion()},
+				{Column: "pending_version_timestamp", Value: wantStatus[index].PendingVersionTimestamp()}}}
 		// Clear the Status in the Config
 		svc.Status = *svcstatus.New(
 			svc.Status.AnnounceChannel, svc.Status.DatabaseChannel, svc.Status.SaveChannel,
@@ -381,5 +447,13 @@ func TestAPI_extractServiceStatus(t *testing.T) {
 			t.Errorf("Expected %q to be updated to %q\ngot %q, want %q",
 				"approved_version", row.ApprovedVersion(), row, wantStatus[i].String())
 		}
+		// AND the PendingVersion is restored (it's not in the row helper)
+		status := &cfg.Service[*wantStatus[i].ServiceID].Status
+		if status.PendingVersion() != wantStatus[i].PendingVersion() ||
+			status.PendingVersionTimestamp() != wantStatus[i].PendingVersionTimestamp() {
+			t.Errorf("Expected %q to be restored to %q (%q)\ngot %q (%q)",
+				"pending_version", wantStatus[i].PendingVersion(), wantStatus[i].PendingVersionTimestamp(),
+				status.PendingVersion(), status.PendingVersionTimestamp())
+		}
 	}
 }
@@ -15,8 +15,8 @@
 package deployedver
 
 import (
+	"context"
 	"crypto/tls"
-	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -24,32 +24,48 @@ import (
 	"strings"
 	"time"
 
-	"github.com/coreos/go-semver/semver"
+	"github.com/release-argus/Argus/service/scheduler"
 	"github.com/release-argus/Argus/util"
 	metric "github.com/release-argus/Argus/web/metrics"
 )
 
-// Track the deployed version (DeployedVersion) of the `parent`.
+// queryTimeout is the longest that a query of the deployed version may take.
+const queryTimeout = 30 * time.Second
+
+// Track the deployed version (DeployedVersion) of the `parent` on the Scheduler
+// until the Service is deleted.
 func (l *Lookup) Track() {
 	if l == nil {
 		return
 	}
-	logFrom := util.LogFrom{Primary: *l.Status.ServiceID}
 
-	// Track forever.
-	for {
-		// If we're deleting this Service, stop tracking it.
-		if l.Status.Deleting() {
-			return
-		}
+	// Give LatestVersion some time to query first.
+	firstQuery := time.Now()
+	if nextQuery, err := time.Parse(time.RFC3339, l.Status.NextQuery()); err == nil && nextQuery.After(firstQuery) {
+		firstQuery = nextQuery
+	}
+	firstQuery = firstQuery.Add(2 * time.Second)
 
-		// Query the deployed version.
-		deployedVersion, _ := l.Query(true, &logFrom)
-		// If new release found by ^ query.
-		l.HandleNewVersion(deployedVersion, true)
-		// Sleep interval between queries.
-		time.Sleep(l.Options.GetIntervalDuration())
+	scheduler.Default.Add(l.Status.Context(), *l.Status.ServiceID+"/deployed_version", firstQuery, l.track)
+}
+
+// track queries the deployed version, and returns the time of the next query.
+func (l *Lookup) track(ctx context.Context) time.Time {
+	// If we're deleting this Service, stop tracking it.
+	if ctx.Err() != nil {
+		return time.Time{}
 	}
+
+	// Plan the query after this one.
+	nextQuery := l.Options.NextQuery(time.Now())
+
+	// Query the deployed version.
+	logFrom := util.LogFrom{Primary: *l.Status.ServiceID}
+	deployedVersion, _ := l.Query(true, &logFrom)
+	// If new release found by ^ query.
+	l.HandleNewVersion(deployedVersion, true)
+
+	return nextQuery
 }
 
 // query the deployed version (DeployedVersion) of the Service.
@@ -62,36 +78,14 @@ func (l *Lookup) query(logFrom *util.LogFrom) (string, error) {
 	var version string
 	// If JSON is provided, use it to extract the version.
 	if l.JSON != "" {
-		jsonKeys := strings.Split(l.JSON, ".")
-		var queriedJSON map[string]interface{}
-		err := json.Unmarshal(rawBody, &queriedJSON)
-		// If the JSON is invalid, return an error.
+		values, err := util.GetValuesFromJSON(rawBody, l.JSON)
 		if err != nil {
-			err := fmt.Errorf("failed to unmarshal the following from %q into json:%s",
-				l.URL, string(rawBody))
-			jLog.Error(err, *logFrom, true)
+			err := fmt.Errorf("%q could not be found in the following JSON (%w):\n%s",
+				l.JSON, err, string(rawBody))
+			jLog.Warn(err, *logFrom, true)
 			return "", err
 		}
-
-		// Iterate through the keys.
-		for k := range jsonKeys {
-			// If the key doesn't exist, return an error.
-			if queriedJSON[jsonKeys[k]] == nil {
-				err := fmt.Errorf("%q could not be found in the following JSON. Failed at %q:\n%s",
-					l.JSON, jsonKeys[k], string(rawBody))
-				jLog.Warn(err, *logFrom, true)
-				return "", err
-			}
-
-			switch v := queriedJSON[jsonKeys[k]].(type) {
-			// If the key is a string, int, float32, or float64, return it.
-			case string, int, float32, float64:
-				version = fmt.Sprint(queriedJSON[jsonKeys[k]])
-				// If the key is a map, set it as the queriedJSON.
-			case map[string]interface{}:
-				queriedJSON = v
-			}
-		}
+		version = values[0]
 	} else {
 		// Use the whole body if not parsing as JSON.
 		version = string(rawBody)
@@ -118,13 +112,13 @@ func (l *Lookup) query(logFrom *util.LogFrom) (string, error) {
 
 	// If semantic versioning is enabled, check that the version is in the correct format.
 	if l.Options.GetSemanticVersioning() {
-		_, err = semver.NewVersion(version)
+		_, err = l.Options.ParseVersion(version)
 		if err != nil {
 			err = fmt.Errorf("failed converting %q to a semantic version. If all "+
 				"versions are in this style, consider adding json/regex to get the version into the "+
-				"style of 'MAJOR.MINOR.PATCH' (https://semver.org/), or disabling semantic versioning "+
+				"style of %s, or disabling semantic versioning "+
 				"(globally with defaults.service.semantic_versioning or just for this service with the semantic_versioning var)",
-				version)
+				version, l.Options.GetVersionStyle())
 			jLog.Error(err, *logFrom, true)
 			return "", err
 		}
@@ -187,13 +181,13 @@ func (l *Lookup) HandleNewVersion(version string, writeToDB bool) {
 		l.Status.AnnounceQueryNewVersion()
 	} else if version != latestVersion &&
 		l.Options.GetSemanticVersioning() {
-		//#nosec G104 -- Disregard as deployedVersion will always be semantic if GetSemanticVersioning
-		deployedVersionSV, _ := semver.NewVersion(version)
-		//#nosec G104 -- Disregard as LatestVersion will always be semantic if GetSemanticVersioning
-		latestVersionSV, _ := semver.NewVersion(latestVersion)
+		deployedVersionSV, deployedErr := l.Options.ParseVersion(version)
+		latestVersionSV, latestErr := l.Options.ParseVersion(latestVersion)
 
 		// Update LatestVersion to DeployedVersion if it's newer
-		if latestVersionSV.LessThan(*deployedVersionSV) {
+		// (can't compare if either don't follow the version_scheme, e.g. the version_scheme changed)
+		if deployedErr == nil && latestErr == nil &&
+			latestVersionSV.LessThan(deployedVersionSV) {
 			l.Status.SetLatestVersion(l.Status.DeployedVersion(), writeToDB)
 			l.Status.SetLatestVersionTimestamp(l.Status.DeployedVersionTimestamp())
 			l.Status.AnnounceQueryNewVersion()
@@ -217,7 +211,7 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBody []byte, err error)
 		customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	}
 
-	req, err := http.NewRequest(http.MethodGet, l.URL, nil)
+	req, err := http.NewRequestWithContext(l.Status.Context(), http.MethodGet, l.URL, nil)
 	if err != nil {
 		jLog.Error(err, *logFrom, true)
 		return
@@ -234,7 +228,13 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBody []byte, err error)
 		req.SetBasicAuth(l.BasicAuth.Username, l.BasicAuth.Password)
 	}
 
-	client := &http.Client{Transport: customTransport}
+	// Conditional requests - reuse the cached body if the page is unchanged
+	cache := l.Status.DeployedVersionCache()
+	cache.SetConditionalHeaders(req)
+
+	client := &http.Client{
+		Transport: customTransport,
+		Timeout:   queryTimeout}
 	resp, err := client.Do(req)
 	if err != nil {
 		// Don't crash on invalid certs.
@@ -251,5 +251,13 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBody []byte, err error)
 	defer resp.Body.Close()
 	rawBody, err = io.ReadAll(resp.Body)
 	jLog.Error(err, *logFrom, err != nil)
+	if err == nil {
+		var notModified bool
+		rawBody, cache, notModified = cache.Response(req, resp, rawBody)
+		if notModified {
+			jLog.Verbose("Using cached body (not modified)", *logFrom, true)
+		}
+		l.Status.SetDeployedVersionCache(cache, true)
+	}
 	return
 }
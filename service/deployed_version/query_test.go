@@ -18,6 +18,8 @@ package deployedver
 
 import (
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"testing"
@@ -71,6 +73,49 @@ func TestLookup_HTTPRequest(t *testing.T) {
 	}
 }
 
+func TestLookup_HTTPRequestConditional(t *testing.T) {
+	// GIVEN a Lookup of a URL that supports conditional requests
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"version":"1.2.3"}`))
+	}))
+	defer server.Close()
+	lookup := testLookup()
+	lookup.URL = server.URL
+
+	// WHEN httpRequest is called on it twice
+	first, err := lookup.httpRequest(&util.LogFrom{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := lookup.httpRequest(&util.LogFrom{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// THEN the second request is conditional and reuses the cached body
+	if requests != 2 {
+		t.Errorf("want 2 requests, got %d",
+			requests)
+	}
+	if string(first) != `{"version":"1.2.3"}` || string(second) != string(first) {
+		t.Errorf("want the cached body on a 304\nfirst:  %q\nsecond: %q",
+			string(first), string(second))
+	}
+	// AND the ETag is cached against the URL
+	cache := lookup.Status.DeployedVersionCache()
+	if cache.URL != server.URL || cache.ETag != `"v1"` {
+		t.Errorf("unexpected cache: %+v",
+			cache)
+	}
+}
+
 func TestLookup_Query(t *testing.T) {
 	// GIVEN a Lookup()
 	tests := map[string]struct {
@@ -69,6 +69,7 @@ func (l *Lookup) applyOverrides(
 		useSemanticVersioning,
 		l.Options.Defaults,
 		l.Options.HardDefaults)
+	options.VersionScheme = l.Options.VersionScheme
 
 	// Create a new lookup with the overrides.
 	lookup := New(
@@ -90,6 +91,8 @@ func (l *Lookup) applyOverrides(
 		0, 0, 0,
 		serviceID,
 		nil)
+	// Use the cached body of the URL (if unchanged, the new json/regex are applied without a refetch)
+	lookup.Status.SetDeployedVersionCache(l.Status.DeployedVersionCache(), false)
 	return lookup, nil
 }
 
@@ -49,7 +49,7 @@ type Lookup struct {
 	LookupBase `yaml:",inline" json:",inline"`
 	BasicAuth  *BasicAuth `yaml:"basic_auth,omitempty" json:"basic_auth,omitempty"` // Basic Auth for the HTTP(S) request.
 	Headers    []Header   `yaml:"headers,omitempty" json:"headers,omitempty"`       // Headers for the HTTP(S) request.
-	JSON       string     `yaml:"json,omitempty" json:"json,omitempty"`             // JSON key to use e.g. version_current.
+	JSON       string     `yaml:"json,omitempty" json:"json,omitempty"`             // JSON path to use e.g. version_current / versions[0].version.
 	Regex      string     `yaml:"regex,omitempty" json:"regex,omitempty"`           // Regex to get the DeployedVersion
 
 	Options *opt.Options      `yaml:"-" json:"-"` // Options for the lookups
@@ -33,6 +33,14 @@ func (l *Lookup) CheckValues(prefix string) (errs error) {
 			util.ErrorToString(errs), prefix)
 	}
 
+	// JSON
+	if l.JSON != "" {
+		if err := util.CheckJSONPath(l.JSON); err != nil {
+			errs = fmt.Errorf("%s%s  json: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, l.JSON, err)
+		}
+	}
+
 	// RegEx
 	_, err := regexp.Compile(l.Regex)
 	if err != nil {
@@ -27,6 +27,7 @@ func TestLookup_CheckValues(t *testing.T) {
 	// GIVEN a Lookup
 	tests := map[string]struct {
 		url        string
+		json       string
 		regex      string
 		defaults   *LookupDefaults
 		errRegex   string
@@ -52,6 +53,12 @@ func TestLookup_CheckValues(t *testing.T) {
 			regex:    "[0-",
 			defaults: &LookupDefaults{},
 		},
+		"invalid json": {
+			errRegex: `json: "foo\[bar\]" <invalid>`,
+			url:      "https://example.com",
+			json:     "foo[bar]",
+			defaults: &LookupDefaults{},
+		},
 		"all errs": {
 			errRegex: `url: <required>`,
 			url:      "",
@@ -73,6 +80,7 @@ func TestLookup_CheckValues(t *testing.T) {
 			lookup := &Lookup{}
 			lookup = testLookup()
 			lookup.URL = tc.url
+			lookup.JSON = tc.json
 			lookup.Regex = tc.regex
 			lookup.Defaults = nil
 			if tc.defaults != nil {
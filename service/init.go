@@ -105,6 +105,8 @@ func (s *Service) ServiceInfo() *util.ServiceInfo {
 		URL:           s.LatestVersion.ServiceURL(true),
 		WebURL:        s.Status.GetWebURL(),
 		LatestVersion: s.Status.LatestVersion(),
+		Asset:         s.Status.LatestVersionAsset(),
+		Release:       s.Status.LatestVersionRelease(),
 	}
 }
 
@@ -0,0 +1,62 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// ContainerTagList is the format of the tags of an image on a registry at /v2/IMAGE/tags/list.
+type ContainerTagList struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// ContainerErrors is the format of an error response from a registry.
+type ContainerErrors struct {
+	Errors []struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// ContainerManifest is the format of a manifest, manifest list or OCI image index at /v2/IMAGE/manifests/REFERENCE.
+type ContainerManifest struct {
+	MediaType string `json:"mediaType"`
+	// Manifests of a manifest list/image index
+	Manifests []struct {
+		MediaType string             `json:"mediaType"`
+		Digest    string             `json:"digest"`
+		Platform  *ContainerPlatform `json:"platform,omitempty"`
+	} `json:"manifests,omitempty"`
+	// Config of a single-platform manifest
+	Config *struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+	} `json:"config,omitempty"`
+}
+
+// ContainerPlatform is the platform of a manifest in a manifest list/image index,
+// or of the config blob of a single-platform manifest.
+type ContainerPlatform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+// String returns the platform in the os/arch[/variant] format.
+func (p *ContainerPlatform) String() (str string) {
+	str = p.OS + "/" + p.Architecture
+	if p.Variant != "" {
+		str += "/" + p.Variant
+	}
+	return
+}
@@ -0,0 +1,102 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import (
+	"encoding/xml"
+	"path"
+	"strings"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// Feed is the format of an RSS 2.0 or Atom feed.
+type Feed struct {
+	XMLName xml.Name
+	Items   []FeedItem `xml:"channel>item"` // RSS
+	Entries []FeedItem `xml:"entry"`        // Atom
+}
+
+// FeedItem is the format of an RSS item or Atom entry.
+type FeedItem struct {
+	Title      string         `xml:"title"`
+	Links      []FeedLink     `xml:"link"`
+	GUID       string         `xml:"guid"` // RSS
+	ID         string         `xml:"id"`   // Atom
+	Categories []FeedCategory `xml:"category"`
+	Enclosures []FeedLink     `xml:"enclosure"` // RSS
+}
+
+// FeedLink is the format of a link of a FeedItem.
+//
+// RSS links are the text of the element, Atom links are in the href attribute.
+type FeedLink struct {
+	Text string `xml:",chardata"`
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	URL  string `xml:"url,attr"` // RSS enclosure
+}
+
+// FeedCategory is the format of a category of a FeedItem.
+//
+// RSS categories are the text of the element, Atom categories are in the term attribute.
+type FeedCategory struct {
+	Text string `xml:",chardata"`
+	Term string `xml:"term,attr"`
+}
+
+// Release converts the FeedItem to a Release with the text of `element` as the TagName
+// and the enclosures as the Assets.
+func (i *FeedItem) Release(element string) (release Release) {
+	release.TagName = i.element(element)
+
+	// RSS
+	for _, enclosure := range i.Enclosures {
+		release.Assets = append(release.Assets, Asset{
+			Name:               path.Base(enclosure.URL),
+			BrowserDownloadURL: enclosure.URL})
+	}
+	// Atom
+	for _, link := range i.Links {
+		if link.Rel == "enclosure" {
+			release.Assets = append(release.Assets, Asset{
+				Name:               path.Base(link.Href),
+				BrowserDownloadURL: link.Href})
+		}
+	}
+	return
+}
+
+// element returns the text of the `element` (category/guid/link/title) of the FeedItem.
+func (i *FeedItem) element(element string) (text string) {
+	switch element {
+	case "category":
+		if len(i.Categories) != 0 {
+			text = util.FirstNonDefault(i.Categories[0].Term, i.Categories[0].Text)
+		}
+	case "guid":
+		text = util.FirstNonDefault(i.GUID, i.ID)
+	case "link":
+		for _, link := range i.Links {
+			if link.Rel == "" || link.Rel == "alternate" {
+				text = util.FirstNonDefault(link.Href, link.Text)
+				break
+			}
+		}
+	default:
+		text = i.Title
+	}
+	return strings.TrimSpace(text)
+}
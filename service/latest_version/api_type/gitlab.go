@@ -0,0 +1,70 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// GitLabRelease is the format of a Release on GITLAB/api/v4/projects/ID/releases.
+type GitLabRelease struct {
+	TagName         string             `json:"tag_name,omitempty"`
+	UpcomingRelease bool               `json:"upcoming_release,omitempty"`
+	Assets          GitLabReleaseAsset `json:"assets,omitempty"`
+	Links           GitLabReleaseLinks `json:"_links,omitempty"`
+}
+
+// GitLabReleaseAsset is the format of the Assets of a GitLabRelease.
+type GitLabReleaseAsset struct {
+	Links []GitLabAssetLink `json:"links,omitempty"`
+}
+
+// GitLabAssetLink is the format of an Asset link on a GitLabRelease.
+type GitLabAssetLink struct {
+	ID             uint   `json:"id"`
+	Name           string `json:"name,omitempty"`
+	URL            string `json:"url,omitempty"`
+	DirectAssetURL string `json:"direct_asset_url,omitempty"`
+}
+
+// GitLabReleaseLinks is the format of the _links of a GitLabRelease.
+type GitLabReleaseLinks struct {
+	Self string `json:"self,omitempty"`
+}
+
+// GitLabTag is the format of a Tag on GITLAB/api/v4/projects/ID/repository/tags.
+type GitLabTag struct {
+	Name string `json:"name,omitempty"`
+}
+
+// Release converts the GitLabRelease to a Release.
+func (r *GitLabRelease) Release() (release Release) {
+	release = Release{
+		URL:        r.Links.Self,
+		TagName:    r.TagName,
+		PreRelease: r.UpcomingRelease}
+
+	if len(r.Assets.Links) != 0 {
+		release.Assets = make([]Asset, len(r.Assets.Links))
+		for i, link := range r.Assets.Links {
+			downloadURL := link.DirectAssetURL
+			if downloadURL == "" {
+				downloadURL = link.URL
+			}
+			release.Assets[i] = Asset{
+				ID:                 link.ID,
+				Name:               link.Name,
+				URL:                link.URL,
+				BrowserDownloadURL: downloadURL}
+		}
+	}
+	return
+}
@@ -0,0 +1,29 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// HelmIndex is the format of a Helm chart repository's index.yaml.
+type HelmIndex struct {
+	Entries map[string][]HelmChartVersion `yaml:"entries"`
+}
+
+// HelmChartVersion is the format of a Chart version in a HelmIndex.
+type HelmChartVersion struct {
+	Name       string   `yaml:"name"`
+	Version    string   `yaml:"version"`
+	AppVersion string   `yaml:"appVersion"`
+	Deprecated bool     `yaml:"deprecated"`
+	URLs       []string `yaml:"urls"`
+}
@@ -0,0 +1,57 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+// PyPIPackage is the format of a Package on pypi.org/pypi/PACKAGE/json.
+type PyPIPackage struct {
+	Releases map[string][]PyPIFile `json:"releases"`
+}
+
+// PyPIFile is the format of a File of a PyPIPackage release.
+type PyPIFile struct {
+	Filename   string `json:"filename"`
+	URL        string `json:"url"`
+	Yanked     bool   `json:"yanked"`
+	UploadTime string `json:"upload_time_iso_8601"`
+}
+
+// NPMPackage is the format of a Package on registry.npmjs.org/PACKAGE.
+type NPMPackage struct {
+	DistTags map[string]string     `json:"dist-tags"`
+	Versions map[string]NPMVersion `json:"versions"`
+	Time     map[string]string     `json:"time"` // Publish time of each version
+}
+
+// NPMVersion is the format of a Version of an NPMPackage.
+type NPMVersion struct {
+	Version    string  `json:"version"`
+	Deprecated *string `json:"deprecated,omitempty"`
+	Dist       struct {
+		Tarball string `json:"tarball"`
+	} `json:"dist"`
+}
+
+// CratesCrate is the format of a Crate on crates.io/api/v1/crates/CRATE.
+type CratesCrate struct {
+	Versions []CratesVersion `json:"versions"`
+}
+
+// CratesVersion is the format of a Version of a CratesCrate.
+type CratesVersion struct {
+	ID     uint   `json:"id"`
+	Num    string `json:"num"`
+	Yanked bool   `json:"yanked"`
+	DLPath string `json:"dl_path"`
+}
@@ -15,18 +15,24 @@
 package types
 
 import (
-	"github.com/coreos/go-semver/semver"
+	opt "github.com/release-argus/Argus/service/options"
 	"github.com/release-argus/Argus/util"
 )
 
 // Release is the format of a Release on api.github.com/repos/OWNER/REPO/releases.
 type Release struct {
-	URL             string          `json:"url,omitempty"`
-	AssetsURL       string          `json:"assets_url,omitempty"`
-	SemanticVersion *semver.Version `json:"-"`
-	TagName         string          `json:"tag_name,omitempty"`
-	PreRelease      bool            `json:"prerelease,omitempty"`
-	Assets          []Asset         `json:"assets,omitempty"`
+	URL             string      `json:"url,omitempty"`
+	HTMLURL         string      `json:"html_url,omitempty"`
+	AssetsURL       string      `json:"assets_url,omitempty"`
+	SemanticVersion opt.Version `json:"-"`
+	TagName         string      `json:"tag_name,omitempty"`
+	Name            string      `json:"name,omitempty"`
+	Body            string      `json:"body,omitempty"`
+	Draft           bool        `json:"draft,omitempty"`
+	PreRelease      bool        `json:"prerelease,omitempty"`
+	PublishedAt     string      `json:"published_at,omitempty"`
+	Author          *User       `json:"author,omitempty"`
+	Assets          []Asset     `json:"assets,omitempty"`
 }
 
 // String returns a string representation of the Release.
@@ -37,12 +43,19 @@ func (r *Release) String() (str string) {
 	return
 }
 
+// User is the format of a User on api.github.com/repos/OWNER/REPO/releases.
+type User struct {
+	Login   string `json:"login,omitempty"`
+	HTMLURL string `json:"html_url,omitempty"`
+}
+
 // Asset is the format of an Asset on api.github.com/repos/OWNER/REPO/releases.
 type Asset struct {
 	ID                 uint   `json:"id"`
 	Name               string `json:"name,omitempty"`
 	URL                string `json:"url,omitempty"`
 	BrowserDownloadURL string `json:"browser_download_url,omitempty"`
+	Size               uint64 `json:"size,omitempty"`
 }
 
 // String returns a string representation of the Asset.
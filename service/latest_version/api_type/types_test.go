@@ -19,10 +19,11 @@ package types
 import (
 	"testing"
 
-	"github.com/coreos/go-semver/semver"
+	opt "github.com/release-argus/Argus/service/options"
 )
 
 func TestRelease_String(t *testing.T) {
+	semVer, _ := opt.ParseVersion("semver", "1.2.3")
 	tests := map[string]struct {
 		release *Release
 		want    string
@@ -38,11 +39,21 @@ func TestRelease_String(t *testing.T) {
 				{ID: 1, Name: "test", URL: "https://test.com", BrowserDownloadURL: "https://test.com/download"},
 				{ID: 2, Name: "test2"}}},
 			want: `{"assets":[{"id":1,"name":"test","url":"https://test.com","browser_download_url":"https://test.com/download"},{"id":2,"name":"test2"}]}`},
+		"release metadata": {
+			release: &Release{
+				HTMLURL:     "https://github.com/release-argus/Argus/releases/tag/v1.2.3",
+				TagName:     "v1.2.3",
+				Name:        "v1.2.3",
+				Body:        "- Fixed a bug",
+				Draft:       true,
+				PublishedAt: "2023-01-01T00:00:00Z",
+				Author:      &User{Login: "someone", HTMLURL: "https://github.com/someone"}},
+			want: `{"html_url":"https://github.com/release-argus/Argus/releases/tag/v1.2.3","tag_name":"v1.2.3","name":"v1.2.3","body":"- Fixed a bug","draft":true,"published_at":"2023-01-01T00:00:00Z","author":{"login":"someone","html_url":"https://github.com/someone"}}`},
 		"all fields defined": {
 			release: &Release{
 				URL:             "https://test.com",
 				AssetsURL:       "https://test.com/assets",
-				SemanticVersion: &semver.Version{Major: 1, Minor: 2, Patch: 3},
+				SemanticVersion: semVer,
 				TagName:         "v1.2.3",
 				PreRelease:      true,
 				Assets: []Asset{
@@ -0,0 +1,185 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	net_url "net/url"
+	"sort"
+	"strings"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/util"
+)
+
+// containerTagsMaxPages is the most pages of tags that will be requested for an image.
+const containerTagsMaxPages = 100
+
+// containerTags will list every tag of the image with the registry v2 API (following the pagination),
+// and return them as a single tags/list body.
+func (l *Lookup) containerTags(client *http.Client, logFrom *util.LogFrom) (rawBody []byte, err error) {
+	if l.registryAuth == nil {
+		var defaults *filter.DockerCheckDefaults
+		if l.Defaults != nil {
+			defaults = &l.Defaults.Require.Docker
+		}
+		registry, _ := net_url.Parse(l.GetBaseURL())
+		l.registryAuth = filter.NewRegistryAuth(
+			registry.Host,
+			l.Username,
+			// The defaults are GitHub tokens, so only use the one on this Lookup
+			util.DefaultIfNil(l.AccessToken),
+			defaults)
+	}
+
+	tagList := github_types.ContainerTagList{Name: l.URL}
+	url := l.GetQueryURL()
+	for page := 0; url != "" && page < containerTagsMaxPages; page++ {
+		var req *http.Request
+		req, err = http.NewRequestWithContext(l.Status.Context(), http.MethodGet, url, nil)
+		if err != nil {
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+		req.Header.Set("Connection", "close")
+
+		var resp *http.Response
+		resp, err = l.registryAuth.Do(client, req)
+		if err != nil {
+			// Don't crash on invalid certs.
+			if strings.Contains(err.Error(), "x509") {
+				err = fmt.Errorf("x509 (certificate invalid)")
+				jLog.Warn(err, *logFrom, true)
+				return
+			}
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+
+		// Read the response body.
+		var body []byte
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("container query for %q failed: %s%s",
+				l.URL, resp.Status, containerErrorMessage(body))
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+
+		var pageTags github_types.ContainerTagList
+		if err = json.Unmarshal(body, &pageTags); err != nil {
+			err = fmt.Errorf("unmarshal of container registry data failed\n%w",
+				err)
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+		tagList.Tags = append(tagList.Tags, pageTags.Tags...)
+
+		url = nextLink(resp.Header.Get("Link"), req.URL)
+	}
+
+	rawBody, err = json.Marshal(tagList)
+	jLog.Error(err, *logFrom, err != nil)
+	return
+}
+
+// checkContainerTagsBody will check that the body is a tags/list of an image
+// and convert the tags to Releases.
+//
+// Registries list the tags in lexical order, so the tags are reversed to get the newer versions first.
+// Tags aren't considered pre-releases as the suffix is often a variant (e.g. 1.2.3-alpine),
+// so use url_commands to filter the tags wanted.
+func (l *Lookup) checkContainerTagsBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	var tagList github_types.ContainerTagList
+	if err = json.Unmarshal(*body, &tagList); err != nil {
+		err = fmt.Errorf("unmarshal of container registry data failed\n%w",
+			err)
+		jLog.Error(err, *logFrom, true)
+		return
+	}
+
+	tags := tagList.Tags
+	sort.Sort(sort.Reverse(sort.StringSlice(tags)))
+	releases = make([]github_types.Release, len(tags))
+	for i := range tags {
+		releases[i] = github_types.Release{TagName: tags[i]}
+	}
+	return
+}
+
+// containerImage will split an image reference into the registry URL and repository,
+// e.g. "ghcr.io/release-argus/argus" -> "https://ghcr.io", "release-argus/argus",
+// and "prometheus" -> "", "prometheus" (Docker Hub).
+func containerImage(image string) (registryURL string, repository string) {
+	scheme := "https"
+	if before, after, found := strings.Cut(image, "://"); found {
+		scheme = before
+		image = after
+	}
+
+	repository = image
+	if host, path, found := strings.Cut(image, "/"); found &&
+		(strings.ContainsAny(host, ".:") || host == "localhost") {
+		if filter.RegistryType(host) != "hub" {
+			registryURL = fmt.Sprintf("%s://%s", scheme, host)
+		}
+		repository = path
+	}
+	return
+}
+
+// containerErrorMessage returns the messages of a registry error body, e.g.
+// {"errors":[{"code":"NAME_UNKNOWN","message":"repository name not known to registry"}]}.
+func containerErrorMessage(body []byte) (message string) {
+	var registryErrors github_types.ContainerErrors
+	if json.Unmarshal(body, &registryErrors) != nil {
+		return
+	}
+
+	for _, registryError := range registryErrors.Errors {
+		message += fmt.Sprintf(" - %s: %s",
+			registryError.Code, registryError.Message)
+	}
+	return
+}
+
+// nextLink returns the URL of the `rel="next"` Link header, resolved against `from`.
+//
+// e.g. `</v2/owner/image/tags/list?last=1.2.3&n=100>; rel="next"`
+func nextLink(header string, from *net_url.URL) string {
+	for _, link := range strings.Split(header, ",") {
+		target, params, _ := strings.Cut(link, ";")
+		if !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+			continue
+		}
+
+		target = strings.Trim(strings.TrimSpace(target), "<>")
+		next, err := from.Parse(target)
+		if err != nil {
+			return ""
+		}
+		return next.String()
+	}
+	return ""
+}
@@ -0,0 +1,270 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestLookup_CheckContainerTagsBody(t *testing.T) {
+	// GIVEN a tags/list body
+	tests := map[string]struct {
+		body     string
+		want     []string
+		errRegex string
+	}{
+		"tags reversed": {
+			body:     `{"name":"owner/image","tags":["1.0.0","1.1.0","latest"]}`,
+			want:     []string{"latest", "1.1.0", "1.0.0"},
+			errRegex: `^$`},
+		"no tags": {
+			body:     `{"name":"owner/image","tags":null}`,
+			want:     []string{},
+			errRegex: `^$`},
+		"invalid json": {
+			body:     `bish bash bosh`,
+			errRegex: `unmarshal of container registry data failed`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			body := []byte(tc.body)
+			lookup := Lookup{
+				Type: "container",
+				URL:  "owner/image"}
+
+			// WHEN checkContainerTagsBody is called on this body
+			releases, err := lookup.checkContainerTagsBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the tags are converted to releases
+			if len(releases) != len(tc.want) {
+				t.Fatalf("want %d releases, got %d\n%v",
+					len(tc.want), len(releases), releases)
+			}
+			for i := range tc.want {
+				if releases[i].TagName != tc.want[i] {
+					t.Errorf("release %d - want %q, got %q",
+						i, tc.want[i], releases[i].TagName)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryContainer(t *testing.T) {
+	// GIVEN a container Lookup and a registry that requires a Bearer token
+	tests := map[string]struct {
+		image       string
+		username    string
+		accessToken string
+		wantVersion string
+		errRegex    string
+	}{
+		"anonymous, tags over multiple pages": {
+			image:       "owner/image",
+			wantVersion: "1.10.0",
+			errRegex:    `^$`},
+		"username/password": {
+			image:       "owner/private",
+			username:    "user",
+			accessToken: "pass",
+			wantVersion: "2.0.0",
+			errRegex:    `^$`},
+		"static token": {
+			image:       "owner/private",
+			accessToken: "static",
+			wantVersion: "2.0.0",
+			errRegex:    `^$`},
+		"no access to private image": {
+			image:    "owner/private",
+			errRegex: `container query for "owner/private" failed: 401 Unauthorized - UNAUTHORIZED: authentication required`},
+		"unknown image": {
+			image:    "owner/unknown",
+			errRegex: `container query for "owner/unknown" failed: 404 Not Found - NAME_UNKNOWN`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Token realm
+				if r.URL.Path == "/token" {
+					token := "anonymous"
+					if username, password, ok := r.BasicAuth(); ok && username == "user" && password == "pass" {
+						token = "user"
+					}
+					fmt.Fprintf(w, `{"token":%q}`, token)
+					return
+				}
+
+				auth := r.Header.Get("Authorization")
+				if auth == "" {
+					w.Header().Set("WWW-Authenticate",
+						fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:owner/image:pull"`, server.URL))
+					w.WriteHeader(http.StatusUnauthorized)
+					w.Write([]byte(`{"errors":[{"code":"UNAUTHORIZED","message":"authentication required"}]}`))
+					return
+				}
+				switch r.URL.Path {
+				case "/v2/owner/image/tags/list":
+					if r.URL.Query().Get("last") == "" {
+						w.Header().Set("Link", `</v2/owner/image/tags/list?last=1.1.0&n=2>; rel="next"`)
+						w.Write([]byte(`{"name":"owner/image","tags":["1.0.0","1.1.0"]}`))
+						return
+					}
+					w.Write([]byte(`{"name":"owner/image","tags":["1.10.0","1.9.0-rc.1","latest"]}`))
+				case "/v2/owner/private/tags/list":
+					if auth != "Bearer user" && auth != "Bearer static" {
+						w.WriteHeader(http.StatusUnauthorized)
+						w.Write([]byte(`{"errors":[{"code":"UNAUTHORIZED","message":"authentication required"}]}`))
+						return
+					}
+					w.Write([]byte(`{"name":"owner/private","tags":["1.0.0","2.0.0"]}`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"errors":[{"code":"NAME_UNKNOWN","message":"repository name not known to registry"}]}`))
+				}
+			}))
+			defer server.Close()
+			lookup := testLookup(false, false)
+			lookup.Type = "container"
+			lookup.URL = tc.image
+			lookup.BaseURL = server.URL
+			lookup.Username = tc.username
+			lookup.AccessToken = &tc.accessToken
+			lookup.URLCommands = nil
+			if err := lookup.CheckValues(""); err != nil {
+				t.Fatalf("CheckValues failed: %v", err)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the newest semantic version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantVersion {
+				t.Errorf("want version %q, got %q",
+					tc.wantVersion, got)
+			}
+		})
+	}
+}
+
+func TestContainerImage(t *testing.T) {
+	// GIVEN an image reference
+	tests := map[string]struct {
+		image          string
+		wantRegistry   string
+		wantRepository string
+	}{
+		"docker hub": {
+			image:          "prometheus/prometheus",
+			wantRegistry:   "",
+			wantRepository: "prometheus/prometheus"},
+		"docker hub with host": {
+			image:          "docker.io/prometheus/prometheus",
+			wantRegistry:   "",
+			wantRepository: "prometheus/prometheus"},
+		"ghcr": {
+			image:          "ghcr.io/release-argus/argus",
+			wantRegistry:   "https://ghcr.io",
+			wantRepository: "release-argus/argus"},
+		"registry with port and scheme": {
+			image:          "http://localhost:5000/team/app",
+			wantRegistry:   "http://localhost:5000",
+			wantRepository: "team/app"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN containerImage is called on it
+			registry, repository := containerImage(tc.image)
+
+			// THEN the registry and repository are split
+			if registry != tc.wantRegistry || repository != tc.wantRepository {
+				t.Errorf("want: %q, %q\ngot:  %q, %q",
+					tc.wantRegistry, tc.wantRepository, registry, repository)
+			}
+		})
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	// GIVEN a Link header
+	from, _ := url.Parse("https://registry.example.com/v2/owner/image/tags/list?n=100")
+	tests := map[string]struct {
+		header string
+		want   string
+	}{
+		"no header": {
+			header: "",
+			want:   ""},
+		"relative next": {
+			header: `</v2/owner/image/tags/list?last=1.2.3&n=100>; rel="next"`,
+			want:   "https://registry.example.com/v2/owner/image/tags/list?last=1.2.3&n=100"},
+		"absolute next after prev": {
+			header: `<https://other.example.com/prev>; rel="prev", <https://other.example.com/next>; rel="next"`,
+			want:   "https://other.example.com/next"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN nextLink is called on it
+			got := nextLink(tc.header, from)
+
+			// THEN the next URL is returned
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// feedElements are the elements of a feed item that the version can be taken from.
+var feedElements = []string{
+	"category", "guid", "link", "title"}
+
+// checkFeedBody will check that the body is an RSS/Atom feed
+// and convert each item/entry to a Release with the TagName of the feed_element.
+func (l *Lookup) checkFeedBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	var feed github_types.Feed
+	if err = xml.Unmarshal(*body, &feed); err != nil {
+		err = fmt.Errorf("unmarshal of feed failed\n%w",
+			err)
+		jLog.Error(err, *logFrom, true)
+		return
+	}
+
+	items := feed.Items
+	if feed.XMLName.Local == "feed" {
+		items = feed.Entries
+	}
+	element := l.GetFeedElement()
+	releases = make([]github_types.Release, len(items))
+	for i := range items {
+		releases[i] = items[i].Release(element)
+	}
+	return
+}
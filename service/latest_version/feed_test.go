@@ -0,0 +1,227 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/util"
+)
+
+var (
+	testFeedRSS = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Example releases</title>
+    <item>
+      <title>Example 1.3.0-beta released</title>
+      <link>https://example.com/releases/1.3.0-beta</link>
+      <guid>release-1.3.0-beta</guid>
+      <category>beta</category>
+    </item>
+    <item>
+      <title>Example 1.2.0 released</title>
+      <link>https://example.com/releases/1.2.0</link>
+      <guid>release-1.2.0</guid>
+      <category>stable</category>
+      <enclosure url="https://example.com/download/example-1.2.0.tar.gz" type="application/gzip"/>
+    </item>
+  </channel>
+</rss>`
+	testFeedAtom = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Example releases</title>
+  <entry>
+    <title>Example 2.1.0</title>
+    <link rel="alternate" href="https://example.com/releases/v2.1.0"/>
+    <link rel="enclosure" href="https://example.com/download/example-2.1.0.zip"/>
+    <id>tag:example.com,2023:v2.1.0</id>
+    <category term="v2.1.0"/>
+  </entry>
+  <entry>
+    <title>Example 2.0.0</title>
+    <link href="https://example.com/releases/v2.0.0"/>
+    <id>tag:example.com,2023:v2.0.0</id>
+  </entry>
+</feed>`
+)
+
+func TestLookup_CheckFeedBody(t *testing.T) {
+	// GIVEN a feed
+	tests := map[string]struct {
+		body       string
+		element    string
+		want       []string
+		wantAssets []int
+		errRegex   string
+	}{
+		"rss - title": {
+			body:       testFeedRSS,
+			want:       []string{"Example 1.3.0-beta released", "Example 1.2.0 released"},
+			wantAssets: []int{0, 1},
+			errRegex:   `^$`},
+		"rss - link": {
+			body:       testFeedRSS,
+			element:    "link",
+			want:       []string{"https://example.com/releases/1.3.0-beta", "https://example.com/releases/1.2.0"},
+			wantAssets: []int{0, 1},
+			errRegex:   `^$`},
+		"rss - guid": {
+			body:       testFeedRSS,
+			element:    "guid",
+			want:       []string{"release-1.3.0-beta", "release-1.2.0"},
+			wantAssets: []int{0, 1},
+			errRegex:   `^$`},
+		"rss - category": {
+			body:       testFeedRSS,
+			element:    "category",
+			want:       []string{"beta", "stable"},
+			wantAssets: []int{0, 1},
+			errRegex:   `^$`},
+		"atom - link": {
+			body:       testFeedAtom,
+			element:    "link",
+			want:       []string{"https://example.com/releases/v2.1.0", "https://example.com/releases/v2.0.0"},
+			wantAssets: []int{1, 0},
+			errRegex:   `^$`},
+		"atom - guid is the id": {
+			body:       testFeedAtom,
+			element:    "guid",
+			want:       []string{"tag:example.com,2023:v2.1.0", "tag:example.com,2023:v2.0.0"},
+			wantAssets: []int{1, 0},
+			errRegex:   `^$`},
+		"atom - category term": {
+			body:       testFeedAtom,
+			element:    "category",
+			want:       []string{"v2.1.0", ""},
+			wantAssets: []int{1, 0},
+			errRegex:   `^$`},
+		"invalid xml": {
+			body:     `{"not":"xml"}`,
+			errRegex: `unmarshal of feed failed`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			body := []byte(tc.body)
+			lookup := Lookup{
+				Type:        "feed",
+				URL:         "https://example.com/feed",
+				FeedElement: tc.element}
+
+			// WHEN checkFeedBody is called on this body
+			releases, err := lookup.checkFeedBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND each item is converted to a release
+			if len(releases) != len(tc.want) {
+				t.Fatalf("want %d releases, got %d\n%v",
+					len(tc.want), len(releases), releases)
+			}
+			for i := range tc.want {
+				if releases[i].TagName != tc.want[i] {
+					t.Errorf("release %d - want %q, got %q",
+						i, tc.want[i], releases[i].TagName)
+				}
+				if len(releases[i].Assets) != tc.wantAssets[i] {
+					t.Errorf("release %d - want %d assets, got %d",
+						i, tc.wantAssets[i], len(releases[i].Assets))
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryFeed(t *testing.T) {
+	// GIVEN a feed Lookup and a feed
+	tests := map[string]struct {
+		feed          string
+		element       string
+		regex         string
+		usePreRelease bool
+		wantVersion   string
+		errRegex      string
+	}{
+		"url_commands run on each item": {
+			feed:        testFeedRSS,
+			regex:       `Example ([0-9.]+) released`,
+			wantVersion: "1.2.0",
+			errRegex:    `^$`},
+		"atom link": {
+			feed:        testFeedAtom,
+			element:     "link",
+			regex:       `v([0-9.]+)$`,
+			wantVersion: "2.1.0",
+			errRegex:    `^$`},
+		"no item matches": {
+			feed:     testFeedRSS,
+			regex:    `Argus ([0-9.]+)`,
+			errRegex: `no releases were found matching the url_commands`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte(tc.feed))
+			}))
+			defer server.Close()
+			lookup := testLookup(false, false)
+			lookup.Type = "feed"
+			lookup.URL = server.URL
+			lookup.FeedElement = tc.element
+			lookup.URLCommands = filter.URLCommandSlice{
+				{Type: "regex", Regex: &tc.regex}}
+			if err := lookup.CheckValues(""); err != nil {
+				t.Fatalf("CheckValues failed: %v", err)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the newest version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantVersion {
+				t.Errorf("want version %q, got %q",
+					tc.wantVersion, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// sizeUnits are the multipliers of the units allowed in a `min_size`.
+var sizeUnits = map[string]uint64{
+	"":    1,
+	"b":   1,
+	"kb":  1000,
+	"mb":  1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30}
+
+// sizeRegex matches a size, e.g. "512", "10MB", "1.5 GiB".
+var sizeRegex = regexp.MustCompile(`^\s*([0-9]+(?:\.[0-9]+)?)\s*([A-Za-z]*)\s*$`)
+
+// AssetsRequire are the requirements of the assets of a release.
+type AssetsRequire struct {
+	Name     string `yaml:"name,omitempty" json:"name,omitempty"`           // "argus-{{ version }}.linux-amd64" RegEx that the asset names must match
+	MinCount int    `yaml:"min_count,omitempty" json:"min_count,omitempty"` // Minimum number of (matching) assets
+	MinSize  string `yaml:"min_size,omitempty" json:"min_size,omitempty"`   // "10MB" Minimum size of the (matching) assets
+}
+
+// String returns a string representation of the AssetsRequire.
+func (a *AssetsRequire) String() (str string) {
+	if a != nil {
+		str = util.ToYAMLString(a, "")
+	}
+	return
+}
+
+// CheckValues of the AssetsRequire.
+func (a *AssetsRequire) CheckValues(prefix string) (errs error) {
+	if a == nil {
+		return
+	}
+
+	// Name
+	if a.Name != "" {
+		if !util.CheckTemplate(a.Name) {
+			errs = fmt.Errorf("%s%s  name: %q <invalid> (didn't pass templating)\\",
+				util.ErrorToString(errs), prefix, a.Name)
+		} else if _, err := regexp.Compile(a.Name); err != nil {
+			errs = fmt.Errorf("%s%s  name: %q <invalid> (Invalid RegEx)\\",
+				util.ErrorToString(errs), prefix, a.Name)
+		}
+	}
+
+	// MinCount
+	if a.MinCount < 0 {
+		errs = fmt.Errorf("%s%s  min_count: %d <invalid> (must be at least 0)\\",
+			util.ErrorToString(errs), prefix, a.MinCount)
+	}
+
+	// MinSize
+	if a.MinSize != "" {
+		if _, err := parseSize(a.MinSize); err != nil {
+			errs = fmt.Errorf("%s%s  min_size: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, a.MinSize, err)
+		}
+	}
+
+	return
+}
+
+// GetMinSize returns the minimum size (in bytes) of the assets.
+func (a *AssetsRequire) GetMinSize() uint64 {
+	size, _ := parseSize(a.MinSize)
+	return size
+}
+
+// AssetsCheck returns the first asset of `assets` that satisfies the require.assets,
+// erroring if there are less than `min_count` (or 1) that do.
+func (r *Require) AssetsCheck(
+	version string,
+	assets []github_types.Asset,
+	logFrom *util.LogFrom,
+) (*github_types.Asset, error) {
+	if r == nil || r.Assets == nil {
+		return nil, nil
+	}
+
+	var nameRegex *regexp.Regexp
+	if r.Assets.Name != "" {
+		// The version may make the templated regex invalid (e.g. an unbalanced bracket).
+		regexStr := util.TemplateString(r.Assets.Name, util.ServiceInfo{LatestVersion: version})
+		var err error
+		if nameRegex, err = regexp.Compile(regexStr); err != nil {
+			err = fmt.Errorf("require.assets not met for version %q - name %q is an invalid regex: %w",
+				version, regexStr, err)
+			r.Status.RegexMissContent()
+			jLog.Info(err, *logFrom, r.Status.RegexMissesContent() == 1)
+			return nil, err
+		}
+	}
+	minSize := r.Assets.GetMinSize()
+
+	var matches []int
+	for i := range assets {
+		if nameRegex != nil && !nameRegex.MatchString(assets[i].Name) {
+			continue
+		}
+		if assets[i].Size < minSize {
+			continue
+		}
+		matches = append(matches, i)
+	}
+	if jLog.IsLevel("DEBUG") {
+		jLog.Debug(
+			fmt.Sprintf("%d/%d assets matched the require.assets for version %q",
+				len(matches), len(assets), version),
+			*logFrom, true)
+	}
+
+	minCount := r.Assets.MinCount
+	if minCount < 1 {
+		minCount = 1
+	}
+	if len(matches) < minCount {
+		err := fmt.Errorf("require.assets not met for version %q - %d/%d assets matched",
+			version, len(matches), minCount)
+		r.Status.RegexMissContent()
+		jLog.Info(err, *logFrom, r.Status.RegexMissesContent() == 1)
+		return nil, err
+	}
+
+	return &assets[matches[0]], nil
+}
+
+// parseSize returns the number of bytes in a size, e.g. "512", "10MB", "1.5GiB".
+func parseSize(size string) (uint64, error) {
+	match := sizeRegex.FindStringSubmatch(size)
+	if match == nil {
+		return 0, fmt.Errorf("use a number of bytes with an optional unit, e.g. '10MB'")
+	}
+	multiplier, ok := sizeUnits[strings.ToLower(match[2])]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q (supported units = [B,KB,MB,GB,KiB,MiB,GiB])", match[2])
+	}
+
+	number, _ := strconv.ParseFloat(match[1], 64)
+	return uint64(number * float64(multiplier)), nil
+}
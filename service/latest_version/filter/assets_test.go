@@ -0,0 +1,249 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	svcstatus "github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestAssetsRequire_CheckValues(t *testing.T) {
+	// GIVEN an AssetsRequire
+	tests := map[string]struct {
+		assets   *AssetsRequire
+		errRegex []string
+	}{
+		"nil": {
+			assets:   nil,
+			errRegex: []string{`^$`}},
+		"valid": {
+			assets: &AssetsRequire{
+				Name:     `argus-{{ version }}\.linux-amd64`,
+				MinCount: 2,
+				MinSize:  "1.5MiB"},
+			errRegex: []string{`^$`}},
+		"invalid name regex": {
+			assets: &AssetsRequire{
+				Name: "[0-"},
+			errRegex: []string{`^  name: "\[0-" <invalid> \(Invalid RegEx\)$`}},
+		"invalid name template": {
+			assets: &AssetsRequire{
+				Name: "{{ version }"},
+			errRegex: []string{`^  name: .* <invalid> \(didn't pass templating\)$`}},
+		"negative min_count": {
+			assets: &AssetsRequire{
+				MinCount: -1},
+			errRegex: []string{`^  min_count: -1 <invalid>`}},
+		"invalid min_size": {
+			assets: &AssetsRequire{
+				MinSize: "10 parsecs"},
+			errRegex: []string{`^  min_size: "10 parsecs" <invalid> \(unknown unit "parsecs"`}},
+		"all possible errors": {
+			assets: &AssetsRequire{
+				Name:     "[0-",
+				MinCount: -1,
+				MinSize:  "big"},
+			errRegex: []string{
+				`^  name: .* <invalid>`,
+				`^  min_count: .* <invalid>`,
+				`^  min_size: .* <invalid>`}},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called on it
+			err := tc.assets.CheckValues("")
+
+			// THEN err is expected
+			e := util.ErrorToString(err)
+			lines := strings.Split(e, `\`)
+			for i := range tc.errRegex {
+				re := regexp.MustCompile(tc.errRegex[i])
+				found := false
+				for j := range lines {
+					if re.MatchString(lines[j]) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("want match for: %q\ngot:  %q",
+						tc.errRegex[i], strings.ReplaceAll(e, `\`, "\n"))
+				}
+			}
+		})
+	}
+}
+
+func TestRequire_AssetsCheck(t *testing.T) {
+	// GIVEN a Require and the assets of a release
+	assets := []github_types.Asset{
+		{Name: "argus-1.2.3.darwin-amd64", BrowserDownloadURL: "https://example.com/argus-1.2.3.darwin-amd64", Size: 2048},
+		{Name: "argus-1.2.3.linux-amd64", BrowserDownloadURL: "https://example.com/argus-1.2.3.linux-amd64", Size: 512},
+		{Name: "argus-1.2.3.linux-arm64", BrowserDownloadURL: "https://example.com/argus-1.2.3.linux-arm64", Size: 4096},
+		{Name: "checksums.txt", Size: 64}}
+	tests := map[string]struct {
+		require   *Require
+		version   string
+		assets    []github_types.Asset
+		wantAsset string
+		errRegex  string
+	}{
+		"nil require": {
+			require:  nil,
+			errRegex: `^$`},
+		"no assets require": {
+			require:  &Require{},
+			errRegex: `^$`},
+		"any asset": {
+			require: &Require{
+				Assets: &AssetsRequire{MinCount: 1}},
+			wantAsset: "argus-1.2.3.darwin-amd64",
+			errRegex:  `^$`},
+		"no assets": {
+			require: &Require{
+				Assets: &AssetsRequire{Name: "linux"}},
+			assets:   []github_types.Asset{},
+			errRegex: `require.assets not met for version "1.2.3" - 0/1 assets matched$`},
+		"name match with the version templated": {
+			require: &Require{
+				Assets: &AssetsRequire{Name: `argus-{{ version }}\.linux-`}},
+			wantAsset: "argus-1.2.3.linux-amd64",
+			errRegex:  `^$`},
+		"name and min_size": {
+			require: &Require{
+				Assets: &AssetsRequire{Name: `linux`, MinSize: "1KB"}},
+			wantAsset: "argus-1.2.3.linux-arm64",
+			errRegex:  `^$`},
+		"min_count met": {
+			require: &Require{
+				Assets: &AssetsRequire{Name: `^argus-`, MinCount: 3}},
+			wantAsset: "argus-1.2.3.darwin-amd64",
+			errRegex:  `^$`},
+		"min_count not met": {
+			require: &Require{
+				Assets: &AssetsRequire{Name: `^argus-`, MinCount: 3, MinSize: "1KiB"}},
+			errRegex: `require.assets not met for version "1.2.3" - 2/3 assets matched$`},
+		"no name match": {
+			require: &Require{
+				Assets: &AssetsRequire{Name: `windows`}},
+			errRegex: `require.assets not met`},
+		"version makes the name an invalid regex": {
+			require: &Require{
+				Assets: &AssetsRequire{Name: `argus-{{ version }}\.linux-`}},
+			version:  "1.2.3[",
+			errRegex: `require.assets not met for version "1.2.3\[" - name .* is an invalid regex: .*missing closing \]`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.require != nil {
+				tc.require.Status = &svcstatus.Status{}
+			}
+			if tc.assets == nil {
+				tc.assets = assets
+			}
+			if tc.version == "" {
+				tc.version = "1.2.3"
+			}
+
+			// WHEN AssetsCheck is called on it
+			asset, err := tc.require.AssetsCheck(tc.version, tc.assets, &util.LogFrom{})
+
+			// THEN the err is what we expect
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the first matching asset is returned
+			got := ""
+			if asset != nil {
+				got = asset.Name
+			}
+			if got != tc.wantAsset {
+				t.Errorf("want asset %q, got %q",
+					tc.wantAsset, got)
+			}
+			// AND a miss is counted like a regex_content miss
+			if err != nil && tc.require.Status.RegexMissesContent() != 1 {
+				t.Errorf("want 1 content miss, got %d",
+					tc.require.Status.RegexMissesContent())
+			}
+		})
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	// GIVEN a size
+	tests := map[string]struct {
+		size     string
+		want     uint64
+		errRegex string
+	}{
+		"bytes": {
+			size: "512", want: 512, errRegex: `^$`},
+		"B": {
+			size: "512B", want: 512, errRegex: `^$`},
+		"MB": {
+			size: "10MB", want: 10 * 1000 * 1000, errRegex: `^$`},
+		"decimal GiB with a space": {
+			size: "1.5 GiB", want: 3 << 29, errRegex: `^$`},
+		"lowercase kib": {
+			size: "2kib", want: 2048, errRegex: `^$`},
+		"unknown unit": {
+			size: "2TB", errRegex: `unknown unit "TB"`},
+		"not a number": {
+			size: "lots", errRegex: `use a number of bytes`},
+		"negative": {
+			size: "-1", errRegex: `use a number of bytes`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN parseSize is called on it
+			got, err := parseSize(tc.size)
+
+			// THEN the size is parsed
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if got != tc.want {
+				t.Errorf("want %d, got %d",
+					tc.want, got)
+			}
+		})
+	}
+}
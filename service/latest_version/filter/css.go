@@ -0,0 +1,30 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+)
+
+// compileCSS compiles the CSS `selector` (a comma-separated group is allowed).
+func compileCSS(selector string) (cascadia.SelectorGroup, error) {
+	return cascadia.ParseGroup(selector)
+}
+
+// selectCSS returns the elements below `root` that match the `selector`, in document order.
+func selectCSS(root *html.Node, selector cascadia.SelectorGroup) []*html.Node {
+	return cascadia.QueryAll(root, selector)
+}
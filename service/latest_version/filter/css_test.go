@@ -0,0 +1,139 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/release-argus/Argus/util"
+)
+
+var testSelectHTML = `<html><body>
+	<div id="releases">
+		<div class="release latest" data-tag="v2.0.0-beta.1"><h2>v2.0.0-beta.1</h2><a href="/dl/v2.0.0-beta.1.tar.gz">source</a></div>
+		<div class="release" data-tag="v1.2.0"><h2>v1.2.0</h2><a href="/dl/v1.2.0.zip">source</a><a href="/dl/v1.2.0.tar.gz">source</a></div>
+		<div class="release" data-tag="v1.1.0"><h2>v1.1.0</h2></div>
+	</div>
+	<p class="footer">v0.0.1</p>
+</body></html>`
+
+func TestSelectCSS(t *testing.T) {
+	// GIVEN a CSS selector
+	tests := map[string]struct {
+		selector string
+		want     []string
+		errRegex string
+	}{
+		"tag": {
+			selector: "h2",
+			want:     []string{"v2.0.0-beta.1", "v1.2.0", "v1.1.0"}},
+		"class": {
+			selector: ".latest h2",
+			want:     []string{"v2.0.0-beta.1"}},
+		"id and child": {
+			selector: "#releases > div > h2",
+			want:     []string{"v2.0.0-beta.1", "v1.2.0", "v1.1.0"}},
+		"child doesn't match grandchildren": {
+			selector: "#releases > h2",
+			want:     nil},
+		"attribute suffix": {
+			selector: `a[href$=".tar.gz"]`,
+			want:     []string{"/dl/v2.0.0-beta.1.tar.gz", "/dl/v1.2.0.tar.gz"}},
+		"attribute contains": {
+			selector: `[data-tag*='beta'] > h2`,
+			want:     []string{"v2.0.0-beta.1"}},
+		"attribute exact and whitespace list": {
+			selector: `div[class~=release][data-tag="v1.1.0"]`,
+			want:     []string{"v1.1.0"}},
+		"nth-child": {
+			selector: `div.release:nth-child(2) h2`,
+			want:     []string{"v1.2.0"}},
+		"last-child": {
+			selector: `div.release:last-child h2`,
+			want:     []string{"v1.1.0"}},
+		"nth-last-child": {
+			selector: `div.release:nth-last-child(3) > h2`,
+			want:     []string{"v2.0.0-beta.1"}},
+		"next sibling": {
+			selector: `h2 + a`,
+			want:     []string{"/dl/v2.0.0-beta.1.tar.gz", "/dl/v1.2.0.zip"}},
+		"subsequent sibling": {
+			selector: `div ~ p`,
+			want:     []string{"v0.0.1"}},
+		"group in document order": {
+			selector: `p.footer, .latest h2`,
+			want:     []string{"v2.0.0-beta.1", "v0.0.1"}},
+		"unknown pseudo-class": {
+			selector: `div:unknown-pseudo`,
+			errRegex: `.+`},
+		"unclosed attribute": {
+			selector: `a[href`,
+			errRegex: `.+`},
+		"empty": {
+			selector: ``,
+			errRegex: `.+`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			root, _ := parseHTML(testSelectHTML)
+
+			// WHEN the selector is compiled
+			selector, err := compileCSS(tc.selector)
+
+			// THEN it err's when expected
+			if tc.errRegex != "" || err != nil {
+				e := util.ErrorToString(err)
+				re := regexp.MustCompile(tc.errRegex)
+				match := re.MatchString(e)
+				if !match || tc.errRegex == "" {
+					t.Fatalf("want match for %q\nnot: %q",
+						tc.errRegex, e)
+				}
+				return
+			}
+			// AND the expected elements are selected
+			var got []string
+			for _, element := range selectCSS(root, selector) {
+				value, exists := nodeValue(element, "href")
+				if !exists {
+					value = nodeText(element)
+				}
+				got = append(got, value)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func mustCompileCSS(t *testing.T, selector string) cascadia.SelectorGroup {
+	compiled, err := compileCSS(selector)
+	if err != nil {
+		t.Fatalf("compileCSS(%q) failed: %v",
+			selector, err)
+	}
+	return compiled
+}
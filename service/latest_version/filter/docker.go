@@ -30,7 +30,7 @@ import (
 )
 
 var dockerCheckTypes = []string{
-	"hub", "quay", "ghcr"}
+	"hub", "quay", "ghcr", "registry"}
 
 // DockerCheckRegistryBase is the base for checking a Docker registry for an image:tag.
 type DockerCheckRegistryBase struct {
@@ -160,9 +160,18 @@ type DockerCheck struct {
 	Username                string `yaml:"username,omitempty" json:"username,omitempty"` // Username to get a new token
 	DockerCheckRegistryBase `yaml:",inline" json:",inline"`
 
+	Registry          string `yaml:"registry,omitempty" json:"registry,omitempty"`                       // Host of the registry (type=registry)
+	CAFile            string `yaml:"ca_file,omitempty" json:"ca_file,omitempty"`                         // PEM file of CA certificates to trust for the Registry
+	AllowInvalidCerts *bool  `yaml:"allow_invalid_certs,omitempty" json:"allow_invalid_certs,omitempty"` // Skip TLS verification of the Registry
+
 	Image string `yaml:"image,omitempty" json:"image,omitempty"` // Image to check
 	Tag   string `yaml:"tag,omitempty" json:"tag,omitempty"`     // Tag to check for
 
+	Platforms []string `yaml:"platforms,omitempty" json:"platforms,omitempty"` // Platforms (os/arch[/variant]) that the Tag must be available for
+
+	registryAuth   *RegistryAuth // Auth for the registry v2 API
+	registryClient *http.Client  // Client for the registry v2 API
+
 	Defaults *DockerCheckDefaults `yaml:"-" json:"-"` // Default values for DockerCheck
 }
 
@@ -206,6 +215,13 @@ func (r *Require) DockerTagCheck(
 	}
 	var url string
 	tag := r.Docker.GetTag(version)
+	// Generic registry, query the v2 API
+	if r.Docker.getType() == "registry" {
+		if err := r.Docker.RegistryTagCheck(tag); err != nil {
+			return err
+		}
+		return r.Docker.PlatformsCheck(tag)
+	}
 	var req *http.Request
 	queryToken, err := r.Docker.getQueryToken()
 	if err != nil {
@@ -250,7 +266,7 @@ func (r *Require) DockerTagCheck(
 			r.Docker.Image, tag)
 	}
 
-	return nil
+	return r.Docker.PlatformsCheck(tag)
 }
 
 // CheckValues of the DockerCheck.
@@ -292,6 +308,29 @@ func (d *DockerCheck) CheckValues(prefix string) (errs error) {
 			util.ErrorToString(errs), prefix, d.Tag)
 	}
 
+	for _, platform := range d.Platforms {
+		if !dockerPlatformRegex.MatchString(platform) {
+			errs = fmt.Errorf("%s%splatforms: %q <invalid> (use 'os/arch[/variant]', e.g. 'linux/arm64')\\",
+				util.ErrorToString(errs), prefix, platform)
+		}
+	}
+
+	if d.getType() == "registry" {
+		if d.Registry == "" {
+			errs = fmt.Errorf("%s%sregistry: <required> (host of the registry, e.g. 'harbor.example.com')\\",
+				util.ErrorToString(errs), prefix)
+		} else if _, err := registryURL(d.Registry); err != nil {
+			errs = fmt.Errorf("%s%sregistry: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, d.Registry, err)
+		}
+		if d.CAFile != "" {
+			if _, err := certPool(d.CAFile); err != nil {
+				errs = fmt.Errorf("%s%sca_file: %q <invalid> (%s)\\",
+					util.ErrorToString(errs), prefix, d.CAFile, err)
+			}
+		}
+	}
+
 	if err := d.checkToken(); err != nil {
 		errs = fmt.Errorf("%s%s%w\\",
 			util.ErrorToString(errs), prefix, err)
@@ -317,6 +356,12 @@ func (d *DockerCheck) checkToken() (err error) {
 		} else if username == "" && token != "" {
 			err = fmt.Errorf("username: <required> (token is for who?)")
 		}
+	case "registry":
+		// token alone is a static Bearer token
+		if d.Username != "" && d.Token == "" {
+			err = fmt.Errorf("token: <required> (password for %s)",
+				d.Username)
+		}
 	case "quay":
 	case "ghcr":
 	}
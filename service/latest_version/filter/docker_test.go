@@ -1612,6 +1612,58 @@ func TestDockerCheck_CheckValues(t *testing.T) {
 				"", "", "", time.Now(),
 				&DockerCheckDefaults{Type: ""}),
 		},
+		"valid platforms": {
+			errRegex: "^$",
+			dockerCheck: &DockerCheck{
+				Type:      "ghcr",
+				Image:     "release-argus/argus",
+				Tag:       "1.2.3",
+				Platforms: []string{"linux/amd64", "linux/arm/v7"}},
+		},
+		"invalid platforms": {
+			errRegex: `^-platforms: "amd64" <invalid>.*-platforms: "linux/ARM64" <invalid>`,
+			dockerCheck: &DockerCheck{
+				Type:      "ghcr",
+				Image:     "release-argus/argus",
+				Tag:       "1.2.3",
+				Platforms: []string{"amd64", "linux/arm64", "linux/ARM64"}},
+		},
+		"registry": {
+			errRegex: "^$",
+			dockerCheck: &DockerCheck{
+				Type:     "registry",
+				Registry: "harbor.example.com",
+				Username: "user",
+				DockerCheckRegistryBase: DockerCheckRegistryBase{
+					Token: "pass"},
+				Image: "mirror/argus",
+				Tag:   "1.2.3"},
+		},
+		"registry without host": {
+			errRegex: `^-registry: <required>`,
+			dockerCheck: &DockerCheck{
+				Type:  "registry",
+				Image: "mirror/argus",
+				Tag:   "1.2.3"},
+		},
+		"registry with invalid host and ca_file": {
+			errRegex: `^-registry: "harbor.example.com/v2" <invalid>.*-ca_file: "/does/not/exist.pem" <invalid>`,
+			dockerCheck: &DockerCheck{
+				Type:     "registry",
+				Registry: "harbor.example.com/v2",
+				CAFile:   "/does/not/exist.pem",
+				Image:    "mirror/argus",
+				Tag:      "1.2.3"},
+		},
+		"registry with username but no password": {
+			errRegex: `^-token: <required> \(password for user\)`,
+			dockerCheck: &DockerCheck{
+				Type:     "registry",
+				Registry: "harbor.example.com",
+				Username: "user",
+				Image:    "mirror/argus",
+				Tag:      "1.2.3"},
+		},
 		"image with period in name": {
 			errRegex: "^$",
 			dockerCheck: &DockerCheck{
@@ -0,0 +1,66 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// parseHTML parses `body` as an HTML5 document (implied end tags are handled as a browser would),
+// returning the document root.
+func parseHTML(body string) (*html.Node, error) {
+	return html.Parse(strings.NewReader(body))
+}
+
+// nodeText returns the text of the node and its descendants (like textContent), with the whitespace collapsed.
+func nodeText(n *html.Node) string {
+	var text strings.Builder
+	var collect func(node *html.Node)
+	collect = func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			text.WriteString(node.Data)
+			return
+		case html.CommentNode:
+			return
+		case html.ElementNode:
+			// The content of these isn't text of the page.
+			if node.Data == "script" || node.Data == "style" {
+				return
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(n)
+	return strings.Join(strings.Fields(text.String()), " ")
+}
+
+// nodeValue returns the `attribute` of the element, or its text if `attribute` is empty.
+func nodeValue(n *html.Node, attribute string) (value string, exists bool) {
+	if attribute == "" {
+		return nodeText(n), true
+	}
+
+	for _, attr := range n.Attr {
+		if strings.EqualFold(attr.Key, attribute) {
+			return attr.Val, true
+		}
+	}
+	return
+}
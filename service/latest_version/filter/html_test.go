@@ -0,0 +1,132 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestParseHTML(t *testing.T) {
+	// GIVEN some HTML
+	tests := map[string]struct {
+		body     string
+		wantText string
+		wantTags []string
+	}{
+		"well-formed": {
+			body:     `<html><body><p>foo <b>bar</b></p></body></html>`,
+			wantText: "foo bar",
+			wantTags: []string{"html", "head", "body", "p", "b"}},
+		"void elements and implied end tags": {
+			body:     `<ul><li>one<br><li>two<img src="x.png"></ul><p>a<p>b<table><tr><td>c<td>d</table>`,
+			wantText: "onetwoabcd",
+			wantTags: []string{"html", "head", "body", "ul", "li", "br", "li", "img", "p", "p", "table", "tbody", "tr", "td", "td"}},
+		"text split across elements": {
+			body:     `<span>1.2.<b>3</b></span>`,
+			wantText: "1.2.3",
+			wantTags: []string{"html", "head", "body", "span", "b"}},
+		"existing whitespace collapsed": {
+			body:     "<div>\n  <p>\t1.2.3 </p>\n  <p>beta</p>\n</div>",
+			wantText: "1.2.3 beta",
+			wantTags: []string{"html", "head", "body", "div", "p", "p"}},
+		"unquoted attributes and entities": {
+			body:     `<div class=release>1.0&nbsp;&amp; more</div>`,
+			wantText: "1.0 & more",
+			wantTags: []string{"html", "head", "body", "div"}},
+		"comments, scripts and styles aren't text": {
+			body:     `<p>a<!-- <b>c</b> --></p><script>if (1 < 2) {}</script><style>p > b {}</style>`,
+			wantText: "a",
+			wantTags: []string{"html", "head", "body", "p", "script", "style"}},
+		"stray end tags ignored": {
+			body:     `<div></span><p>text</p></div>`,
+			wantText: "text",
+			wantTags: []string{"html", "head", "body", "div", "p"}},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN parseHTML is called on it
+			root, err := parseHTML(tc.body)
+
+			// THEN it parses without error
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			// AND the text is as expected
+			if got := nodeText(root); got != tc.wantText {
+				t.Errorf("want text %q, got %q",
+					tc.wantText, got)
+			}
+			// AND the elements are as expected
+			var tags []string
+			var walk func(node *html.Node)
+			walk = func(node *html.Node) {
+				if node.Type == html.ElementNode {
+					tags = append(tags, node.Data)
+				}
+				for child := node.FirstChild; child != nil; child = child.NextSibling {
+					walk(child)
+				}
+			}
+			walk(root)
+			if strings.Join(tags, ",") != strings.Join(tc.wantTags, ",") {
+				t.Errorf("want tags %v, got %v",
+					tc.wantTags, tags)
+			}
+		})
+	}
+}
+
+func TestNodeValue(t *testing.T) {
+	// GIVEN an element
+	root, _ := parseHTML(`<a HREF="/dl/v1.2.3.tar.gz" class="x">  v1.2.3 <b>source</b></a>`)
+	anchors := selectCSS(root, mustCompileCSS(t, "a"))
+	tests := map[string]struct {
+		attribute  string
+		want       string
+		wantExists bool
+	}{
+		"text": {
+			want: "v1.2.3 source", wantExists: true},
+		"attribute (case-insensitive)": {
+			attribute: "href", want: "/dl/v1.2.3.tar.gz", wantExists: true},
+		"missing attribute": {
+			attribute: "data-tag", wantExists: false},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN nodeValue is called on it
+			got, exists := nodeValue(anchors[0], tc.attribute)
+
+			// THEN the text/attribute is returned
+			if got != tc.want || exists != tc.wantExists {
+				t.Errorf("want: %q, %t\ngot:  %q, %t",
+					tc.want, tc.wantExists, got, exists)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	net_url "net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// dockerRegistryURLs are the registry v2 API URLs for each DockerCheck type (except the generic registry).
+var dockerRegistryURLs = map[string]string{
+	"hub":  "https://registry-1.docker.io",
+	"ghcr": "https://ghcr.io",
+	"quay": "https://quay.io"}
+
+// dockerManifestMediaTypes are the manifest formats accepted from a registry.
+var dockerManifestMediaTypes = []string{
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.oci.image.manifest.v1+json"}
+
+// dockerPlatformRegex is the format of a platform, e.g. linux/amd64 or linux/arm/v7.
+var dockerPlatformRegex = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+
+// dockerManifestMaxSize is the largest manifest/config that will be read.
+const dockerManifestMaxSize = 4 * 1024 * 1024
+
+// PlatformsCheck will verify that every Platform is available for the image:`tag`.
+func (d *DockerCheck) PlatformsCheck(tag string) error {
+	if len(d.Platforms) == 0 {
+		return nil
+	}
+
+	client, auth, baseURL, err := d.registryAPI()
+	if err != nil {
+		return fmt.Errorf("%s:%s - %w",
+			d.Image, tag, err)
+	}
+	platforms, err := imagePlatforms(client, auth, baseURL, d.Image, tag)
+	if err != nil {
+		return fmt.Errorf("%s:%s - %w",
+			d.Image, tag, err)
+	}
+
+	if missing := missingPlatforms(d.Platforms, platforms); len(missing) != 0 {
+		return fmt.Errorf("%s:%s - missing platforms [%s] (found [%s])",
+			d.Image, tag, strings.Join(missing, ", "), strings.Join(platforms, ", "))
+	}
+	return nil
+}
+
+// RegistryTagCheck will verify that the image:`tag` exists on the (type=registry) Registry.
+func (d *DockerCheck) RegistryTagCheck(tag string) error {
+	client, auth, baseURL, err := d.registryAPI()
+	if err != nil {
+		return fmt.Errorf("%s:%s - %w",
+			d.Image, tag, err)
+	}
+
+	if _, err = registryGet(client, auth,
+		fmt.Sprintf("%s/v2/%s/manifests/%s", baseURL, d.Image, tag),
+		strings.Join(dockerManifestMediaTypes, ", ")); err != nil {
+		return fmt.Errorf("%s:%s - %w",
+			d.Image, tag, err)
+	}
+	return nil
+}
+
+// registryAPI returns the client, auth and base URL for queries to the registry v2 API of this DockerCheck.
+func (d *DockerCheck) registryAPI() (client *http.Client, auth *RegistryAuth, baseURL string, err error) {
+	dType := d.getType()
+	baseURL = dockerRegistryURLs[dType]
+	if dType == "registry" {
+		if baseURL, err = registryURL(d.Registry); err != nil {
+			return
+		}
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if d.registryClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if dType == "registry" {
+			if transport.TLSClientConfig, err = d.tlsConfig(); err != nil {
+				return
+			}
+		}
+		d.registryClient = &http.Client{Transport: transport}
+	}
+	if d.registryAuth == nil {
+		username := d.Username
+		if dType != "registry" {
+			username = d.getUsername()
+		}
+		d.registryAuth = newRegistryAuthForType(dType, username, d.getToken())
+	}
+
+	return d.registryClient, d.registryAuth, baseURL, nil
+}
+
+// tlsConfig for the Registry, trusting the CAFile and/or skipping verification with AllowInvalidCerts.
+func (d *DockerCheck) tlsConfig() (*tls.Config, error) {
+	//#nosec G402 -- explicitly wanted InsecureSkipVerify
+	config := &tls.Config{
+		InsecureSkipVerify: util.DefaultIfNil(d.AllowInvalidCerts)}
+	if d.CAFile != "" {
+		pool, err := certPool(d.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("ca_file %q: %w", d.CAFile, err)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// certPool returns the system certificate pool with the certificates of the PEM `file` added.
+func certPool(file string) (*x509.CertPool, error) {
+	pemCerts, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pemCerts) {
+		return nil, fmt.Errorf("no PEM certificates found")
+	}
+	return pool, nil
+}
+
+// registryURL returns the base URL of the `registry` host (https if no scheme is given),
+// e.g. harbor.example.com -> https://harbor.example.com.
+func registryURL(registry string) (string, error) {
+	if !strings.Contains(registry, "://") {
+		registry = "https://" + registry
+	}
+	parsed, err := net_url.Parse(registry)
+	if err != nil || parsed.Host == "" || (parsed.Path != "" && parsed.Path != "/") {
+		return "", fmt.Errorf("use 'host[:port]', e.g. 'harbor.example.com'")
+	}
+	if parsed.Scheme != "https" && parsed.Scheme != "http" {
+		return "", fmt.Errorf("scheme must be http or https")
+	}
+	return parsed.Scheme + "://" + parsed.Host, nil
+}
+
+// imagePlatforms returns the platforms that image:`reference` is available for on the registry at `registryURL`.
+//
+// A manifest list/image index gives the platform of each of its manifests,
+// whereas a single-platform manifest requires the config blob for its platform.
+func imagePlatforms(
+	client *http.Client,
+	auth *RegistryAuth,
+	registryURL string,
+	image string,
+	reference string,
+) (platforms []string, err error) {
+	body, err := registryGet(client, auth,
+		fmt.Sprintf("%s/v2/%s/manifests/%s", registryURL, image, reference),
+		strings.Join(dockerManifestMediaTypes, ", "))
+	if err != nil {
+		return nil, fmt.Errorf("manifest query failed: %w", err)
+	}
+	var manifest github_types.ContainerManifest
+	if err = json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("unmarshal of manifest failed: %w", err)
+	}
+
+	// Manifest list/image index
+	if len(manifest.Manifests) != 0 {
+		for _, m := range manifest.Manifests {
+			// Attestations are listed with an unknown platform
+			if m.Platform == nil || m.Platform.OS == "unknown" {
+				continue
+			}
+			platforms = append(platforms, m.Platform.String())
+		}
+		return
+	}
+
+	// Single-platform manifest
+	if manifest.Config == nil || manifest.Config.Digest == "" {
+		return nil, fmt.Errorf("manifest has neither manifests nor a config")
+	}
+	body, err = registryGet(client, auth,
+		fmt.Sprintf("%s/v2/%s/blobs/%s", registryURL, image, manifest.Config.Digest),
+		"")
+	if err != nil {
+		return nil, fmt.Errorf("config query failed: %w", err)
+	}
+	var platform github_types.ContainerPlatform
+	if err = json.Unmarshal(body, &platform); err != nil {
+		return nil, fmt.Errorf("unmarshal of config failed: %w", err)
+	}
+	platforms = []string{platform.String()}
+	return
+}
+
+// registryGet returns the body of a GET to `url`, accepting the `accept` media types.
+func registryGet(client *http.Client, auth *RegistryAuth, url string, accept string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	req.Header.Set("Connection", "close")
+
+	resp, err := auth.Do(client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dockerManifestMaxSize))
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	return body, nil
+}
+
+// missingPlatforms returns the platforms of `want` that aren't in `have`.
+//
+// A platform without a variant (e.g. linux/arm) is satisfied by any variant of it (e.g. linux/arm/v7).
+func missingPlatforms(want []string, have []string) (missing []string) {
+	for _, platform := range want {
+		found := false
+		for _, available := range have {
+			if available == platform || strings.HasPrefix(available, platform+"/") {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, platform)
+		}
+	}
+	return
+}
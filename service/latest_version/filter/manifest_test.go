@@ -0,0 +1,307 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestImagePlatforms(t *testing.T) {
+	// GIVEN a registry with a manifest for an image
+	tests := map[string]struct {
+		manifest      string
+		config        string
+		wantAccept    bool
+		wantPlatforms []string
+		errRegex      string
+	}{
+		"manifest list": {
+			manifest: `{
+				"mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+				"manifests": [
+					{"digest": "sha256:1", "platform": {"os": "linux", "architecture": "amd64"}},
+					{"digest": "sha256:2", "platform": {"os": "linux", "architecture": "arm", "variant": "v7"}}]}`,
+			wantPlatforms: []string{"linux/amd64", "linux/arm/v7"},
+			errRegex:      `^$`},
+		"OCI image index with attestations": {
+			manifest: `{
+				"mediaType": "application/vnd.oci.image.index.v1+json",
+				"manifests": [
+					{"digest": "sha256:1", "platform": {"os": "linux", "architecture": "arm64"}},
+					{"digest": "sha256:2", "platform": {"os": "unknown", "architecture": "unknown"}}]}`,
+			wantPlatforms: []string{"linux/arm64"},
+			errRegex:      `^$`},
+		"single-platform manifest": {
+			manifest: `{
+				"mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+				"config": {"mediaType": "application/vnd.docker.container.image.v1+json", "digest": "sha256:config"}}`,
+			config:        `{"architecture": "arm64", "os": "linux", "variant": "v8"}`,
+			wantPlatforms: []string{"linux/arm64/v8"},
+			errRegex:      `^$`},
+		"single-platform manifest without a config": {
+			manifest: `{"mediaType": "application/vnd.oci.image.manifest.v1+json"}`,
+			errRegex: `^manifest has neither manifests nor a config$`},
+		"single-platform manifest with a missing config": {
+			manifest: `{
+				"mediaType": "application/vnd.oci.image.manifest.v1+json",
+				"config": {"digest": "sha256:config"}}`,
+			errRegex: `^config query failed: 404 Not Found$`},
+		"missing manifest": {
+			errRegex: `^manifest query failed: 404 Not Found$`},
+		"invalid manifest": {
+			manifest: `[]`,
+			errRegex: `^unmarshal of manifest failed: `},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/v2/release-argus/argus/manifests/1.2.3" && tc.manifest != "":
+					if !strings.Contains(r.Header.Get("Accept"), "application/vnd.oci.image.index.v1+json") {
+						t.Errorf("manifest requested without accepting image indexes: %q",
+							r.Header.Get("Accept"))
+					}
+					w.Write([]byte(tc.manifest))
+				case r.URL.Path == "/v2/release-argus/argus/blobs/sha256:config" && tc.config != "":
+					w.Write([]byte(tc.config))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			// WHEN imagePlatforms is called
+			platforms, err := imagePlatforms(
+				server.Client(), &RegistryAuth{}, server.URL, "release-argus/argus", "1.2.3")
+
+			// THEN the platforms of the image are returned
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if strings.Join(platforms, ",") != strings.Join(tc.wantPlatforms, ",") {
+				t.Errorf("want platforms %v, got %v",
+					tc.wantPlatforms, platforms)
+			}
+		})
+	}
+}
+
+func TestMissingPlatforms(t *testing.T) {
+	// GIVEN the platforms wanted and the platforms available
+	have := []string{"linux/amd64", "linux/arm64/v8", "linux/arm/v7"}
+	tests := map[string]struct {
+		want        []string
+		wantMissing []string
+	}{
+		"all available": {
+			want: []string{"linux/amd64", "linux/arm64/v8"}},
+		"any variant": {
+			want: []string{"linux/arm64", "linux/arm"}},
+		"variant missing": {
+			want:        []string{"linux/arm/v6", "linux/arm/v7"},
+			wantMissing: []string{"linux/arm/v6"}},
+		"arch isn't a prefix": {
+			want:        []string{"linux/arm64", "linux/amd"},
+			wantMissing: []string{"linux/amd"}},
+		"os missing": {
+			want:        []string{"windows/amd64"},
+			wantMissing: []string{"windows/amd64"}},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN missingPlatforms is called
+			got := missingPlatforms(tc.want, have)
+
+			// THEN the platforms that aren't available are returned
+			if strings.Join(got, ",") != strings.Join(tc.wantMissing, ",") {
+				t.Errorf("want %v, got %v",
+					tc.wantMissing, got)
+			}
+		})
+	}
+}
+
+func TestDockerCheck_RegistryTagCheck(t *testing.T) {
+	// GIVEN a DockerCheck on a registry with a self-signed certificate
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"token":"query-token","expires_in":300}`)
+			return
+		}
+
+		if auth := r.Header.Get("Authorization"); auth != "Bearer query-token" && auth != "Bearer static-token" {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="https://%s/token",service="registry",scope="repository:mirror/argus:pull"`,
+					r.Host))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v2/mirror/argus/manifests/1.2.3" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"mediaType":"application/vnd.oci.image.index.v1+json","manifests":[{"digest":"sha256:1","platform":{"os":"linux","architecture":"amd64"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(caFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+		0600)
+	tests := map[string]struct {
+		tag               string
+		username, token   string
+		caFile            string
+		allowInvalidCerts bool
+		platforms         []string
+		errRegex          string
+	}{
+		"username/password with custom CA": {
+			tag:      "1.2.3",
+			username: "user", token: "pass",
+			caFile:   caFile,
+			errRegex: `^$`},
+		"static token with allow_invalid_certs": {
+			tag:               "1.2.3",
+			token:             "static-token",
+			allowInvalidCerts: true,
+			errRegex:          `^$`},
+		"untrusted certificate": {
+			tag:      "1.2.3",
+			token:    "static-token",
+			errRegex: `^mirror/argus:1.2.3 - .*x509`},
+		"wrong password": {
+			tag:      "1.2.3",
+			username: "user", token: "wrong",
+			caFile:   caFile,
+			errRegex: `^mirror/argus:1.2.3 - registry token request failed`},
+		"tag not found": {
+			tag:      "1.2.4",
+			token:    "static-token",
+			caFile:   caFile,
+			errRegex: `^mirror/argus:1.2.4 - 404 Not Found$`},
+		"platforms available": {
+			tag:       "1.2.3",
+			token:     "static-token",
+			caFile:    caFile,
+			platforms: []string{"linux/amd64"},
+			errRegex:  `^$`},
+		"platforms missing": {
+			tag:       "1.2.3",
+			token:     "static-token",
+			caFile:    caFile,
+			platforms: []string{"linux/amd64", "linux/arm64"},
+			errRegex:  `^mirror/argus:1.2.3 - missing platforms \[linux/arm64\] \(found \[linux/amd64\]\)$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require := Require{
+				Docker: &DockerCheck{
+					Type:     "registry",
+					Registry: server.Listener.Addr().String(),
+					CAFile:   tc.caFile,
+					DockerCheckRegistryBase: DockerCheckRegistryBase{
+						Token: tc.token},
+					Username:          tc.username,
+					AllowInvalidCerts: &tc.allowInvalidCerts,
+					Image:             "mirror/argus",
+					Tag:               "{{ version }}",
+					Platforms:         tc.platforms}}
+
+			// WHEN DockerTagCheck is called
+			err := require.DockerTagCheck(tc.tag)
+
+			// THEN the tag is checked on the registry
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestRegistryURL(t *testing.T) {
+	// GIVEN a registry
+	tests := map[string]struct {
+		registry string
+		want     string
+		errRegex string
+	}{
+		"host": {
+			registry: "harbor.example.com", want: "https://harbor.example.com", errRegex: `^$`},
+		"host and port": {
+			registry: "harbor.example.com:8443", want: "https://harbor.example.com:8443", errRegex: `^$`},
+		"http URL": {
+			registry: "http://registry.local:5000/", want: "http://registry.local:5000", errRegex: `^$`},
+		"path": {
+			registry: "harbor.example.com/v2", errRegex: `^use 'host\[:port\]'`},
+		"scheme": {
+			registry: "ftp://harbor.example.com", errRegex: `^scheme must be http or https$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN registryURL is called on it
+			got, err := registryURL(tc.registry)
+
+			// THEN the base URL is returned
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if got != tc.want {
+				t.Errorf("want %q, got %q",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisignPublicKey is a parsed minisign public key.
+type minisignPublicKey struct {
+	keyID [8]byte
+	key   ed25519.PublicKey
+}
+
+// parseMinisignPublicKey parses a minisign public key.
+//
+// Accepts either the base64 key ("RWQ...") or the contents of a minisign.pub file.
+func parseMinisignPublicKey(publicKey string) (*minisignPublicKey, error) {
+	encoded := lastNonCommentLine(publicKey)
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(decoded) != 2+8+ed25519.PublicKeySize {
+		return nil, errors.New("not a minisign public key")
+	}
+	if string(decoded[:2]) != "Ed" {
+		return nil, fmt.Errorf("unsupported signature algorithm %q", decoded[:2])
+	}
+
+	key := minisignPublicKey{key: ed25519.PublicKey(decoded[10:])}
+	copy(key.keyID[:], decoded[2:10])
+	return &key, nil
+}
+
+// verifyMinisign verifies that `signature` is a valid minisign signature of `message` by `publicKey`.
+func verifyMinisign(publicKey string, message []byte, signature []byte) error {
+	key, err := parseMinisignPublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+
+	// untrusted comment, signature, trusted comment, global signature
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(signature), "\r\n", "\n")), "\n")
+	if len(lines) < 4 ||
+		!strings.HasPrefix(lines[2], "trusted comment: ") {
+		return errors.New("malformed minisign signature")
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil || len(sig) != 2+8+ed25519.SignatureSize {
+		return errors.New("malformed minisign signature")
+	}
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return errors.New("malformed minisign signature")
+	}
+
+	if !bytes.Equal(sig[2:10], key.keyID[:]) {
+		return fmt.Errorf("signed with key %X, not %X",
+			reverse(sig[2:10]), reverse(key.keyID[:]))
+	}
+
+	// Ed = signature of the message, ED = signature of the BLAKE2b-512 hash of the message
+	switch string(sig[:2]) {
+	case "Ed":
+	case "ED":
+		digest := blake2b.Sum512(message)
+		message = digest[:]
+	default:
+		return fmt.Errorf("unsupported signature algorithm %q", sig[:2])
+	}
+	if !ed25519.Verify(key.key, message, sig[10:]) {
+		return errors.New("invalid signature")
+	}
+
+	// The trusted comment is signed along with the signature
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	signed := make([]byte, 0, ed25519.SignatureSize+len(trustedComment))
+	signed = append(append(signed, sig[10:]...), trustedComment...)
+	if !ed25519.Verify(key.key, signed, globalSig) {
+		return errors.New("invalid signature of the trusted comment")
+	}
+
+	return nil
+}
+
+// lastNonCommentLine returns the last line of `str` that isn't a minisign comment.
+func lastNonCommentLine(str string) (line string) {
+	for _, l := range strings.Split(str, "\n") {
+		l = strings.TrimSpace(l)
+		if l != "" && !strings.HasPrefix(l, "untrusted comment:") {
+			line = l
+		}
+	}
+	return
+}
+
+// reverse returns a reversed copy of `b` (minisign key IDs are displayed little-endian).
+func reverse(b []byte) []byte {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[len(b)-1-i] = b[i]
+	}
+	return reversed
+}
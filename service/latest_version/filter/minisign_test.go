@@ -0,0 +1,293 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+	"golang.org/x/crypto/blake2b"
+)
+
+// testMinisignKey returns a minisign private key, and its public key in minisign format.
+func testMinisignKey(seed byte) (ed25519.PrivateKey, string) {
+	privateKey := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{seed}, ed25519.SeedSize))
+	keyID := bytes.Repeat([]byte{seed}, 8)
+	publicKey := append(append([]byte("Ed"), keyID...), privateKey.Public().(ed25519.PublicKey)...)
+	return privateKey, base64.StdEncoding.EncodeToString(publicKey)
+}
+
+// testMinisignSign returns a minisign signature of `message` with `privateKey`.
+func testMinisignSign(privateKey ed25519.PrivateKey, keyIDSeed byte, message []byte, prehashed bool) []byte {
+	algorithm := "Ed"
+	if prehashed {
+		algorithm = "ED"
+		digest := blake2b.Sum512(message)
+		message = digest[:]
+	}
+	sig := append(append([]byte(algorithm), bytes.Repeat([]byte{keyIDSeed}, 8)...),
+		ed25519.Sign(privateKey, message)...)
+	trustedComment := "timestamp:1690000000\tfile:SHA256SUMS"
+	globalSig := ed25519.Sign(privateKey, append(append([]byte{}, sig[10:]...), trustedComment...))
+
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(sig),
+		trustedComment,
+		base64.StdEncoding.EncodeToString(globalSig)))
+}
+
+func TestBlake2bSum512(t *testing.T) {
+	// GIVEN data of a single block, and of multiple blocks (128 bytes each)
+	// (known answers from Python's hashlib.blake2b)
+	tests := map[string]struct {
+		data string
+		want string
+	}{
+		"empty": {
+			data: "",
+			want: "786a02f742015903c6c6fd852552d272912f4740e15847618a86e217f71f5419d25e1031afee585313896444934eb04b903a685b1448b755d56f701afe9be2ce"},
+		"abc": {
+			data: "abc",
+			want: "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923"},
+		"127 bytes": {
+			data: strings.Repeat("a", 127),
+			want: "94596b9d6199c807c40ae1a935f3633ba5a8dd5655f7f1bd44f5285b1ce8dbb0054771eba409539df85a963296d28788807105153c90fa3ec3d761228e90f8b8"},
+		"128 bytes": {
+			data: strings.Repeat("a", 128),
+			want: "fc6c71f688f43ea7d60817478808f3cac753e61571865c95adbc2d9122c943a76b92c2cb1047ef3fe7bf6e436ec1d0a99a9e5b216780bf7fed9d7ca91d3a8f3b"},
+		"129 bytes": {
+			data: strings.Repeat("a", 129),
+			want: "55e6e0eb418149a8af92fd9ddc99254781b2f522a131b4f4d984404b71a00e1167b8124d5dcddd4c6977b299392335d6edd303da6d344d74bbef2d38101b232b"},
+		"256 bytes": {
+			data: strings.Repeat("a", 256),
+			want: "0eee13d0c73a2710c5015a8b4be0a16120bb88f826b662951ffe4b3b81441cfdce1f712c58e237dba72a0dad7f9c86b9745ea0b4b3b850ff3a260fb7df9d3e81"},
+		"1000 bytes": {
+			data: strings.Repeat("a", 1000),
+			want: "d6a69459fe93fc6b9537ed4336e5099e0dcca3e97290a412500ed7a0daffb03d80cf3650a20e0591f748e10c3c534945ee83d5f2c9722f1a68d98b8c01af23fd"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN the BLAKE2b-512 digest that minisign prehashes with is taken
+			got := blake2b.Sum512([]byte(tc.data))
+
+			// THEN it's the known answer
+			if hex.EncodeToString(got[:]) != tc.want {
+				t.Errorf("want %s\ngot  %x",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestVerifyMinisign_Fixtures(t *testing.T) {
+	// GIVEN signatures generated by minisign itself
+	// (from the tests of github.com/jedisct1/go-minisign and aead.dev/minisign)
+	const publicKey = "RWQf6LRCGA9i53mlYecO4IzT51TGPpvWucNSCh1CBM0QTaLn73Y7GFO3"
+	tests := map[string]struct {
+		publicKey string
+		message   string
+		signature string
+		errRegex  string
+	}{
+		"legacy signature": {
+			publicKey: publicKey,
+			message:   "test",
+			signature: "untrusted comment: signature from minisign secret key\n" +
+				"RWQf6LRCGA9i59SLOFxz6NxvASXDJeRtuZykwQepbDEGt87ig1BNpWaVWuNrm73YiIiJbq71Wi+dP9eKL8OC351vwIasSSbXxwA=\n" +
+				"trusted comment: timestamp:1635442742\tfile:test\n" +
+				"0YteLgV960ia80vnA/fHbvkyjl/IoP/HNOCaZfrF0CdhAlp7ok+Tpkya+VpWPX5C/Is3q8a/kEDSY7fBmmgJCg==\n",
+			errRegex: `^$`},
+		"prehashed signature": {
+			publicKey: publicKey,
+			message:   "test",
+			signature: "untrusted comment: signature from minisign secret key\n" +
+				"RUQf6LRCGA9i559r3g7V1qNyJDApGip8MfqcadIgT9CuhV3EMhHoN1mGTkUidF/z7SrlQgXdy8ofjb7bNJJylDOocrCo8KLzZwo=\n" +
+				"trusted comment: timestamp:1635443258\tfile:test\thashed\n" +
+				"/cj37GK60vryibFn+ftOgbCvW9NKhKYgjVpFFQUcWPAnjO23wrvVDTt7cloNC06maoBli9q6qwZDXXoaxweICQ==\n",
+			errRegex: `^$`},
+		"prehashed signature of a different message": {
+			publicKey: publicKey,
+			message:   "tests",
+			signature: "untrusted comment: signature from minisign secret key\n" +
+				"RUQf6LRCGA9i559r3g7V1qNyJDApGip8MfqcadIgT9CuhV3EMhHoN1mGTkUidF/z7SrlQgXdy8ofjb7bNJJylDOocrCo8KLzZwo=\n" +
+				"trusted comment: timestamp:1635443258\tfile:test\thashed\n" +
+				"/cj37GK60vryibFn+ftOgbCvW9NKhKYgjVpFFQUcWPAnjO23wrvVDTt7cloNC06maoBli9q6qwZDXXoaxweICQ==\n",
+			errRegex: `^invalid signature$`},
+		"signature with a minisign.pub public key": {
+			publicKey: "untrusted comment: minisign public key C373193807678450\n" +
+				"RWRQhGcHOBlzw4CoKyugkk4ioDfoxlXxC9LBx+VNhJ3w9w+cAxgvPsuo\n",
+			message: "Hello World!\n",
+			signature: "untrusted comment: signature from minisign secret key\n" +
+				"RWRQhGcHOBlzwxrJCyuC+rJfHSfyRKRxkuwa3JJ0bWEs7RHjL1OUmqnTr+V1B9JzFuJIH/ybR2Eus9oEZKt9RbitpF/L4D3+5wg=\n" +
+				"trusted comment: timestamp:1614549543\tfile:message.txt\n" +
+				"P/722+ynQ+tIy0qadFHwLx5MsyNz/jDKJkDWQj4dDD2OKnVte8m/M14mwPE/1NMwzShPMSBhMXqZGdbe+UZjDg==\n",
+			errRegex: `^$`},
+		"signed by a different key": {
+			publicKey: "RWRQhGcHOBlzw4CoKyugkk4ioDfoxlXxC9LBx+VNhJ3w9w+cAxgvPsuo",
+			message:   "test",
+			signature: "untrusted comment: signature from minisign secret key\n" +
+				"RUQf6LRCGA9i559r3g7V1qNyJDApGip8MfqcadIgT9CuhV3EMhHoN1mGTkUidF/z7SrlQgXdy8ofjb7bNJJylDOocrCo8KLzZwo=\n" +
+				"trusted comment: timestamp:1635443258\tfile:test\thashed\n" +
+				"/cj37GK60vryibFn+ftOgbCvW9NKhKYgjVpFFQUcWPAnjO23wrvVDTt7cloNC06maoBli9q6qwZDXXoaxweICQ==\n",
+			errRegex: `^signed with key E7620F1842B4E81F, not C373193807678450$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN verifyMinisign is called
+			err := verifyMinisign(tc.publicKey, []byte(tc.message), []byte(tc.signature))
+
+			// THEN the signature is verified
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestVerifyMinisign(t *testing.T) {
+	// GIVEN a minisign key and signatures of a message
+	message := []byte("abc123  argus-1.2.3.linux-amd64\n")
+	privateKey, publicKey := testMinisignKey(1)
+	otherPrivateKey, otherPublicKey := testMinisignKey(2)
+	tests := map[string]struct {
+		publicKey string
+		message   []byte
+		signature []byte
+		errRegex  string
+	}{
+		"valid prehashed signature": {
+			publicKey: publicKey,
+			signature: testMinisignSign(privateKey, 1, message, true),
+			errRegex:  `^$`},
+		"valid legacy signature": {
+			publicKey: publicKey,
+			signature: testMinisignSign(privateKey, 1, message, false),
+			errRegex:  `^$`},
+		"valid signature with a minisign.pub public key": {
+			publicKey: "untrusted comment: minisign public key 0101010101010101\n" + publicKey + "\n",
+			signature: testMinisignSign(privateKey, 1, message, true),
+			errRegex:  `^$`},
+		"modified message": {
+			publicKey: publicKey,
+			message:   []byte("abc124  argus-1.2.3.linux-amd64\n"),
+			signature: testMinisignSign(privateKey, 1, message, true),
+			errRegex:  `^invalid signature$`},
+		"modified trusted comment": {
+			publicKey: publicKey,
+			signature: bytes.Replace(testMinisignSign(privateKey, 1, message, true),
+				[]byte("timestamp:1690000000"), []byte("timestamp:1690000001"), 1),
+			errRegex: `^invalid signature of the trusted comment$`},
+		"signed by a different key": {
+			publicKey: publicKey,
+			signature: testMinisignSign(otherPrivateKey, 2, message, true),
+			errRegex:  `^signed with key 0202020202020202, not 0101010101010101$`},
+		"signed by a different key with the same key ID": {
+			publicKey: otherPublicKey,
+			signature: testMinisignSign(privateKey, 2, message, true),
+			errRegex:  `^invalid signature$`},
+		"malformed signature": {
+			publicKey: publicKey,
+			signature: []byte("untrusted comment: foo\nbar\n"),
+			errRegex:  `^malformed minisign signature$`},
+		"invalid public key": {
+			publicKey: "RWQ",
+			signature: testMinisignSign(privateKey, 1, message, true),
+			errRegex:  `^not a minisign public key$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.message == nil {
+				tc.message = message
+			}
+
+			// WHEN verifyMinisign is called
+			err := verifyMinisign(tc.publicKey, tc.message, tc.signature)
+
+			// THEN the signature is verified
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestParseMinisignPublicKey(t *testing.T) {
+	// GIVEN a minisign public key
+	_, publicKey := testMinisignKey(3)
+	decoded, _ := base64.StdEncoding.DecodeString(publicKey)
+	tests := map[string]struct {
+		publicKey string
+		errRegex  string
+	}{
+		"valid": {
+			publicKey: publicKey, errRegex: `^$`},
+		"not base64": {
+			publicKey: "not!base64", errRegex: `^not a minisign public key$`},
+		"wrong length": {
+			publicKey: strings.TrimRight(publicKey, "=")[:20], errRegex: `^not a minisign public key$`},
+		"wrong algorithm": {
+			publicKey: base64.StdEncoding.EncodeToString(append([]byte("XX"), decoded[2:]...)),
+			errRegex:  `^unsupported signature algorithm "XX"$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN parseMinisignPublicKey is called on it
+			key, err := parseMinisignPublicKey(tc.publicKey)
+
+			// THEN it's parsed
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if err == nil && key.keyID != [8]byte{3, 3, 3, 3, 3, 3, 3, 3} {
+				t.Errorf("want key ID 0303030303030303, got %X",
+					key.keyID)
+			}
+		})
+	}
+}
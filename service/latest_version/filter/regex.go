@@ -106,3 +106,34 @@ func (r *Require) RegexCheckContent(
 
 	return nil
 }
+
+// RegexCheckNotes returns whether the release `notes` of `version` satisfy the
+// regex_notes_include and regex_notes_exclude.
+func (r *Require) RegexCheckNotes(
+	version string,
+	notes string,
+	logFrom *util.LogFrom,
+) error {
+	if r == nil {
+		return nil
+	}
+
+	var err error
+	if r.RegexNotesInclude != "" &&
+		!util.RegexCheckWithParams(r.RegexNotesInclude, notes, version) {
+		regexStr := util.TemplateString(r.RegexNotesInclude, util.ServiceInfo{LatestVersion: version})
+		err = fmt.Errorf("regex_notes_include %q not matched on the release notes for version %q",
+			regexStr, version)
+	} else if r.RegexNotesExclude != "" &&
+		util.RegexCheckWithParams(r.RegexNotesExclude, notes, version) {
+		regexStr := util.TemplateString(r.RegexNotesExclude, util.ServiceInfo{LatestVersion: version})
+		err = fmt.Errorf("regex_notes_exclude %q matched on the release notes for version %q",
+			regexStr, version)
+	}
+	if err != nil {
+		r.Status.RegexMissContent()
+		jLog.Info(err, *logFrom, r.Status.RegexMissesContent() == 1)
+	}
+
+	return err
+}
@@ -67,6 +67,69 @@ func TestRequire_RegexCheckVersion(t *testing.T) {
 	}
 }
 
+func TestRequire_RegexCheckNotes(t *testing.T) {
+	// GIVEN a Require and the release notes of a version
+	notes := "## v0.1.1\n- Fixed a bug\n\n**Known regression** in the API"
+	tests := map[string]struct {
+		require  *Require
+		errRegex string
+	}{
+		"nil require": {
+			require:  nil,
+			errRegex: `^$`},
+		"no notes regex": {
+			require:  &Require{},
+			errRegex: `^$`},
+		"include match": {
+			require:  &Require{RegexNotesInclude: `Fixed`},
+			errRegex: `^$`},
+		"include match with the version templated": {
+			require:  &Require{RegexNotesInclude: `## v{{ version }}`},
+			errRegex: `^$`},
+		"include no match": {
+			require:  &Require{RegexNotesInclude: `(?i)stable`},
+			errRegex: `^regex_notes_include "\(\?i\)stable" not matched on the release notes for version "0.1.1"$`},
+		"exclude no match": {
+			require:  &Require{RegexNotesExclude: `(?i)do not use`},
+			errRegex: `^$`},
+		"exclude match": {
+			require:  &Require{RegexNotesExclude: `(?i)known regression`},
+			errRegex: `^regex_notes_exclude "\(\?i\)known regression" matched on the release notes for version "0.1.1"$`},
+		"include match but exclude match": {
+			require: &Require{
+				RegexNotesInclude: `Fixed`,
+				RegexNotesExclude: `(?i)known regression`},
+			errRegex: `^regex_notes_exclude .* matched`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.require != nil {
+				tc.require.Status = &svcstatus.Status{}
+			}
+
+			// WHEN RegexCheckNotes is called on it
+			err := tc.require.RegexCheckNotes("0.1.1", notes, &util.LogFrom{})
+
+			// THEN the err is what we expect
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND a failure is counted like a regex_content miss
+			if err != nil && tc.require.Status.RegexMissesContent() != 1 {
+				t.Errorf("want 1 content miss, got %d",
+					tc.require.Status.RegexMissesContent())
+			}
+		})
+	}
+}
+
 func TestRequire_RegexCheckContent(t *testing.T) {
 	// GIVEN a Require
 	tests := map[string]struct {
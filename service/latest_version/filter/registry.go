@@ -0,0 +1,233 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	net_url "net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RegistryAuth handles the authentication for queries to an OCI distribution (Docker v2) registry,
+// answering any Bearer challenge with a token from the realm given in the WWW-Authenticate header.
+//
+// https://distribution.github.io/distribution/spec/auth/token/
+type RegistryAuth struct {
+	Username string // Username to get a token from the realm with
+	Token    string // Password for the Username, or a static Bearer token when there's no Username
+
+	queryToken string       // Token for queries
+	validUntil time.Time    // Time until the queryToken needs to be renewed
+	mutex      sync.RWMutex // Mutex for the queryToken
+}
+
+// NewRegistryAuth returns a new RegistryAuth for the `registry` host.
+//
+// If neither `username` nor `token` are given, the require.docker defaults of Docker Hub/GHCR/Quay are used
+// when `registry` is one of those.
+func NewRegistryAuth(
+	registry string,
+	username string,
+	token string,
+	defaults *DockerCheckDefaults,
+) (auth *RegistryAuth) {
+	if username != "" || token != "" {
+		return &RegistryAuth{
+			Username: username,
+			Token:    token}
+	}
+
+	dType := RegistryType(registry)
+	return newRegistryAuthForType(dType, defaults.getUsername(), defaults.getToken(dType))
+}
+
+// newRegistryAuthForType returns a new RegistryAuth using the `username`/`token` as the
+// DockerCheck `dType` registry expects them.
+func newRegistryAuthForType(dType string, username string, token string) (auth *RegistryAuth) {
+	auth = &RegistryAuth{}
+	switch dType {
+	case "hub", "registry":
+		auth.Username = username
+		auth.Token = token
+	case "ghcr":
+		auth.Token = token
+		// Base64 encode the token if it's not already
+		if strings.HasPrefix(auth.Token, "ghp_") {
+			auth.Token = base64.StdEncoding.EncodeToString([]byte(auth.Token))
+		}
+	case "quay":
+		// OAuth tokens are given as the password of this user
+		if token != "" {
+			auth.Username = "$oauthtoken"
+			auth.Token = token
+		}
+	}
+	return
+}
+
+// RegistryType returns the DockerCheck type of the `registry` host, or "" if it's not one of them.
+func RegistryType(registry string) string {
+	switch registry {
+	case "docker.io", "index.docker.io", "registry-1.docker.io", "registry.hub.docker.com":
+		return "hub"
+	case "ghcr.io":
+		return "ghcr"
+	case "quay.io":
+		return "quay"
+	}
+	return ""
+}
+
+// Do sends the request with the Authorization of this RegistryAuth,
+// requesting a new token and retrying when the registry responds with a Bearer challenge.
+func (a *RegistryAuth) Do(client *http.Client, req *http.Request) (resp *http.Response, err error) {
+	a.setAuthorization(req)
+	resp, err = client.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return
+	}
+
+	// Unauthorized, answer the challenge.
+	scheme, params := parseAuthChallenge(resp.Header.Get("WWW-Authenticate"))
+	switch scheme {
+	case "bearer":
+		resp.Body.Close()
+		if err = a.refreshToken(client, params); err != nil {
+			return
+		}
+	case "basic":
+		if a.Username == "" {
+			return
+		}
+		resp.Body.Close()
+	default:
+		return
+	}
+
+	retry := req.Clone(req.Context())
+	if scheme == "basic" {
+		retry.SetBasicAuth(a.Username, a.Token)
+	} else {
+		a.setAuthorization(retry)
+	}
+	//nolint:wrapcheck
+	return client.Do(retry)
+}
+
+// setAuthorization header of `req` with the queryToken (or static Token).
+func (a *RegistryAuth) setAuthorization(req *http.Request) {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	// Have a queryToken and it's valid for atleast 2s
+	if a.queryToken != "" && a.validUntil.After(time.Now().Add(2*time.Second).UTC()) {
+		req.Header.Set("Authorization", "Bearer "+a.queryToken)
+	} else if a.Username == "" && a.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+	}
+}
+
+// refreshToken from the realm of the Bearer challenge `params`.
+func (a *RegistryAuth) refreshToken(client *http.Client, params map[string]string) error {
+	realm := params["realm"]
+	if realm == "" {
+		return fmt.Errorf("registry token request failed - no realm in the challenge")
+	}
+	tokenURL, err := net_url.Parse(realm)
+	if err != nil {
+		return fmt.Errorf("registry token request failed - invalid realm %q: %w", realm, err)
+	}
+	query := tokenURL.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("registry token request, creation failed: %w", err)
+	}
+	req.Header.Set("Connection", "close")
+	if a.Username != "" {
+		req.SetBasicAuth(a.Username, a.Token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("registry token request failed: %w", err)
+	}
+
+	// Parse the body
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry token request failed - %s", body)
+	}
+	var tokenJSON struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err = json.Unmarshal(body, &tokenJSON); err != nil {
+		return fmt.Errorf("registry token request failed - %w", err)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	a.queryToken = tokenJSON.Token
+	if a.queryToken == "" {
+		a.queryToken = tokenJSON.AccessToken
+	}
+	// Tokens without an expiry are valid for at least 60s
+	expiresIn := time.Duration(tokenJSON.ExpiresIn) * time.Second
+	if expiresIn < time.Minute {
+		expiresIn = time.Minute
+	}
+	a.validUntil = time.Now().UTC().Add(expiresIn)
+	return nil
+}
+
+// parseAuthChallenge returns the lowercase scheme and the params of a WWW-Authenticate header,
+// e.g. `Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:owner/image:pull"`.
+func parseAuthChallenge(header string) (scheme string, params map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	scheme = strings.ToLower(scheme)
+	params = make(map[string]string)
+
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(rest, "=")
+		key = strings.ToLower(strings.Trim(key, " ,"))
+		rest = strings.TrimSpace(rest)
+		// Quoted value (may contain commas)
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key != "" {
+			params[key] = value
+		}
+		rest = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+	}
+	return
+}
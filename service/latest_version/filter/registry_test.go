@@ -0,0 +1,209 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewRegistryAuth(t *testing.T) {
+	// GIVEN registry credentials and DockerCheckDefaults
+	defaults := NewDockerCheckDefaults(
+		"", "ghp_ghcr", "hub-token", "hub-user", "quay-token", nil)
+	tests := map[string]struct {
+		registry     string
+		username     string
+		token        string
+		wantUsername string
+		wantToken    string
+	}{
+		"given credentials are used": {
+			registry:     "ghcr.io",
+			username:     "user",
+			token:        "pass",
+			wantUsername: "user",
+			wantToken:    "pass"},
+		"docker hub defaults": {
+			registry:     "registry-1.docker.io",
+			wantUsername: "hub-user",
+			wantToken:    "hub-token"},
+		"ghcr default is base64 encoded": {
+			registry:  "ghcr.io",
+			wantToken: base64.StdEncoding.EncodeToString([]byte("ghp_ghcr"))},
+		"quay default is an oauth token": {
+			registry:     "quay.io",
+			wantUsername: "$oauthtoken",
+			wantToken:    "quay-token"},
+		"other registry has no defaults": {
+			registry: "registry.example.com"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN NewRegistryAuth is called
+			auth := NewRegistryAuth(tc.registry, tc.username, tc.token, defaults)
+
+			// THEN the credentials are what we expect
+			if auth.Username != tc.wantUsername || auth.Token != tc.wantToken {
+				t.Errorf("want: %q:%q\ngot:  %q:%q",
+					tc.wantUsername, tc.wantToken, auth.Username, auth.Token)
+			}
+		})
+	}
+}
+
+func TestRegistryAuth_Do(t *testing.T) {
+	// GIVEN a registry that challenges requests without a token
+	tests := map[string]struct {
+		challenge  string
+		username   string
+		token      string
+		wantStatus int
+	}{
+		"anonymous bearer challenge": {
+			challenge:  "Bearer",
+			wantStatus: http.StatusOK},
+		"bearer challenge with credentials": {
+			challenge:  "Bearer",
+			username:   "user",
+			token:      "pass",
+			wantStatus: http.StatusOK},
+		"basic challenge with credentials": {
+			challenge:  "Basic",
+			username:   "user",
+			token:      "pass",
+			wantStatus: http.StatusOK},
+		"basic challenge without credentials": {
+			challenge:  "Basic",
+			wantStatus: http.StatusUnauthorized},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/token" {
+					token := "anonymous"
+					if username, _, ok := r.BasicAuth(); ok {
+						token = username
+					}
+					fmt.Fprintf(w, `{"access_token":%q,"expires_in":300}`, token)
+					return
+				}
+
+				wantAuth := "Bearer anonymous"
+				if tc.username != "" {
+					wantAuth = "Bearer " + tc.username
+				}
+				if tc.challenge == "Basic" {
+					if username, password, ok := r.BasicAuth(); ok && username == tc.username && password == tc.token {
+						return
+					}
+				} else if r.Header.Get("Authorization") == wantAuth {
+					return
+				}
+				if tc.challenge == "Basic" {
+					w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+				} else {
+					w.Header().Set("WWW-Authenticate",
+						fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:owner/image:pull"`, server.URL))
+				}
+				w.WriteHeader(http.StatusUnauthorized)
+			}))
+			defer server.Close()
+			auth := NewRegistryAuth("", tc.username, tc.token, nil)
+			req, _ := http.NewRequest(http.MethodGet, server.URL+"/v2/owner/image/tags/list", nil)
+
+			// WHEN Do is called with the request
+			resp, err := auth.Do(&http.Client{}, req)
+
+			// THEN the challenge is answered when possible
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tc.wantStatus {
+				t.Errorf("want status %d, got %d",
+					tc.wantStatus, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	// GIVEN a WWW-Authenticate header
+	tests := map[string]struct {
+		header     string
+		wantScheme string
+		wantParams map[string]string
+	}{
+		"empty": {
+			header:     "",
+			wantScheme: "",
+			wantParams: map[string]string{}},
+		"bearer": {
+			header:     `Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:owner/image:pull"`,
+			wantScheme: "bearer",
+			wantParams: map[string]string{
+				"realm":   "https://ghcr.io/token",
+				"service": "ghcr.io",
+				"scope":   "repository:owner/image:pull"}},
+		"quoted comma and unquoted value": {
+			header:     `Bearer realm="https://auth.example.com/token", scope="repository:a:pull,push", error=insufficient_scope`,
+			wantScheme: "bearer",
+			wantParams: map[string]string{
+				"realm": "https://auth.example.com/token",
+				"scope": "repository:a:pull,push",
+				"error": "insufficient_scope"}},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN parseAuthChallenge is called on it
+			scheme, params := parseAuthChallenge(tc.header)
+
+			// THEN the scheme and params are parsed
+			if scheme != tc.wantScheme {
+				t.Errorf("want scheme %q, got %q",
+					tc.wantScheme, scheme)
+			}
+			if len(params) != len(tc.wantParams) {
+				t.Fatalf("want params %v, got %v",
+					tc.wantParams, params)
+			}
+			for key, value := range tc.wantParams {
+				if params[key] != value {
+					t.Errorf("want %s=%q, got %q",
+						key, value, params[key])
+				}
+			}
+		})
+	}
+}
@@ -18,7 +18,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	command "github.com/release-argus/Argus/commands"
 	svcstatus "github.com/release-argus/Argus/service/status"
@@ -66,11 +68,16 @@ func (r *RequireDefaults) CheckValues(prefix string) (errs error) {
 
 // Require for version to be considered valid.
 type Require struct {
-	Status       *svcstatus.Status `yaml:"-" json:"-"`                                             // Service Status
-	RegexContent string            `yaml:"regex_content,omitempty" json:"regex_content,omitempty"` // "abc-[a-z]+-{{ version }}_amd64.deb" This regex must exist in the body of the URL to trigger new version actions
-	RegexVersion string            `yaml:"regex_version,omitempty" json:"regex_version,omitempty"` // "v*[0-9.]+" The version found must match this release to trigger new version actions
-	Command      command.Command   `yaml:"command,omitempty" json:"command,omitempty"`             // Require Command to pass
-	Docker       *DockerCheck      `yaml:"docker,omitempty" json:"docker,omitempty"`               // Docker image tag requirements
+	Status            *svcstatus.Status `yaml:"-" json:"-"`                                                         // Service Status
+	RegexContent      string            `yaml:"regex_content,omitempty" json:"regex_content,omitempty"`             // "abc-[a-z]+-{{ version }}_amd64.deb" This regex must exist in the body of the URL to trigger new version actions
+	RegexVersion      string            `yaml:"regex_version,omitempty" json:"regex_version,omitempty"`             // "v*[0-9.]+" The version found must match this release to trigger new version actions
+	RegexNotesInclude string            `yaml:"regex_notes_include,omitempty" json:"regex_notes_include,omitempty"` // "(?i)stable" This regex must exist in the release notes to trigger new version actions
+	RegexNotesExclude string            `yaml:"regex_notes_exclude,omitempty" json:"regex_notes_exclude,omitempty"` // "(?i)do not use" This regex mustn't exist in the release notes to trigger new version actions
+	Command           command.Command   `yaml:"command,omitempty" json:"command,omitempty"`                         // Require Command to pass
+	Docker            *DockerCheck      `yaml:"docker,omitempty" json:"docker,omitempty"`                           // Docker image tag requirements
+	MinAge            string            `yaml:"min_age,omitempty" json:"min_age,omitempty"`                         // "48h" The version must have been visible for this long to trigger new version actions
+	Assets            *AssetsRequire    `yaml:"assets,omitempty" json:"assets,omitempty"`                           // Release asset requirements
+	Verify            *VerifyRequire    `yaml:"verify,omitempty" json:"verify,omitempty"`                           // Checksum/signature verification of a release asset
 }
 
 // String returns a string representation of the Require.
@@ -94,6 +101,16 @@ func (r *Require) Init(status *svcstatus.Status, defaults *RequireDefaults) {
 	}
 }
 
+// GetMinAge returns how long a version must have been visible for before it triggers new version actions.
+func (r *Require) GetMinAge() time.Duration {
+	if r == nil {
+		return 0
+	}
+
+	d, _ := time.ParseDuration(r.MinAge)
+	return d
+}
+
 // CheckValues of the Require option.
 func (r *Require) CheckValues(prefix string) (errs error) {
 	if r == nil {
@@ -123,6 +140,26 @@ func (r *Require) CheckValues(prefix string) (errs error) {
 		}
 	}
 
+	// Notes RegEx
+	if r.RegexNotesInclude != "" {
+		if !util.CheckTemplate(r.RegexNotesInclude) {
+			errs = fmt.Errorf("%s%s  regex_notes_include: %q <invalid> (didn't pass templating)\\",
+				util.ErrorToString(errs), prefix, r.RegexNotesInclude)
+		} else if _, err := regexp.Compile(r.RegexNotesInclude); err != nil {
+			errs = fmt.Errorf("%s%s  regex_notes_include: %q <invalid> (Invalid RegEx)\\",
+				util.ErrorToString(errs), prefix, r.RegexNotesInclude)
+		}
+	}
+	if r.RegexNotesExclude != "" {
+		if !util.CheckTemplate(r.RegexNotesExclude) {
+			errs = fmt.Errorf("%s%s  regex_notes_exclude: %q <invalid> (didn't pass templating)\\",
+				util.ErrorToString(errs), prefix, r.RegexNotesExclude)
+		} else if _, err := regexp.Compile(r.RegexNotesExclude); err != nil {
+			errs = fmt.Errorf("%s%s  regex_notes_exclude: %q <invalid> (Invalid RegEx)\\",
+				util.ErrorToString(errs), prefix, r.RegexNotesExclude)
+		}
+	}
+
 	for i := range r.Command {
 		if !util.CheckTemplate(r.Command[i]) {
 			errs = fmt.Errorf("%s%s  command: %v (%q) <invalid> (didn't pass templating)\\",
@@ -136,6 +173,41 @@ func (r *Require) CheckValues(prefix string) (errs error) {
 			util.ErrorToString(errs), prefix, err)
 	}
 
+	// Assets - Remove if empty
+	if r.Assets != nil && *r.Assets == (AssetsRequire{}) {
+		r.Assets = nil
+	}
+	if err := r.Assets.CheckValues(prefix + "  "); err != nil {
+		errs = fmt.Errorf("%s%s  assets:\\%w",
+			util.ErrorToString(errs), prefix, err)
+	}
+
+	// Verify - Remove if empty
+	if r.Verify != nil {
+		if r.Verify.Signature != nil && *r.Verify.Signature == (VerifySignature{}) {
+			r.Verify.Signature = nil
+		}
+		if *r.Verify == (VerifyRequire{}) {
+			r.Verify = nil
+		}
+	}
+	if err := r.Verify.CheckValues(prefix + "  "); err != nil {
+		errs = fmt.Errorf("%s%s  verify:\\%w",
+			util.ErrorToString(errs), prefix, err)
+	}
+
+	// Min Age
+	if r.MinAge != "" {
+		// Default to seconds when an integer is provided
+		if _, err := strconv.Atoi(r.MinAge); err == nil {
+			r.MinAge += "s"
+		}
+		if d, err := time.ParseDuration(r.MinAge); err != nil || d < 0 {
+			errs = fmt.Errorf("%s%s  min_age: %q <invalid> (Use 'AhBmCs' duration format)\\",
+				util.ErrorToString(errs), prefix, r.MinAge)
+		}
+	}
+
 	if errs != nil {
 		errs = fmt.Errorf("%srequire:\\%s",
 			prefix, util.ErrorToString(errs))
@@ -173,10 +245,36 @@ func RequireFromStr(jsonStr *string, previous *Require, logFrom *util.LogFrom) (
 		if !util.Contains(jsonKeys, "regex_version") {
 			require.RegexVersion = previous.RegexVersion
 		}
+		if !util.Contains(jsonKeys, "regex_notes_include") {
+			require.RegexNotesInclude = previous.RegexNotesInclude
+		}
+		if !util.Contains(jsonKeys, "regex_notes_exclude") {
+			require.RegexNotesExclude = previous.RegexNotesExclude
+		}
 
 		if !util.Contains(jsonKeys, "command") {
 			require.Command = previous.Command
 		}
+		if !util.Contains(jsonKeys, "min_age") {
+			require.MinAge = previous.MinAge
+		}
+		if !util.Contains(jsonKeys, "assets") {
+			require.Assets = previous.Assets
+			// Default the Assets params that haven't been changed
+		} else if previous.Assets != nil && require.Assets != nil {
+			if !util.Contains(jsonKeys, "assets.name") {
+				require.Assets.Name = previous.Assets.Name
+			}
+			if !util.Contains(jsonKeys, "assets.min_count") {
+				require.Assets.MinCount = previous.Assets.MinCount
+			}
+			if !util.Contains(jsonKeys, "assets.min_size") {
+				require.Assets.MinSize = previous.Assets.MinSize
+			}
+		}
+		if !util.Contains(jsonKeys, "verify") {
+			require.Verify = previous.Verify
+		}
 
 		// Default the Docker params
 		if previous.Docker != nil {
@@ -197,6 +295,18 @@ func RequireFromStr(jsonStr *string, previous *Require, logFrom *util.LogFrom) (
 				if !util.Contains(jsonKeys, "docker.tag") {
 					require.Docker.Tag = previous.Docker.Tag
 				}
+				if !util.Contains(jsonKeys, "docker.platforms") {
+					require.Docker.Platforms = previous.Docker.Platforms
+				}
+				if !util.Contains(jsonKeys, "docker.registry") {
+					require.Docker.Registry = previous.Docker.Registry
+				}
+				if !util.Contains(jsonKeys, "docker.ca_file") {
+					require.Docker.CAFile = previous.Docker.CAFile
+				}
+				if !util.Contains(jsonKeys, "docker.allow_invalid_certs") {
+					require.Docker.AllowInvalidCerts = previous.Docker.AllowInvalidCerts
+				}
 				if !util.Contains(jsonKeys, "docker.username") {
 					require.Docker.Username = previous.Docker.Username
 					sameDockerImageAndCredentials++
@@ -188,6 +188,21 @@ func TestRequire_CheckValues(t *testing.T) {
 				`^require:$`,
 				`^  regex_version: .* <invalid>`},
 		},
+		"valid regex_notes_include/regex_notes_exclude": {
+			require: &Require{
+				RegexNotesInclude: "(?i)stable",
+				RegexNotesExclude: "(?i)do not use|{{ version }} is broken"},
+			errRegex: []string{`^$`},
+		},
+		"invalid regex_notes_include/regex_notes_exclude": {
+			require: &Require{
+				RegexNotesInclude: "[0-",
+				RegexNotesExclude: "{{ version }"},
+			errRegex: []string{
+				`^require:$`,
+				`^  regex_notes_include: "\[0-" <invalid> \(Invalid RegEx\)$`,
+				`^  regex_notes_exclude: "{{ version }" <invalid> \(didn't pass templating\)$`},
+		},
 		"valid command": {
 			require: &Require{
 				Command: []string{
@@ -220,6 +235,57 @@ func TestRequire_CheckValues(t *testing.T) {
 				`^  docker:$`,
 				`^    type: .* <invalid>`},
 		},
+		"valid min_age": {
+			require: &Require{
+				MinAge: "48h"},
+			errRegex: []string{`^$`},
+		},
+		"invalid min_age": {
+			require: &Require{
+				MinAge: "2d"},
+			errRegex: []string{
+				`^require:$`,
+				`^  min_age: "2d" <invalid>`},
+		},
+		"negative min_age": {
+			require: &Require{
+				MinAge: "-1h"},
+			errRegex: []string{
+				`^require:$`,
+				`^  min_age: "-1h" <invalid>`},
+		},
+		"empty assets": {
+			require: &Require{
+				Assets: &AssetsRequire{}},
+			errRegex: []string{`^$`},
+		},
+		"invalid assets": {
+			require: &Require{
+				Assets: &AssetsRequire{MinSize: "big"}},
+			errRegex: []string{
+				`^require:$`,
+				`^  assets:$`,
+				`^    min_size: "big" <invalid>`},
+		},
+		"empty verify": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Signature: &VerifySignature{}}},
+			errRegex: []string{`^$`},
+		},
+		"invalid verify": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset: "linux-amd64$",
+					Signature: &VerifySignature{
+						Type: "minisign"}}},
+			errRegex: []string{
+				`^require:$`,
+				`^  verify:$`,
+				`^    checksums: <required>`,
+				`^    signature:$`,
+				`^      public_key: <required>`},
+		},
 		"all possible errors": {
 			require: &Require{
 				RegexContent: "[0-",
@@ -264,6 +330,45 @@ func TestRequire_CheckValues(t *testing.T) {
 	}
 }
 
+func TestRequire_GetMinAge(t *testing.T) {
+	// GIVEN a Require
+	tests := map[string]struct {
+		require *Require
+		want    time.Duration
+	}{
+		"nil": {
+			require: nil,
+			want:    0},
+		"empty": {
+			require: &Require{},
+			want:    0},
+		"duration": {
+			require: &Require{MinAge: "1h30m"},
+			want:    90 * time.Minute},
+		"integer seconds after CheckValues": {
+			require: &Require{MinAge: "30"},
+			want:    30 * time.Second},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.require.CheckValues("")
+
+			// WHEN GetMinAge is called on it
+			got := tc.require.GetMinAge()
+
+			// THEN the duration is returned
+			if got != tc.want {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestRequire_FromStr(t *testing.T) {
 	// GIVEN a JSON string and a Require to use as defaults
 	dflt := testRequire()
@@ -372,6 +477,25 @@ func TestRequire_FromStr(t *testing.T) {
 				RegexVersion: "foo",
 				Command:      []string{}},
 		},
+		"Assets changing MinCount keeps default Name/MinSize": {
+			jsonStr: stringPtr(`{
+				"assets": {
+					"min_count": 2}}`),
+			dflt: &Require{
+				Assets: &AssetsRequire{Name: "linux", MinCount: 1, MinSize: "1MB"}},
+			want: &Require{
+				Assets: &AssetsRequire{Name: "linux", MinCount: 2, MinSize: "1MB"}},
+		},
+		"No Assets JSON uses default": {
+			jsonStr: stringPtr(`{
+				"min_age": "1h"}`),
+			dflt: &Require{
+				MinAge: "2h",
+				Assets: &AssetsRequire{Name: "linux"}},
+			want: &Require{
+				MinAge: "1h",
+				Assets: &AssetsRequire{Name: "linux"}},
+		},
 		"Only Docker.Type sent": {
 			jsonStr: stringPtr(`{
 				"docker": {
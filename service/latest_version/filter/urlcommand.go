@@ -20,7 +20,10 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/andybalholm/cascadia"
+	"github.com/antchfx/xpath"
 	"github.com/release-argus/Argus/util"
+	"golang.org/x/net/html"
 )
 
 // URLCommandSlice to be used to filter version from the URL Content.
@@ -36,12 +39,16 @@ func (s *URLCommandSlice) String() (str string) {
 
 // URLCommand is a command to be ran to filter version from the URL body.
 type URLCommand struct {
-	Type  string  `yaml:"type" json:"type"`                       // regex/replace/split
-	Regex *string `yaml:"regex,omitempty" json:"regex,omitempty"` // regex: regexp.MustCompile(Regex)
-	Index int     `yaml:"index,omitempty" json:"index,omitempty"` // regex/split: re.FindAllString(URL_content, -1)[Index]  /  strings.Split("text")[Index]
-	Text  *string `yaml:"text,omitempty" json:"text,omitempty"`   // split: strings.Split(tgtString, "Text")
-	New   *string `yaml:"new,omitempty" json:"new,omitempty"`     // replace: strings.ReplaceAll(tgtString, "Old", "New")
-	Old   *string `yaml:"old,omitempty" json:"old,omitempty"`     // replace: strings.ReplaceAll(tgtString, "Old", "New")
+	Type      string  `yaml:"type" json:"type"`                               // css/json/regex/replace/split/xpath
+	Regex     *string `yaml:"regex,omitempty" json:"regex,omitempty"`         // regex: regexp.MustCompile(Regex)
+	Index     int     `yaml:"index,omitempty" json:"index,omitempty"`         // css/json/regex/split/xpath: matches[Index]  /  util.GetValuesFromJSON(URL_content, Path)[Index]  /  re.FindAllString(URL_content, -1)[Index]  /  strings.Split("text")[Index]
+	Path      *string `yaml:"path,omitempty" json:"path,omitempty"`           // json: "foo.bar[0].version" / "releases[-1].tag" / "assets[*].name"  -  xpath: "//div[@class='release']/h2" / "//a[contains(@href,'/tag/')]/@href"
+	Selector  *string `yaml:"selector,omitempty" json:"selector,omitempty"`   // css: "div.release > h2" / "a[href*='/tag/']"
+	Attribute *string `yaml:"attribute,omitempty" json:"attribute,omitempty"` // css/xpath: attribute of the matched elements to use (default = their text)
+	All       bool    `yaml:"all,omitempty" json:"all,omitempty"`             // css/json/regex/split/xpath: every match is a candidate version (rather than just the one at Index)
+	Text      *string `yaml:"text,omitempty" json:"text,omitempty"`           // split: strings.Split(tgtString, "Text")
+	New       *string `yaml:"new,omitempty" json:"new,omitempty"`             // replace: strings.ReplaceAll(tgtString, "Old", "New")
+	Old       *string `yaml:"old,omitempty" json:"old,omitempty"`             // replace: strings.ReplaceAll(tgtString, "Old", "New")
 }
 
 // String returns a string representation of the URLCommand.
@@ -76,6 +83,8 @@ func (s *URLCommandSlice) UnmarshalYAML(unmarshal func(interface{}) error) (err
 }
 
 // Run all of the URLCommand(s) in this URLCommandSlice.
+//
+// Commands with `all` give their first match.
 func (s *URLCommandSlice) Run(text string, logFrom util.LogFrom) (string, error) {
 	if s == nil {
 		return text, nil
@@ -92,53 +101,119 @@ func (s *URLCommandSlice) Run(text string, logFrom util.LogFrom) (string, error)
 	return text, nil
 }
 
-// run this URLCommand on `text`
+// RunAll of the URLCommand(s) in this URLCommandSlice, returning every candidate version.
+//
+// Commands with `all` give every match as a candidate (rather than the one at their index),
+// and the following commands are ran on each candidate. Candidates that fail a following command are dropped,
+// and an error is only returned when every candidate failed.
+func (s *URLCommandSlice) RunAll(text string, logFrom util.LogFrom) ([]string, error) {
+	if s == nil {
+		return []string{text}, nil
+	}
+
+	logFrom.Secondary = "url_commands"
+	texts := []string{text}
+	for commandIndex := range *s {
+		var (
+			candidates []string
+			err        error
+		)
+		for i := range texts {
+			var matches []string
+			if matches, err = (*s)[commandIndex].candidates(texts[i], &logFrom); err == nil {
+				candidates = append(candidates, matches...)
+			}
+		}
+		if len(candidates) == 0 {
+			return nil, err
+		}
+		texts = candidates
+	}
+
+	// Remove duplicates
+	unique := make([]string, 0, len(texts))
+	for i := range texts {
+		if !util.Contains(unique, texts[i]) {
+			unique = append(unique, texts[i])
+		}
+	}
+	return unique, nil
+}
+
+// run this URLCommand on `text`, returning the first candidate.
 func (c *URLCommand) run(text string, logFrom *util.LogFrom) (string, error) {
-	var err error
-	// Iterate through the commands to filter the text.
-	textBak := text
+	texts, err := c.candidates(text, logFrom)
+	if err != nil {
+		return text, err
+	}
+	return texts[0], nil
+}
+
+// candidates of this URLCommand on `text`.
+//
+// Every match when `all`, otherwise just the one at the index.
+func (c *URLCommand) candidates(text string, logFrom *util.LogFrom) (texts []string, err error) {
 	if jLog.IsLevel("DEBUG") {
 		jLog.Debug(
 			fmt.Sprintf("Looking through:\n%q", text),
 			*logFrom, true)
 	}
 
-	var msg string
+	var msg, query string
 	switch c.Type {
+	case "css":
+		msg = fmt.Sprintf("Selecting %q with index %d", *c.Selector, c.Index)
+		query = *c.Selector
+		texts, err = c.css(text, logFrom)
+	case "json":
+		msg = fmt.Sprintf("Getting %q with index %d", *c.Path, c.Index)
+		query = *c.Path
+		texts, err = c.json(text, logFrom)
 	case "split":
 		msg = fmt.Sprintf("Splitting on %q with index %d", *c.Text, c.Index)
-		text, err = c.split(text, logFrom)
+		query = *c.Text
+		texts, err = c.split(text, logFrom)
 	case "replace":
 		msg = fmt.Sprintf("Replacing %q with %q", *c.Old, *c.New)
-		text = strings.ReplaceAll(text, *c.Old, *c.New)
+		texts = []string{strings.ReplaceAll(text, *c.Old, *c.New)}
 	case "regex":
 		msg = fmt.Sprintf("Regexing %q", *c.Regex)
-		text, err = c.regex(text, logFrom)
+		query = *c.Regex
+		texts, err = c.regex(text, logFrom)
+	case "xpath":
+		msg = fmt.Sprintf("Evaluating %q with index %d", *c.Path, c.Index)
+		query = *c.Path
+		texts, err = c.xpath(text, logFrom)
 	}
 	if err != nil {
-		return textBak, err
+		return
 	}
 
-	msg = fmt.Sprintf("%s\nResolved to %s", msg, text)
+	if c.All {
+		msg = fmt.Sprintf("%s\nResolved to %q", msg, texts)
+	} else if c.Type != "replace" {
+		var match string
+		if match, err = c.elementAtIndex(texts, query, logFrom); err != nil {
+			return nil, err
+		}
+		texts = []string{match}
+		msg = fmt.Sprintf("%s\nResolved to %s", msg, match)
+	} else {
+		msg = fmt.Sprintf("%s\nResolved to %s", msg, texts[0])
+	}
 	if jLog.IsLevel("DEBUG") {
 		jLog.Debug(msg, *logFrom, true)
 	}
-	return text, err
+	return
 }
 
-// regex `text` with the URLCommand's regex.
-func (c *URLCommand) regex(text string, logFrom *util.LogFrom) (string, error) {
+// regex `text` with the URLCommand's regex, returning the last capture group (or whole match) of every match.
+func (c *URLCommand) regex(text string, logFrom *util.LogFrom) (texts []string, err error) {
 	re := regexp.MustCompile(*c.Regex)
 
-	index := c.Index
-	texts := re.FindAllStringSubmatch(text, -1)
-	// Handle negative indices.
-	if index < 0 {
-		index = len(texts) + c.Index
-	}
-
-	if len(texts) == 0 {
-		err := fmt.Errorf("%s %q didn't return any matches",
+	matches := re.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		err = fmt.Errorf("%s %q didn't return any matches",
 			c.Type, *c.Regex)
 		if len(text) < 20 {
 			err = fmt.Errorf("%w on %q",
@@ -146,49 +221,122 @@ func (c *URLCommand) regex(text string, logFrom *util.LogFrom) (string, error) {
 		}
 		jLog.Warn(err, *logFrom, true)
 
-		return text, err
+		return
 	}
 
-	if (len(texts) - index) < 1 {
-		err := fmt.Errorf("%s (%s) returned %d elements but the index wants element number %d",
-			c.Type, *c.Regex, len(texts), (index + 1))
+	texts = make([]string, len(matches))
+	for i := range matches {
+		texts[i] = matches[i][len(matches[i])-1]
+	}
+	return
+}
+
+// json gets the values at the URLCommand's path in the JSON `text`.
+func (c *URLCommand) json(text string, logFrom *util.LogFrom) (texts []string, err error) {
+	texts, err = util.GetValuesFromJSON([]byte(text), *c.Path)
+	if err != nil {
+		err = fmt.Errorf("%s %w",
+			c.Type, err)
 		jLog.Warn(err, *logFrom, true)
+	}
+	return
+}
 
-		return text, err
+// css selects the elements matching the URLCommand's selector in the HTML `text`
+// and returns their text (or attribute).
+func (c *URLCommand) css(text string, logFrom *util.LogFrom) ([]string, error) {
+	root, err := parseHTML(text)
+	if err == nil {
+		var selector cascadia.SelectorGroup
+		if selector, err = compileCSS(*c.Selector); err == nil {
+			return c.elementValues(selectCSS(root, selector), nil, *c.Selector, logFrom)
+		}
 	}
 
-	return texts[index][len(texts[index])-1], nil
+	err = fmt.Errorf("%s %w",
+		c.Type, err)
+	jLog.Warn(err, *logFrom, true)
+	return nil, err
 }
 
-// split `text` with the URLCommand's text amd return the index specified.
-func (c *URLCommand) split(text string, logFrom *util.LogFrom) (string, error) {
-	texts := strings.Split(text, *c.Text)
+// xpath evaluates the URLCommand's path on the HTML/XML `text`
+// and returns the text (or attribute) of the nodes selected.
+func (c *URLCommand) xpath(text string, logFrom *util.LogFrom) ([]string, error) {
+	root, err := parseHTML(text)
+	if err == nil {
+		var expression *xpath.Expr
+		if expression, err = compileXPath(*c.Path); err == nil {
+			nodes, values := evaluateXPath(root, expression)
+			return c.elementValues(nodes, values, *c.Path, logFrom)
+		}
+	}
 
-	if len(texts) == 1 {
-		err := fmt.Errorf("%s didn't find any %q to split on",
-			c.Type, *c.Text)
+	err = fmt.Errorf("%s %w",
+		c.Type, err)
+	jLog.Warn(err, *logFrom, true)
+	return nil, err
+}
+
+// elementValues returns `values` (when non-nil), or the text/attribute of `nodes`.
+func (c *URLCommand) elementValues(
+	nodes []*html.Node,
+	values []string,
+	query string,
+	logFrom *util.LogFrom,
+) ([]string, error) {
+	if values == nil {
+		attribute := util.DefaultIfNil(c.Attribute)
+		for _, node := range nodes {
+			if value, exists := nodeValue(node, attribute); exists {
+				values = append(values, value)
+			}
+		}
+	}
+
+	if len(values) == 0 {
+		err := fmt.Errorf("%s %q didn't return any matches",
+			c.Type, query)
 		jLog.Warn(err, *logFrom, true)
 
-		return text, err
+		return nil, err
 	}
 
+	return values, nil
+}
+
+// elementAtIndex returns the element of `texts` at the URLCommand's index (negative indices count back from the end).
+func (c *URLCommand) elementAtIndex(texts []string, query string, logFrom *util.LogFrom) (string, error) {
 	index := c.Index
 	// Handle negative indices.
 	if index < 0 {
 		index = len(texts) + index
 	}
 
-	if (len(texts) - index) < 1 {
+	if index < 0 || (len(texts)-index) < 1 {
 		err := fmt.Errorf("%s (%s) returned %d elements but the index wants element number %d",
-			c.Type, *c.Text, len(texts), (index + 1))
+			c.Type, query, len(texts), (index + 1))
 		jLog.Warn(err, *logFrom, true)
 
-		return text, err
+		return "", err
 	}
 
 	return texts[index], nil
 }
 
+// split `text` with the URLCommand's text.
+func (c *URLCommand) split(text string, logFrom *util.LogFrom) (texts []string, err error) {
+	texts = strings.Split(text, *c.Text)
+
+	if len(texts) == 1 {
+		err = fmt.Errorf("%s didn't find any %q to split on",
+			c.Type, *c.Text)
+		jLog.Warn(err, *logFrom, true)
+
+		return nil, err
+	}
+	return
+}
+
 // CheckValues of the URLCommand(s) in the URLCommandSlice.
 func (s *URLCommandSlice) CheckValues(prefix string) (errs error) {
 	if s == nil {
@@ -214,6 +362,22 @@ func (c *URLCommand) CheckValues(prefix string) (errs error) {
 	validType := true
 
 	switch c.Type {
+	case "css":
+		if c.Selector == nil {
+			errs = fmt.Errorf("%s%sselector: <required> (CSS selector of the elements, e.g. 'div.release > h2')\\",
+				util.ErrorToString(errs), prefix)
+		} else if _, err := compileCSS(*c.Selector); err != nil {
+			errs = fmt.Errorf("%s%sselector: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, *c.Selector, err)
+		}
+	case "json":
+		if c.Path == nil {
+			errs = fmt.Errorf("%s%spath: <required> (path to the value, e.g. 'releases[0].version')\\",
+				util.ErrorToString(errs), prefix)
+		} else if err := util.CheckJSONPath(*c.Path); err != nil {
+			errs = fmt.Errorf("%s%spath: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, *c.Path, err)
+		}
 	case "regex":
 		if c.Regex == nil {
 			errs = fmt.Errorf("%s%sregex: <required> (regex to use)\\",
@@ -239,9 +403,17 @@ func (c *URLCommand) CheckValues(prefix string) (errs error) {
 			errs = fmt.Errorf("%s%stext: <required> (text to split on)\\",
 				util.ErrorToString(errs), prefix)
 		}
+	case "xpath":
+		if c.Path == nil {
+			errs = fmt.Errorf("%s%spath: <required> (XPath of the elements, e.g. '//div[@class=\"release\"]/h2')\\",
+				util.ErrorToString(errs), prefix)
+		} else if _, err := compileXPath(*c.Path); err != nil {
+			errs = fmt.Errorf("%s%spath: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, *c.Path, err)
+		}
 	default:
 		validType = false
-		errs = fmt.Errorf("%s%stype: %q <invalid> is not a valid url_command (regex/replace/split)\\",
+		errs = fmt.Errorf("%s%stype: %q <invalid> is not a valid url_command (css/json/regex/replace/split/xpath)\\",
 			util.ErrorToString(errs), prefix, c.Type)
 	}
 
@@ -198,6 +198,10 @@ func TestLogInit(t *testing.T) {
 func TestURLCommandSlice_Run(t *testing.T) {
 	// GIVEN a URLCommandSlice
 	testText := "abc123-def456"
+	testHTML := `<html><body>
+		<div class="release" data-date="2023-02-01"><h2>v1.2.0</h2><a href="/releases/tag/v1.2.0">notes</a></div>
+		<div class="release" data-date="2023-01-01"><h2>v1.1.0</h2><a href="/releases/tag/v1.1.0">notes</a></div>
+	</body></html>`
 	tests := map[string]struct {
 		slice    *URLCommandSlice
 		text     string
@@ -270,6 +274,113 @@ func TestURLCommandSlice_Run(t *testing.T) {
 			errRegex: `split .* returned \d elements but the index wants element number \d`,
 			want:     testText,
 		},
+		"json": {
+			slice: &URLCommandSlice{
+				{Type: "json", Path: stringPtr("releases[-1].version")}},
+			text:     `{"releases":[{"version":"1.0.0"},{"version":"1.1.0"}]}`,
+			errRegex: "^$",
+			want:     "1.1.0",
+		},
+		"json wildcard with index": {
+			slice: &URLCommandSlice{
+				{Type: "json", Path: stringPtr("releases[*].version"), Index: -2}},
+			text:     `{"releases":[{"version":"1.0.0"},{"version":"1.1.0"},{"version":"1.2.0"}]}`,
+			errRegex: "^$",
+			want:     "1.1.0",
+		},
+		"json path not found": {
+			slice: &URLCommandSlice{
+				{Type: "json", Path: stringPtr("releases[0].tag")}},
+			text:     `{"releases":[{"version":"1.0.0"}]}`,
+			errRegex: `json "releases\[0\].tag" could not be found. Failed at "tag"`,
+			want:     `{"releases":[{"version":"1.0.0"}]}`,
+		},
+		"json index out of bounds": {
+			slice: &URLCommandSlice{
+				{Type: "json", Path: stringPtr("releases[*].version"), Index: 1}},
+			text:     `{"releases":[{"version":"1.0.0"}]}`,
+			errRegex: `json .* returned 1 elements but the index wants element number 2`,
+			want:     `{"releases":[{"version":"1.0.0"}]}`,
+		},
+		"json then regex": {
+			slice: &URLCommandSlice{
+				{Type: "json", Path: stringPtr("name")},
+				{Type: "regex", Regex: stringPtr("v([0-9.]+)")}},
+			text:     `{"name":"Release v1.2.3"}`,
+			errRegex: "^$",
+			want:     "1.2.3",
+		},
+		"css": {
+			slice: &URLCommandSlice{
+				{Type: "css", Selector: stringPtr("div.release > h2")}},
+			text:     testHTML,
+			errRegex: "^$",
+			want:     "v1.2.0",
+		},
+		"css with attribute and negative index": {
+			slice: &URLCommandSlice{
+				{Type: "css", Selector: stringPtr("a[href*='/tag/']"), Attribute: stringPtr("href"), Index: -1}},
+			text:     testHTML,
+			errRegex: "^$",
+			want:     "/releases/tag/v1.1.0",
+		},
+		"css doesn't match": {
+			slice: &URLCommandSlice{
+				{Type: "css", Selector: stringPtr("table td")}},
+			text:     testHTML,
+			errRegex: `css "table td" didn't return any matches`,
+			want:     testHTML,
+		},
+		"css attribute not on the elements": {
+			slice: &URLCommandSlice{
+				{Type: "css", Selector: stringPtr("h2"), Attribute: stringPtr("href")}},
+			text:     testHTML,
+			errRegex: `css "h2" didn't return any matches`,
+			want:     testHTML,
+		},
+		"css index out of bounds": {
+			slice: &URLCommandSlice{
+				{Type: "css", Selector: stringPtr("h2"), Index: 2}},
+			text:     testHTML,
+			errRegex: `css \(h2\) returned 2 elements but the index wants element number 3`,
+			want:     testHTML,
+		},
+		"xpath": {
+			slice: &URLCommandSlice{
+				{Type: "xpath", Path: stringPtr("//div[@class='release'][last()]/h2")}},
+			text:     testHTML,
+			errRegex: "^$",
+			want:     "v1.1.0",
+		},
+		"xpath attribute step": {
+			slice: &URLCommandSlice{
+				{Type: "xpath", Path: stringPtr("//a[contains(@href,'/tag/')]/@href")}},
+			text:     testHTML,
+			errRegex: "^$",
+			want:     "/releases/tag/v1.2.0",
+		},
+		"xpath with attribute": {
+			slice: &URLCommandSlice{
+				{Type: "xpath", Path: stringPtr("//div[@class='release']"), Attribute: stringPtr("data-date"), Index: 1}},
+			text:     testHTML,
+			errRegex: "^$",
+			want:     "2023-01-01",
+		},
+		"xpath doesn't match": {
+			slice: &URLCommandSlice{
+				{Type: "xpath", Path: stringPtr("//table")}},
+			text:     testHTML,
+			errRegex: `xpath "//table" didn't return any matches`,
+			want:     testHTML,
+		},
+		"css then regex": {
+			slice: &URLCommandSlice{
+				{Type: "css", Selector: stringPtr("h2")},
+				{Type: "regex", Regex: stringPtr("v([0-9.]+)")}},
+			text:     testHTML,
+			errRegex: "^$",
+			want:     "1.2.0",
+		},
 		"all types": {
 			slice: &URLCommandSlice{
 				{Type: "regex", Regex: stringPtr("([a-z]+)[0-9]+"), Index: 1},
@@ -306,6 +417,78 @@ func TestURLCommandSlice_Run(t *testing.T) {
 	}
 }
 
+func TestURLCommandSlice_RunAll(t *testing.T) {
+	// GIVEN a URLCommandSlice
+	testText := "v1.0.0, v1.1.0-beta, v1.1.0, v1.1.0"
+	tests := map[string]struct {
+		slice    *URLCommandSlice
+		want     []string
+		errRegex string
+	}{
+		"nil slice": {
+			slice:    nil,
+			want:     []string{testText},
+			errRegex: "^$"},
+		"without all gives the index": {
+			slice: &URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`v([0-9.]+)`), Index: 1}},
+			want:     []string{"1.1.0"},
+			errRegex: "^$"},
+		"regex all (duplicates removed)": {
+			slice: &URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`v([0-9.]+)`), All: true}},
+			want:     []string{"1.0.0", "1.1.0"},
+			errRegex: "^$"},
+		"split all then commands on each": {
+			slice: &URLCommandSlice{
+				{Type: "split", Text: stringPtr(", "), All: true},
+				{Type: "replace", Old: stringPtr("v"), New: stringPtr("")}},
+			want:     []string{"1.0.0", "1.1.0-beta", "1.1.0"},
+			errRegex: "^$"},
+		"candidates failing a later command are dropped": {
+			slice: &URLCommandSlice{
+				{Type: "split", Text: stringPtr(", "), All: true},
+				{Type: "regex", Regex: stringPtr(`-([a-z]+)$`)}},
+			want:     []string{"beta"},
+			errRegex: "^$"},
+		"every candidate failing a later command": {
+			slice: &URLCommandSlice{
+				{Type: "split", Text: stringPtr(", "), All: true},
+				{Type: "split", Text: stringPtr("_"), Index: 1}},
+			errRegex: `split didn't find any "_" to split on`},
+		"json all": {
+			slice: &URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`.*`)},
+				{Type: "replace", Old: stringPtr(testText), New: stringPtr(`{"tags":[{"name":"1.0"},{"name":"2.0"}]}`)},
+				{Type: "json", Path: stringPtr("tags[*].name"), All: true}},
+			want:     []string{"1.0", "2.0"},
+			errRegex: "^$"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN RunAll is called on it
+			got, err := tc.slice.RunAll(testText, util.LogFrom{})
+
+			// THEN the expected candidates are returned
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestURLCommand_String(t *testing.T) {
 	// GIVEN a URLCommand
 	regex := testURLCommandRegex()
@@ -404,6 +587,51 @@ func TestURLCommandSlice_CheckValues(t *testing.T) {
 				{Type: "split"}},
 			errRegex: []string{`^    text: <required>`},
 		},
+		"valid json": {
+			slice: &URLCommandSlice{
+				{Type: "json", Path: stringPtr("foo[-1].bar")}},
+			errRegex: []string{`^$`},
+		},
+		"undefined json path": {
+			slice: &URLCommandSlice{
+				{Type: "json"}},
+			errRegex: []string{`^    path: <required>`},
+		},
+		"invalid json path": {
+			slice: &URLCommandSlice{
+				{Type: "json", Path: stringPtr("foo[bar]")}},
+			errRegex: []string{`^    path: "foo\[bar\]" <invalid> \(index "bar" is not an integer`},
+		},
+		"valid css": {
+			slice: &URLCommandSlice{
+				{Type: "css", Selector: stringPtr("div.release > h2")}},
+			errRegex: []string{`^$`},
+		},
+		"undefined css selector": {
+			slice: &URLCommandSlice{
+				{Type: "css"}},
+			errRegex: []string{`^    selector: <required>`},
+		},
+		"invalid css selector": {
+			slice: &URLCommandSlice{
+				{Type: "css", Selector: stringPtr("div[")}},
+			errRegex: []string{`^    selector: "div\[" <invalid>`},
+		},
+		"valid xpath": {
+			slice: &URLCommandSlice{
+				{Type: "xpath", Path: stringPtr("//a[contains(@href,'/tag/')]/@href")}},
+			errRegex: []string{`^$`},
+		},
+		"undefined xpath path": {
+			slice: &URLCommandSlice{
+				{Type: "xpath"}},
+			errRegex: []string{`^    path: <required>`},
+		},
+		"invalid xpath path": {
+			slice: &URLCommandSlice{
+				{Type: "xpath", Path: stringPtr("//div[@class='release'")}},
+			errRegex: []string{`^    path: .* <invalid> \(.*invalid token`},
+		},
 		"invalid type": {
 			slice: &URLCommandSlice{
 				{Type: "something"}},
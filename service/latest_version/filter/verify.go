@@ -0,0 +1,405 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// maxVerifyFileSize is the largest checksum/signature file that will be downloaded.
+const maxVerifyFileSize = 1 << 20
+
+// verifyDownloadTimeout is the longest that the download of a require.verify file may take.
+const verifyDownloadTimeout = 10 * time.Minute
+
+// VerifySignatureTypes are the supported types of `require.verify.signature`.
+var VerifySignatureTypes = []string{"minisign", "openpgp"}
+
+// bsdChecksumRegex matches a BSD-style checksum line, e.g. "SHA256 (argus.tar.gz) = abc...".
+var bsdChecksumRegex = regexp.MustCompile(`^[A-Za-z0-9-]+ \((.+)\) = ([0-9A-Fa-f]+)$`)
+
+// VerifyRequire will verify a release asset against a checksums asset,
+// and optionally that checksums asset against a detached signature.
+type VerifyRequire struct {
+	Asset     string           `yaml:"asset,omitempty" json:"asset,omitempty"`         // "argus-{{ version }}.linux-amd64$" RegEx of the asset to verify
+	Checksums string           `yaml:"checksums,omitempty" json:"checksums,omitempty"` // "SHA256SUMS$" RegEx of the asset holding the checksums
+	Signature *VerifySignature `yaml:"signature,omitempty" json:"signature,omitempty"` // Detached signature of the checksums asset
+}
+
+// VerifySignature is a detached signature of the checksums asset.
+type VerifySignature struct {
+	Type      string `yaml:"type,omitempty" json:"type,omitempty"`             // "minisign"/"openpgp"
+	Asset     string `yaml:"asset,omitempty" json:"asset,omitempty"`           // "SHA256SUMS\.minisig$" RegEx of the asset holding the signature
+	PublicKey string `yaml:"public_key,omitempty" json:"public_key,omitempty"` // minisign - "RWQ..." public key
+	Keyring   string `yaml:"keyring,omitempty" json:"keyring,omitempty"`       // openpgp - "/keys/release.gpg" path to the (binary or armored) keyring
+}
+
+// AssetDownload is how the assets of a release are downloaded for the require.verify.
+type AssetDownload struct {
+	Client  *http.Client                                                                // Client of the Lookup (e.g. allowing invalid certs)
+	Request func(ctx context.Context, asset *github_types.Asset) (*http.Request, error) // Request of an asset, with the credentials of the Lookup
+}
+
+// String returns a string representation of the VerifyRequire.
+func (v *VerifyRequire) String() (str string) {
+	if v != nil {
+		str = util.ToYAMLString(v, "")
+	}
+	return
+}
+
+// CheckValues of the VerifyRequire.
+func (v *VerifyRequire) CheckValues(prefix string) (errs error) {
+	if v == nil {
+		return
+	}
+
+	// Asset
+	if v.Asset == "" {
+		errs = fmt.Errorf("%s%s  asset: <required> (RegEx of the asset to verify)\\",
+			util.ErrorToString(errs), prefix)
+	} else if err := checkTemplatedRegex(v.Asset); err != "" {
+		errs = fmt.Errorf("%s%s  asset: %q <invalid> (%s)\\",
+			util.ErrorToString(errs), prefix, v.Asset, err)
+	}
+
+	// Checksums
+	if v.Checksums == "" {
+		errs = fmt.Errorf("%s%s  checksums: <required> (RegEx of the asset holding the checksums)\\",
+			util.ErrorToString(errs), prefix)
+	} else if err := checkTemplatedRegex(v.Checksums); err != "" {
+		errs = fmt.Errorf("%s%s  checksums: %q <invalid> (%s)\\",
+			util.ErrorToString(errs), prefix, v.Checksums, err)
+	}
+
+	// Signature
+	if err := v.Signature.CheckValues(prefix + "  "); err != nil {
+		errs = fmt.Errorf("%s%s  signature:\\%w",
+			util.ErrorToString(errs), prefix, err)
+	}
+
+	return
+}
+
+// CheckValues of the VerifySignature.
+func (s *VerifySignature) CheckValues(prefix string) (errs error) {
+	if s == nil {
+		return
+	}
+
+	// Asset
+	if s.Asset == "" {
+		errs = fmt.Errorf("%s%s  asset: <required> (RegEx of the asset holding the signature)\\",
+			util.ErrorToString(errs), prefix)
+	} else if err := checkTemplatedRegex(s.Asset); err != "" {
+		errs = fmt.Errorf("%s%s  asset: %q <invalid> (%s)\\",
+			util.ErrorToString(errs), prefix, s.Asset, err)
+	}
+
+	switch s.Type {
+	case "minisign":
+		if s.PublicKey == "" {
+			errs = fmt.Errorf("%s%s  public_key: <required> (minisign public key)\\",
+				util.ErrorToString(errs), prefix)
+		} else if _, err := parseMinisignPublicKey(s.PublicKey); err != nil {
+			errs = fmt.Errorf("%s%s  public_key: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, s.PublicKey, err)
+		}
+	case "openpgp":
+		if s.Keyring == "" {
+			errs = fmt.Errorf("%s%s  keyring: <required> (path to the OpenPGP keyring)\\",
+				util.ErrorToString(errs), prefix)
+		}
+	case "":
+		errs = fmt.Errorf("%s%s  type: <required> (supported types = %s)\\",
+			util.ErrorToString(errs), prefix, VerifySignatureTypes)
+	default:
+		errs = fmt.Errorf("%s%s  type: %q <invalid> (supported types = %s)\\",
+			util.ErrorToString(errs), prefix, s.Type, VerifySignatureTypes)
+	}
+
+	return
+}
+
+// checkTemplatedRegex returns why `regex` is invalid, or "" if it's valid.
+func checkTemplatedRegex(regex string) string {
+	if !util.CheckTemplate(regex) {
+		return "didn't pass templating"
+	}
+	if _, err := regexp.Compile(regex); err != nil {
+		return "Invalid RegEx"
+	}
+	return ""
+}
+
+// VerifyCheck downloads the require.verify asset and checksums (and signature) of `version` with `download`,
+// erroring if the checksum (or signature) doesn't match.
+func (r *Require) VerifyCheck(
+	ctx context.Context,
+	download AssetDownload,
+	version string,
+	assets []github_types.Asset,
+	logFrom *util.LogFrom,
+) error {
+	if r == nil || r.Verify == nil ||
+		// Already verified this version
+		version == r.Status.LatestVersion() {
+		return nil
+	}
+
+	if err := r.Verify.verify(ctx, download, version, assets); err != nil {
+		err = fmt.Errorf("require.verify failed for version %q - %w", version, err)
+		r.Status.RegexMissContent()
+		jLog.Warn(err, *logFrom, true)
+		return err
+	}
+
+	jLog.Info(
+		fmt.Sprintf("Verified the checksum of version %q", version),
+		*logFrom, true)
+	return nil
+}
+
+// verify the asset/checksums/signature of `version`.
+func (v *VerifyRequire) verify(ctx context.Context, download AssetDownload, version string, assets []github_types.Asset) error {
+	serviceInfo := util.ServiceInfo{LatestVersion: version}
+
+	asset, err := findAsset(v.Asset, assets, serviceInfo)
+	if err != nil {
+		return err
+	}
+	checksumsAsset, err := findAsset(v.Checksums, assets, serviceInfo)
+	if err != nil {
+		return err
+	}
+	checksums, err := download.file(ctx, checksumsAsset)
+	if err != nil {
+		return err
+	}
+
+	// Signature of the checksums
+	if v.Signature != nil {
+		signatureAsset, err := findAsset(v.Signature.Asset, assets, serviceInfo)
+		if err != nil {
+			return err
+		}
+		signature, err := download.file(ctx, signatureAsset)
+		if err != nil {
+			return err
+		}
+		if err := v.Signature.verify(checksums, signature); err != nil {
+			return fmt.Errorf("%s signature of %q: %w",
+				v.Signature.Type, checksumsAsset.Name, err)
+		}
+	}
+
+	// Checksum of the asset
+	want, err := findChecksum(checksums, asset.Name)
+	if err != nil {
+		return fmt.Errorf("%q: %w", checksumsAsset.Name, err)
+	}
+	var hasher hash.Hash
+	switch len(want) {
+	case sha256.Size * 2:
+		hasher = sha256.New()
+	case sha512.Size * 2:
+		hasher = sha512.New()
+	default:
+		return fmt.Errorf("%q: unsupported checksum %q for %q (want SHA256/SHA512)",
+			checksumsAsset.Name, want, asset.Name)
+	}
+	if err := download.copy(ctx, asset, hasher, -1); err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(hasher.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch for %q - got %s, want %s",
+			asset.Name, got, want)
+	}
+
+	return nil
+}
+
+// verify that `signature` is a valid signature of `message`.
+func (s *VerifySignature) verify(message []byte, signature []byte) error {
+	switch s.Type {
+	case "minisign":
+		return verifyMinisign(s.PublicKey, message, signature)
+	case "openpgp":
+		return verifyOpenPGP(s.Keyring, message, signature)
+	default:
+		return fmt.Errorf("unsupported signature type %q", s.Type)
+	}
+}
+
+// verifyOpenPGP verifies the (binary or armored) `signature` of `message` against the keys in `keyring`.
+func verifyOpenPGP(keyring string, message []byte, signature []byte) error {
+	keys, err := readOpenPGPKeyring(keyring)
+	if err != nil {
+		return err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN")) {
+		_, err = openpgp.CheckArmoredDetachedSignature(keys, bytes.NewReader(message), bytes.NewReader(signature), nil)
+	} else {
+		_, err = openpgp.CheckDetachedSignature(keys, bytes.NewReader(message), bytes.NewReader(signature), nil)
+	}
+	return err //nolint:wrapcheck
+}
+
+// readOpenPGPKeyring reads the (binary or armored) OpenPGP keyring at `path`.
+func readOpenPGPKeyring(path string) (openpgp.EntityList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	var keys openpgp.EntityList
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN")) {
+		keys, err = openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+	} else {
+		keys, err = openpgp.ReadKeyRing(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("keyring %q: %w", path, err)
+	}
+	return keys, nil
+}
+
+// findAsset returns the first of `assets` whose name matches the templated `regex`.
+func findAsset(regex string, assets []github_types.Asset, serviceInfo util.ServiceInfo) (*github_types.Asset, error) {
+	regex = util.TemplateString(regex, serviceInfo)
+	re, err := regexp.Compile(regex)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	for i := range assets {
+		if re.MatchString(assets[i].Name) {
+			return &assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no asset matching %q", regex)
+}
+
+// findChecksum returns the (lowercase hex) checksum of `name` in the SHA256SUMS-style `checksums`.
+//
+// Supports GNU ("<hash>  <name>"/"<hash> *<name>"), BSD ("SHA256 (<name>) = <hash>")
+// and single-file ("<hash>") formats.
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var checksum, file string
+		if match := bsdChecksumRegex.FindStringSubmatch(line); match != nil {
+			file, checksum = match[1], match[2]
+		} else {
+			fields := strings.Fields(line)
+			checksum = fields[0]
+			if len(fields) > 1 {
+				file = strings.TrimPrefix(strings.TrimPrefix(fields[len(fields)-1], "*"), "./")
+			}
+		}
+
+		// Single-file checksums don't (always) contain the name
+		if file == name || file == "" {
+			if _, err := hex.DecodeString(checksum); err != nil {
+				return "", fmt.Errorf("invalid checksum %q for %q", checksum, name)
+			}
+			return strings.ToLower(checksum), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %q", name)
+}
+
+// file returns the contents of the (checksum/signature) `asset`.
+func (d AssetDownload) file(ctx context.Context, asset *github_types.Asset) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.copy(ctx, asset, &buf, maxVerifyFileSize); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// copy `asset` into `w`, erroring if it's larger than `limit` bytes (when `limit` >= 0).
+func (d AssetDownload) copy(ctx context.Context, asset *github_types.Asset, w io.Writer, limit int64) error {
+	ctx, cancel := context.WithTimeout(ctx, verifyDownloadTimeout)
+	defer cancel()
+
+	req, err := d.request(ctx, asset)
+	if err != nil {
+		return err
+	}
+	client := d.Client
+	if client == nil {
+		client = &http.Client{}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("download of %q failed: %w", asset.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download of %q failed: %s", asset.Name, resp.Status)
+	}
+
+	var body io.Reader = resp.Body
+	if limit >= 0 {
+		// Read one more byte than the limit to detect files that are too large
+		body = io.LimitReader(resp.Body, limit+1)
+	}
+	n, err := io.Copy(w, body)
+	if err != nil {
+		return fmt.Errorf("download of %q failed: %w", asset.Name, err)
+	}
+	if limit >= 0 && n > limit {
+		return fmt.Errorf("download of %q failed: larger than %d bytes", asset.Name, limit)
+	}
+	return nil
+}
+
+// request returns the request to download `asset`
+// (of its browser_download_url, or API url, without credentials if there's no Request).
+func (d AssetDownload) request(ctx context.Context, asset *github_types.Asset) (*http.Request, error) {
+	if d.Request != nil {
+		return d.Request(ctx, asset)
+	}
+
+	url := util.FirstNonDefault(asset.BrowserDownloadURL, asset.URL)
+	if url == "" {
+		return nil, fmt.Errorf("no download URL for %q", asset.Name)
+	}
+	return http.NewRequestWithContext(ctx, http.MethodGet, url, nil) //nolint:wrapcheck
+}
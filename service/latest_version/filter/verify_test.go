@@ -0,0 +1,551 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	svcstatus "github.com/release-argus/Argus/service/status"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestVerifyRequire_CheckValues(t *testing.T) {
+	// GIVEN a VerifyRequire
+	_, publicKey := testMinisignKey(1)
+	tests := map[string]struct {
+		verify   *VerifyRequire
+		errRegex []string
+	}{
+		"nil": {
+			verify:   nil,
+			errRegex: []string{`^$`}},
+		"valid without a signature": {
+			verify: &VerifyRequire{
+				Asset:     `argus-{{ version }}\.linux-amd64$`,
+				Checksums: `SHA256SUMS$`},
+			errRegex: []string{`^$`}},
+		"valid minisign signature": {
+			verify: &VerifyRequire{
+				Asset:     `linux-amd64$`,
+				Checksums: `SHA256SUMS$`,
+				Signature: &VerifySignature{
+					Type:      "minisign",
+					Asset:     `SHA256SUMS\.minisig$`,
+					PublicKey: publicKey}},
+			errRegex: []string{`^$`}},
+		"valid openpgp signature": {
+			verify: &VerifyRequire{
+				Asset:     `linux-amd64$`,
+				Checksums: `SHA256SUMS$`,
+				Signature: &VerifySignature{
+					Type:    "openpgp",
+					Asset:   `SHA256SUMS\.asc$`,
+					Keyring: "/keys/release.gpg"}},
+			errRegex: []string{`^$`}},
+		"missing asset and checksums": {
+			verify: &VerifyRequire{
+				Signature: &VerifySignature{
+					Type:    "openpgp",
+					Asset:   `SHA256SUMS\.asc$`,
+					Keyring: "/keys/release.gpg"}},
+			errRegex: []string{
+				`^  asset: <required>`,
+				`^  checksums: <required>`}},
+		"invalid asset regex and checksums templating": {
+			verify: &VerifyRequire{
+				Asset:     `linux-[amd64$`,
+				Checksums: `{{ version }`},
+			errRegex: []string{
+				`^  asset: "linux-\[amd64\$" <invalid> \(Invalid RegEx\)$`,
+				`^  checksums: "{{ version }" <invalid> \(didn't pass templating\)$`}},
+		"invalid signature type": {
+			verify: &VerifyRequire{
+				Asset:     `linux-amd64$`,
+				Checksums: `SHA256SUMS$`,
+				Signature: &VerifySignature{
+					Type:  "foo",
+					Asset: `SHA256SUMS\.sig$`}},
+			errRegex: []string{
+				`^  signature:$`,
+				`^    type: "foo" <invalid> \(supported types = \[minisign openpgp\]\)$`}},
+		"missing signature type/asset": {
+			verify: &VerifyRequire{
+				Asset:     `linux-amd64$`,
+				Checksums: `SHA256SUMS$`,
+				Signature: &VerifySignature{
+					PublicKey: publicKey}},
+			errRegex: []string{
+				`^  signature:$`,
+				`^    asset: <required>`,
+				`^    type: <required>`}},
+		"minisign without a public key": {
+			verify: &VerifyRequire{
+				Asset:     `linux-amd64$`,
+				Checksums: `SHA256SUMS$`,
+				Signature: &VerifySignature{
+					Type:  "minisign",
+					Asset: `SHA256SUMS\.minisig$`}},
+			errRegex: []string{
+				`^    public_key: <required>`}},
+		"minisign with an invalid public key": {
+			verify: &VerifyRequire{
+				Asset:     `linux-amd64$`,
+				Checksums: `SHA256SUMS$`,
+				Signature: &VerifySignature{
+					Type:      "minisign",
+					Asset:     `SHA256SUMS\.minisig$`,
+					PublicKey: "RWQfoo"}},
+			errRegex: []string{
+				`^    public_key: "RWQfoo" <invalid> \(not a minisign public key\)$`}},
+		"openpgp without a keyring": {
+			verify: &VerifyRequire{
+				Asset:     `linux-amd64$`,
+				Checksums: `SHA256SUMS$`,
+				Signature: &VerifySignature{
+					Type:  "openpgp",
+					Asset: `SHA256SUMS\.asc$`}},
+			errRegex: []string{
+				`^    keyring: <required>`}},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckValues is called on it
+			err := tc.verify.CheckValues("")
+
+			// THEN the err is what we expect
+			e := util.ErrorToString(err)
+			lines := strings.Split(e, `\`)
+			for i := range tc.errRegex {
+				re := regexp.MustCompile(tc.errRegex[i])
+				found := false
+				for j := range lines {
+					if re.MatchString(lines[j]) {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Fatalf("want match for: %q\ngot:  %q",
+						tc.errRegex[i], strings.ReplaceAll(e, `\`, "\n"))
+				}
+			}
+		})
+	}
+}
+
+func TestFindChecksum(t *testing.T) {
+	// GIVEN a checksums file
+	tests := map[string]struct {
+		checksums string
+		name      string
+		want      string
+		errRegex  string
+	}{
+		"GNU text mode": {
+			checksums: "aaaa  argus-1.2.3.darwin-amd64\nBBBB  argus-1.2.3.linux-amd64\n",
+			name:      "argus-1.2.3.linux-amd64",
+			want:      "bbbb",
+			errRegex:  `^$`},
+		"GNU binary mode": {
+			checksums: "aaaa *argus-1.2.3.linux-amd64\n",
+			name:      "argus-1.2.3.linux-amd64",
+			want:      "aaaa",
+			errRegex:  `^$`},
+		"relative path": {
+			checksums: "aaaa  ./argus-1.2.3.linux-amd64\n",
+			name:      "argus-1.2.3.linux-amd64",
+			want:      "aaaa",
+			errRegex:  `^$`},
+		"BSD style": {
+			checksums: "SHA256 (argus-1.2.3.darwin-amd64) = aaaa\nSHA256 (argus 1.2.3.linux-amd64) = cccc\n",
+			name:      "argus 1.2.3.linux-amd64",
+			want:      "cccc",
+			errRegex:  `^$`},
+		"single-file": {
+			checksums: "dddd\n",
+			name:      "argus-1.2.3.linux-amd64",
+			want:      "dddd",
+			errRegex:  `^$`},
+		"comments and blank lines": {
+			checksums: "# checksums\n\naaaa  argus-1.2.3.linux-amd64\n",
+			name:      "argus-1.2.3.linux-amd64",
+			want:      "aaaa",
+			errRegex:  `^$`},
+		"not listed": {
+			checksums: "aaaa  argus-1.2.3.darwin-amd64\n",
+			name:      "argus-1.2.3.linux-amd64",
+			errRegex:  `^no checksum for "argus-1.2.3.linux-amd64"$`},
+		"not hex": {
+			checksums: "zzzz  argus-1.2.3.linux-amd64\n",
+			name:      "argus-1.2.3.linux-amd64",
+			errRegex:  `^invalid checksum "zzzz"`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN findChecksum is called on it
+			got, err := findChecksum([]byte(tc.checksums), tc.name)
+
+			// THEN the checksum of the file is returned
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if got != tc.want {
+				t.Errorf("want %q, got %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+// testOpenPGPKey returns an OpenPGP key, writing its public key to a (binary, or armored) keyring file.
+func testOpenPGPKey(t *testing.T, armored bool) (*openpgp.Entity, string) {
+	entity, err := openpgp.NewEntity("Argus", "", "argus@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to generate an OpenPGP key: %v", err)
+	}
+
+	var keyring bytes.Buffer
+	if armored {
+		w, _ := armor.Encode(&keyring, openpgp.PublicKeyType, nil)
+		entity.Serialize(w)
+		w.Close()
+	} else {
+		entity.Serialize(&keyring)
+	}
+	path := filepath.Join(t.TempDir(), "keyring.gpg")
+	os.WriteFile(path, keyring.Bytes(), 0600)
+	return entity, path
+}
+
+// testOpenPGPSign returns a detached (binary, or armored) signature of `message` by `entity`.
+func testOpenPGPSign(entity *openpgp.Entity, message []byte, armored bool) []byte {
+	var signature bytes.Buffer
+	if armored {
+		openpgp.ArmoredDetachSign(&signature, entity, bytes.NewReader(message), nil)
+	} else {
+		openpgp.DetachSign(&signature, entity, bytes.NewReader(message), nil)
+	}
+	return signature.Bytes()
+}
+
+func TestVerifyOpenPGP(t *testing.T) {
+	// GIVEN OpenPGP keyrings and signatures of a message
+	message := []byte("abc123  argus-1.2.3.linux-amd64\n")
+	entity, keyring := testOpenPGPKey(t, false)
+	armoredEntity, armoredKeyring := testOpenPGPKey(t, true)
+	otherEntity, _ := testOpenPGPKey(t, false)
+	notKeyring := filepath.Join(t.TempDir(), "not-a-keyring.gpg")
+	os.WriteFile(notKeyring, []byte("foo"), 0600)
+	tests := map[string]struct {
+		keyring   string
+		message   []byte
+		signature []byte
+		errRegex  string
+	}{
+		"binary signature": {
+			keyring:   keyring,
+			signature: testOpenPGPSign(entity, message, false),
+			errRegex:  `^$`},
+		"armored signature": {
+			keyring:   keyring,
+			signature: testOpenPGPSign(entity, message, true),
+			errRegex:  `^$`},
+		"armored keyring": {
+			keyring:   armoredKeyring,
+			signature: testOpenPGPSign(armoredEntity, message, false),
+			errRegex:  `^$`},
+		"modified message": {
+			keyring:   keyring,
+			message:   []byte("abc124  argus-1.2.3.linux-amd64\n"),
+			signature: testOpenPGPSign(entity, message, true),
+			errRegex:  `signature`},
+		"signed by a different key": {
+			keyring:   keyring,
+			signature: testOpenPGPSign(otherEntity, message, true),
+			errRegex:  `signature made by unknown entity`},
+		"malformed signature": {
+			keyring:   keyring,
+			signature: []byte("not a signature"),
+			errRegex:  `.+`},
+		"missing keyring": {
+			keyring:   filepath.Join(t.TempDir(), "missing.gpg"),
+			signature: testOpenPGPSign(entity, message, true),
+			errRegex:  `no such file`},
+		"invalid keyring": {
+			keyring:   notKeyring,
+			signature: testOpenPGPSign(entity, message, true),
+			errRegex:  `^keyring ".*not-a-keyring.gpg": `},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.message == nil {
+				tc.message = message
+			}
+
+			// WHEN verifyOpenPGP is called
+			err := verifyOpenPGP(tc.keyring, tc.message, tc.signature)
+
+			// THEN the signature is verified
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestRequire_VerifyCheck(t *testing.T) {
+	// GIVEN a Require and the assets of a release, served over HTTP
+	binary := []byte("#!/bin/sh\necho argus\n")
+	privateKey, publicKey := testMinisignKey(1)
+	otherPrivateKey, _ := testMinisignKey(2)
+	entity, keyring := testOpenPGPKey(t, false)
+	sha256sums := []byte(fmt.Sprintf("%x  argus-1.2.3.linux-amd64\n%x  argus-1.2.3.linux-arm64\n",
+		sha256.Sum256(binary), sha256.Sum256([]byte("other"))))
+	files := map[string][]byte{
+		"/argus-1.2.3.linux-amd64":   binary,
+		"/argus-1.2.3.linux-arm64":   []byte("tampered"),
+		"/SHA256SUMS":                sha256sums,
+		"/SHA256SUMS.minisig":        testMinisignSign(privateKey, 1, sha256sums, true),
+		"/SHA256SUMS.other.minisig":  testMinisignSign(otherPrivateKey, 1, sha256sums, true),
+		"/SHA512SUMS":                []byte(fmt.Sprintf("%x  argus-1.2.3.linux-amd64\n", sha512.Sum512(binary))),
+		"/MD5SUMS":                   []byte("0123456789abcdef0123456789abcdef  argus-1.2.3.linux-amd64\n"),
+		"/SHA256SUMS.asc":            testOpenPGPSign(entity, sha256sums, true),
+		"/SHA256SUMS.invalid.asc":    []byte("not a signature"),
+		"/SHA256SUMS.private":        sha256sums,
+		"/argus-1.2.3.linux-riscv64": binary}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only downloadable with credentials
+		if strings.HasSuffix(r.URL.Path, ".private") && r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if file, ok := files[r.URL.Path]; ok {
+			w.Write(file)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	var assets []github_types.Asset
+	for _, name := range util.SortedKeys(files) {
+		assets = append(assets, github_types.Asset{
+			Name:               strings.TrimPrefix(name, "/"),
+			BrowserDownloadURL: server.URL + name})
+	}
+	// Asset that isn't served
+	assets = append(assets, github_types.Asset{
+		Name:               "SHA256SUMS.missing",
+		BrowserDownloadURL: server.URL + "/SHA256SUMS.missing"})
+	withCredentials := func(ctx context.Context, asset *github_types.Asset) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.BrowserDownloadURL, nil)
+		if err == nil {
+			req.Header.Set("Authorization", "token secret")
+		}
+		return req, err
+	}
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := map[string]struct {
+		require       *Require
+		ctx           context.Context
+		download      AssetDownload
+		latestVersion string
+		errRegex      string
+	}{
+		"nil require": {
+			require:  nil,
+			errRegex: `^$`},
+		"no verify require": {
+			require:  &Require{},
+			errRegex: `^$`},
+		"sha256 checksum": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `argus-{{ version }}\.linux-amd64$`,
+					Checksums: `^SHA256SUMS$`}},
+			errRegex: `^$`},
+		"sha512 checksum": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-amd64$`,
+					Checksums: `^SHA512SUMS$`}},
+			errRegex: `^$`},
+		"sha256 checksum and minisign signature": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-amd64$`,
+					Checksums: `^SHA256SUMS$`,
+					Signature: &VerifySignature{
+						Type:      "minisign",
+						Asset:     `^SHA256SUMS\.minisig$`,
+						PublicKey: publicKey}}},
+			errRegex: `^$`},
+		"checksum mismatch": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-arm64$`,
+					Checksums: `^SHA256SUMS$`}},
+			errRegex: `^require.verify failed for version "1.2.3" - checksum mismatch for "argus-1.2.3.linux-arm64"`},
+		"asset not in checksums": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-riscv64$`,
+					Checksums: `^SHA256SUMS$`}},
+			errRegex: `"SHA256SUMS": no checksum for "argus-1.2.3.linux-riscv64"$`},
+		"unsupported checksum": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-amd64$`,
+					Checksums: `^MD5SUMS$`}},
+			errRegex: `unsupported checksum "0123456789abcdef0123456789abcdef"`},
+		"no matching asset": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `windows`,
+					Checksums: `^SHA256SUMS$`}},
+			errRegex: `no asset matching "windows"$`},
+		"checksums download failed": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-amd64$`,
+					Checksums: `^SHA256SUMS\.missing$`}},
+			errRegex: `download of "SHA256SUMS.missing" failed: 404 Not Found$`},
+		"minisign signature by a different key": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-amd64$`,
+					Checksums: `^SHA256SUMS$`,
+					Signature: &VerifySignature{
+						Type:      "minisign",
+						Asset:     `^SHA256SUMS\.other\.minisig$`,
+						PublicKey: publicKey}}},
+			errRegex: `minisign signature of "SHA256SUMS": invalid signature$`},
+		"sha256 checksum and openpgp signature": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-amd64$`,
+					Checksums: `^SHA256SUMS$`,
+					Signature: &VerifySignature{
+						Type:    "openpgp",
+						Asset:   `^SHA256SUMS\.asc$`,
+						Keyring: keyring}}},
+			errRegex: `^$`},
+		"openpgp signature invalid": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-amd64$`,
+					Checksums: `^SHA256SUMS$`,
+					Signature: &VerifySignature{
+						Type:    "openpgp",
+						Asset:   `^SHA256SUMS\.invalid\.asc$`,
+						Keyring: keyring}}},
+			errRegex: `openpgp signature of "SHA256SUMS": `},
+		"download without credentials": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-amd64$`,
+					Checksums: `^SHA256SUMS\.private$`}},
+			errRegex: `download of "SHA256SUMS.private" failed: 404 Not Found$`},
+		"download with the credentials of the lookup": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-amd64$`,
+					Checksums: `^SHA256SUMS\.private$`}},
+			download: AssetDownload{
+				Client:  server.Client(),
+				Request: withCredentials},
+			errRegex: `^$`},
+		"download cancelled": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-amd64$`,
+					Checksums: `^SHA256SUMS$`}},
+			ctx:      cancelled,
+			errRegex: `download of "SHA256SUMS" failed: .*context canceled$`},
+		"already verified the latest version": {
+			require: &Require{
+				Verify: &VerifyRequire{
+					Asset:     `linux-arm64$`,
+					Checksums: `^SHA256SUMS$`}},
+			latestVersion: "1.2.3",
+			errRegex:      `^$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.require != nil {
+				tc.require.Status = &svcstatus.Status{}
+				tc.require.Status.SetLatestVersion(tc.latestVersion, false)
+			}
+
+			if tc.ctx == nil {
+				tc.ctx = context.Background()
+			}
+
+			// WHEN VerifyCheck is called on it
+			err := tc.require.VerifyCheck(tc.ctx, tc.download, "1.2.3", assets, &util.LogFrom{})
+
+			// THEN the err is what we expect
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND a failure is counted like a regex_content miss
+			if err != nil && tc.require.Status.RegexMissesContent() != 1 {
+				t.Errorf("want 1 content miss, got %d",
+					tc.require.Status.RegexMissesContent())
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"strconv"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"golang.org/x/net/html"
+)
+
+// compileXPath compiles the XPath `expression`.
+func compileXPath(expression string) (*xpath.Expr, error) {
+	return xpath.Compile(expression)
+}
+
+// evaluateXPath evaluates the `expression` on `root`, returning the nodes selected,
+// or the value when it evaluates to a string/number/boolean (e.g. 'string(//h2)').
+//
+// Attribute nodes (e.g. '//a/@href') are returned as an element whose text is the attribute value.
+func evaluateXPath(root *html.Node, expression *xpath.Expr) (nodes []*html.Node, values []string) {
+	switch result := expression.Evaluate(htmlquery.CreateXPathNavigator(root)).(type) {
+	case *xpath.NodeIterator:
+		for result.MoveNext() {
+			navigator := result.Current().(*htmlquery.NodeNavigator)
+			if navigator.NodeType() == xpath.AttributeNode {
+				text := &html.Node{Type: html.TextNode, Data: navigator.Value()}
+				nodes = append(nodes, &html.Node{
+					Type:       html.ElementNode,
+					Data:       navigator.LocalName(),
+					FirstChild: text,
+					LastChild:  text})
+				continue
+			}
+			nodes = append(nodes, navigator.Current())
+		}
+	case string:
+		if result != "" {
+			values = []string{result}
+		}
+	case float64:
+		values = []string{strconv.FormatFloat(result, 'f', -1, 64)}
+	case bool:
+		values = []string{strconv.FormatBool(result)}
+	}
+	return
+}
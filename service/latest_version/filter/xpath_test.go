@@ -0,0 +1,128 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package filter
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestEvaluateXPath(t *testing.T) {
+	// GIVEN an XPath expression
+	tests := map[string]struct {
+		path     string
+		want     []string
+		errRegex string
+	}{
+		"absolute": {
+			path: "/html/body/p",
+			want: []string{"v0.0.1"}},
+		"relative to the document": {
+			path: "html/body/p",
+			want: []string{"v0.0.1"}},
+		"descendant": {
+			path: "//div[@id='releases']//h2",
+			want: []string{"v2.0.0-beta.1", "v1.2.0", "v1.1.0"}},
+		"position is per parent": {
+			path: "//div/a[1]/@href",
+			want: []string{"/dl/v2.0.0-beta.1.tar.gz", "/dl/v1.2.0.zip"}},
+		"last()": {
+			path: "//div[@class='release'][last()]/h2",
+			want: []string{"v1.1.0"}},
+		"last()-1": {
+			path: "//div[@id='releases']/div[last()-1]/h2/text()",
+			want: []string{"v1.2.0"}},
+		"contains and not": {
+			path: "//div[contains(@class,'release') and not(contains(@data-tag,'beta'))]/@data-tag",
+			want: []string{"v1.2.0", "v1.1.0"}},
+		"starts-with or ends-with": {
+			path: "//a[starts-with(@href,'/dl/v2') or ends-with(@href,'.zip')]/@href",
+			want: []string{"/dl/v2.0.0-beta.1.tar.gz", "/dl/v1.2.0.zip"}},
+		"child element comparison": {
+			path: "//div[h2='v1.2.0']/@data-tag",
+			want: []string{"v1.2.0"}},
+		"attribute exists": {
+			path: "//div[@data-tag][a]/h2",
+			want: []string{"v2.0.0-beta.1", "v1.2.0"}},
+		"text() comparison": {
+			path: "//h2[text()!='v1.2.0']",
+			want: []string{"v2.0.0-beta.1", "v1.1.0"}},
+		"parent": {
+			path: "//a[@href='/dl/v1.2.0.zip']/../h2",
+			want: []string{"v1.2.0"}},
+		"wildcard": {
+			path: "/html/body/*/@class",
+			want: []string{"footer"}},
+		"no matches": {
+			path: "//table",
+			want: nil},
+		"function of the nodes": {
+			path: "count(//div[@class='release'])",
+			want: []string{"2"}},
+		"string of the first node": {
+			path: "string(//h2)",
+			want: []string{"v2.0.0-beta.1"}},
+		"count() in a predicate": {
+			path: "//div[count(a)=2]/h2",
+			want: []string{"v1.2.0"}},
+		"missing bracket": {
+			path:     "//div[@id='releases'",
+			errRegex: `.+`},
+		"unknown function": {
+			path:     "//div[unknown(a)]",
+			errRegex: `.+`},
+		"empty": {
+			path:     "",
+			errRegex: `.+`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			root, _ := parseHTML(testSelectHTML)
+
+			// WHEN the expression is compiled
+			expression, err := compileXPath(tc.path)
+
+			// THEN it err's when expected
+			if tc.errRegex != "" || err != nil {
+				e := util.ErrorToString(err)
+				re := regexp.MustCompile(tc.errRegex)
+				match := re.MatchString(e)
+				if !match || tc.errRegex == "" {
+					t.Fatalf("want match for %q\nnot: %q",
+						tc.errRegex, e)
+				}
+				return
+			}
+			// AND the expected elements/values are selected
+			nodes, got := evaluateXPath(root, expression)
+			for _, node := range nodes {
+				got = append(got, nodeText(node))
+			}
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -16,8 +16,10 @@ package latestver
 
 import (
 	"fmt"
+	net_url "net/url"
 	"strings"
 
+	"github.com/release-argus/Argus/service/latest_version/filter"
 	"github.com/release-argus/Argus/util"
 )
 
@@ -50,17 +52,132 @@ func (l *Lookup) ServiceURL(ignoreWebURL bool) (serviceURL string) {
 	}
 
 	serviceURL = l.URL
+	switch l.Type {
 	// GitHub service. Get the non-API URL.
-	if l.Type == "github" {
+	case "github":
 		// If it's "owner/repo" rather than a full path.
 		if strings.Count(serviceURL, "/") == 1 {
 			serviceURL = fmt.Sprintf("https://github.com/%s", serviceURL)
 		}
+	// Container image. Get the image page.
+	case "container":
+		switch filter.RegistryType(strings.TrimPrefix(l.GetBaseURL(), "https://")) {
+		case "hub":
+			serviceURL = fmt.Sprintf("https://hub.docker.com/r/%s",
+				strings.Replace(serviceURL, "library/", "_/", 1))
+		case "quay":
+			serviceURL = fmt.Sprintf("https://quay.io/repository/%s", serviceURL)
+		default:
+			serviceURL = fmt.Sprintf("%s/%s", l.GetBaseURL(), serviceURL)
+		}
+	// Gitea/GitLab service. Get the project URL.
+	case "gitea", "gitlab":
+		serviceURL = fmt.Sprintf("%s/%s", l.GetBaseURL(), serviceURL)
+	// Package registry. Get the package page (unless it's a mirror).
+	case "crates", "goproxy", "npm", "pypi":
+		if l.BaseURL != "" {
+			serviceURL = l.GetQueryURL()
+			break
+		}
+		switch l.Type {
+		case "crates":
+			serviceURL = fmt.Sprintf("https://crates.io/crates/%s", serviceURL)
+		case "goproxy":
+			serviceURL = fmt.Sprintf("https://pkg.go.dev/%s", serviceURL)
+		case "npm":
+			serviceURL = fmt.Sprintf("https://www.npmjs.com/package/%s", serviceURL)
+		case "pypi":
+			serviceURL = fmt.Sprintf("https://pypi.org/project/%s", serviceURL)
+		}
 	}
 	return
 }
 
-// Get UsePreRelease will return whether GitHub PreReleases are considered valid for new versions.
+// GetBaseURL of the forge/registry to query, falling back to the public instance for the type.
+func (l *Lookup) GetBaseURL() string {
+	if l.BaseURL != "" {
+		return strings.TrimSuffix(l.BaseURL, "/")
+	}
+
+	switch l.Type {
+	case "container":
+		return "https://registry-1.docker.io"
+	case "crates":
+		return "https://crates.io"
+	case "gitlab":
+		return "https://gitlab.com"
+	case "goproxy":
+		return "https://proxy.golang.org"
+	case "npm":
+		return "https://registry.npmjs.org"
+	case "pypi":
+		return "https://pypi.org"
+	}
+	return ""
+}
+
+// GetUseTags will return whether the tags should be queried rather than the releases.
+func (l *Lookup) GetUseTags() bool {
+	return util.EvalNilPtr(l.UseTags, false)
+}
+
+// GetUseAppVersion will return whether the appVersion of a Helm chart should be tracked rather than the chart version.
+func (l *Lookup) GetUseAppVersion() bool {
+	return util.EvalNilPtr(l.UseAppVersion, false)
+}
+
+// GetFeedElement will return the element of each feed item that the version is taken from.
+func (l *Lookup) GetFeedElement() string {
+	return util.FirstNonDefault(l.FeedElement, "title")
+}
+
+// GetQueryURL will return the API URL to query for this Lookup.
+func (l *Lookup) GetQueryURL() string {
+	switch l.Type {
+	case "container":
+		return fmt.Sprintf("%s/v2/%s/tags/list?n=1000",
+			l.GetBaseURL(), l.URL)
+	case "crates":
+		return fmt.Sprintf("%s/api/v1/crates/%s",
+			l.GetBaseURL(), l.URL)
+	case "gitea":
+		return fmt.Sprintf("%s/api/v1/repos/%s/releases?limit=50",
+			l.GetBaseURL(), l.URL)
+	case "gitlab":
+		endpoint := "releases"
+		if l.GetUseTags() {
+			endpoint = "repository/tags"
+		}
+		return fmt.Sprintf("%s/api/v4/projects/%s/%s?per_page=100",
+			l.GetBaseURL(), net_url.PathEscape(l.URL), endpoint)
+	case "goproxy":
+		return fmt.Sprintf("%s/%s/@v/list",
+			l.GetBaseURL(), goModuleEscape(l.URL))
+	case "git":
+		return gitInfoRefsURL(l.URL)
+	case "helm":
+		return helmIndexURL(l.URL)
+	case "npm":
+		// Scoped packages, e.g. @scope/package -> @scope%2Fpackage
+		return fmt.Sprintf("%s/%s",
+			l.GetBaseURL(), strings.ReplaceAll(l.URL, "/", "%2F"))
+	case "pypi":
+		return fmt.Sprintf("%s/pypi/%s/json",
+			l.GetBaseURL(), l.URL)
+	}
+	return GetURL(l.URL, l.Type)
+}
+
+// GetUseGraphQL will return whether GitHub releases are queried in batched GraphQL requests.
+func (l *Lookup) GetUseGraphQL() bool {
+	return util.EvalNilPtr(util.FirstNonNilPtr(
+		l.UseGraphQL,
+		l.Defaults.UseGraphQL,
+		l.HardDefaults.UseGraphQL),
+		false)
+}
+
+// Get UsePreRelease will return whether GitHub PreReleases (GitLab upcoming releases) are considered valid for new versions.
 func (l *Lookup) GetUsePreRelease() bool {
 	return *util.FirstNonDefault(
 		l.UsePreRelease,
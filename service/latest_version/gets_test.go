@@ -159,6 +159,20 @@ func TestLookup_ServiceURL(t *testing.T) {
 			latestVersion: "",
 			ignoreWebURL:  false,
 		},
+		"gitlab - want project url address": {
+			want:         "https://gitlab.com/group/sub-group/project",
+			serviceType:  "gitlab",
+			url:          "group/sub-group/project",
+			webURL:       "foo",
+			ignoreWebURL: true,
+		},
+		"container - want docker hub image page": {
+			want:         "https://hub.docker.com/r/_/prometheus",
+			serviceType:  "container",
+			url:          "library/prometheus",
+			webURL:       "foo",
+			ignoreWebURL: true,
+		},
 		"url - want query url": {
 			want:         "https://release-argus.io",
 			serviceType:  "url",
@@ -262,3 +276,102 @@ func TestLookup_GetUsePreRelease(t *testing.T) {
 		})
 	}
 }
+
+func TestLookup_GetQueryURL(t *testing.T) {
+	// GIVEN a Lookup
+	tests := map[string]struct {
+		lType   string
+		url     string
+		baseURL string
+		useTags *bool
+		want    string
+	}{
+		"github - owner/repo": {
+			lType: "github",
+			url:   "release-argus/Argus",
+			want:  "https://api.github.com/repos/release-argus/Argus/releases"},
+		"gitlab - releases": {
+			lType: "gitlab",
+			url:   "group/project",
+			want:  "https://gitlab.com/api/v4/projects/group%2Fproject/releases?per_page=100"},
+		"gitlab - tags": {
+			lType:   "gitlab",
+			url:     "group/project",
+			useTags: boolPtr(true),
+			want:    "https://gitlab.com/api/v4/projects/group%2Fproject/repository/tags?per_page=100"},
+		"gitlab - self-hosted": {
+			lType:   "gitlab",
+			url:     "group/sub-group/project",
+			baseURL: "https://gitlab.example.com/",
+			want:    "https://gitlab.example.com/api/v4/projects/group%2Fsub-group%2Fproject/releases?per_page=100"},
+		"gitea": {
+			lType:   "gitea",
+			url:     "owner/repo",
+			baseURL: "https://codeberg.org",
+			want:    "https://codeberg.org/api/v1/repos/owner/repo/releases?limit=50"},
+		"container - docker hub": {
+			lType: "container",
+			url:   "library/prometheus",
+			want:  "https://registry-1.docker.io/v2/library/prometheus/tags/list?n=1000"},
+		"container - ghcr": {
+			lType:   "container",
+			url:     "release-argus/argus",
+			baseURL: "https://ghcr.io",
+			want:    "https://ghcr.io/v2/release-argus/argus/tags/list?n=1000"},
+		"git": {
+			lType: "git",
+			url:   "https://git.example.com/project.git/",
+			want:  "https://git.example.com/project.git/info/refs?service=git-upload-pack"},
+		"helm - repository": {
+			lType: "helm",
+			url:   "https://charts.example.com/stable/",
+			want:  "https://charts.example.com/stable/index.yaml"},
+		"helm - index": {
+			lType: "helm",
+			url:   "https://charts.example.com/stable/index.yaml",
+			want:  "https://charts.example.com/stable/index.yaml"},
+		"crates": {
+			lType: "crates",
+			url:   "serde",
+			want:  "https://crates.io/api/v1/crates/serde"},
+		"goproxy": {
+			lType: "goproxy",
+			url:   "github.com/BurntSushi/toml",
+			want:  "https://proxy.golang.org/github.com/!burnt!sushi/toml/@v/list"},
+		"npm - scoped package": {
+			lType: "npm",
+			url:   "@types/node",
+			want:  "https://registry.npmjs.org/@types%2Fnode"},
+		"pypi - mirror": {
+			lType:   "pypi",
+			url:     "requests",
+			baseURL: "https://pypi.example.com",
+			want:    "https://pypi.example.com/pypi/requests/json"},
+		"url": {
+			lType: "url",
+			url:   "https://release-argus.io",
+			want:  "https://release-argus.io"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := Lookup{
+				Type:    tc.lType,
+				URL:     tc.url,
+				BaseURL: tc.baseURL,
+				UseTags: tc.useTags}
+
+			// WHEN GetQueryURL is called
+			got := lookup.GetQueryURL()
+
+			// THEN the function returns the correct result
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// checkGitRefsBody will check that the body is the ref advertisement of a git repository
+// and convert the tags to Releases.
+//
+// Both the smart-HTTP (pkt-line) and dumb-HTTP (tab-separated) formats of info/refs are accepted.
+// Refs are advertised in lexical order, so the tags are reversed to get the newer versions first.
+func (l *Lookup) checkGitRefsBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	var refs []string
+	if refs, err = gitRefs(*body); err != nil {
+		err = fmt.Errorf("git query for %q failed - %w",
+			l.URL, err)
+		jLog.Error(err, *logFrom, true)
+		return
+	}
+
+	tags := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		tag, isTag := strings.CutPrefix(ref, "refs/tags/")
+		// Skip the peeled (dereferenced) annotated tags
+		if !isTag || strings.HasSuffix(tag, "^{}") {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(tags)))
+	releases = make([]github_types.Release, len(tags))
+	for i := range tags {
+		releases[i] = github_types.Release{TagName: tags[i]}
+	}
+	return
+}
+
+// gitRefs returns the names of the refs in an info/refs body.
+func gitRefs(body []byte) (refs []string, err error) {
+	// Dumb-HTTP, "<sha>\t<ref>" lines
+	if !bytes.HasPrefix(body, []byte("001e# service=")) {
+		for _, line := range strings.Split(string(body), "\n") {
+			if _, ref, found := strings.Cut(line, "\t"); found {
+				refs = append(refs, strings.TrimSpace(ref))
+			}
+		}
+		if len(refs) == 0 && len(bytes.TrimSpace(body)) != 0 {
+			err = fmt.Errorf("body is not a ref advertisement")
+		}
+		return
+	}
+
+	// Smart-HTTP, pkt-lines of "<sha> <ref>[\0<capabilities>]\n"
+	for len(body) != 0 {
+		if len(body) < 4 {
+			err = fmt.Errorf("truncated pkt-line")
+			return
+		}
+		var length int64
+		if length, err = strconv.ParseInt(string(body[:4]), 16, 32); err != nil {
+			err = fmt.Errorf("invalid pkt-line length %q", body[:4])
+			return
+		}
+		// flush-pkt
+		if length == 0 {
+			body = body[4:]
+			continue
+		}
+		if length < 4 || int(length) > len(body) {
+			err = fmt.Errorf("invalid pkt-line length %d", length)
+			return
+		}
+		line := string(body[4:length])
+		body = body[length:]
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		line, _, _ = strings.Cut(line, "\x00")
+		if _, ref, found := strings.Cut(strings.TrimSuffix(line, "\n"), " "); found {
+			refs = append(refs, ref)
+		}
+	}
+	return
+}
+
+// gitInfoRefsURL returns the smart-HTTP ref advertisement URL of the repository `url`.
+func gitInfoRefsURL(url string) string {
+	return strings.TrimSuffix(url, "/") + "/info/refs?service=git-upload-pack"
+}
@@ -0,0 +1,198 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+)
+
+// testGitPktLines returns the smart-HTTP ref advertisement of `refs`.
+func testGitPktLines(refs ...string) string {
+	pktLine := func(line string) string {
+		return fmt.Sprintf("%04x%s", len(line)+4, line)
+	}
+
+	var body strings.Builder
+	body.WriteString(pktLine("# service=git-upload-pack\n"))
+	body.WriteString("0000")
+	sha := strings.Repeat("a", 40)
+	for i, ref := range refs {
+		line := fmt.Sprintf("%s %s", sha, ref)
+		if i == 0 {
+			line += "\x00multi_ack side-band-64k ofs-delta"
+		}
+		body.WriteString(pktLine(line + "\n"))
+	}
+	body.WriteString("0000")
+	return body.String()
+}
+
+func TestLookup_CheckGitRefsBody(t *testing.T) {
+	// GIVEN an info/refs body
+	tests := map[string]struct {
+		body     string
+		want     []string
+		errRegex string
+	}{
+		"smart-http": {
+			body: testGitPktLines(
+				"HEAD",
+				"refs/heads/main",
+				"refs/tags/v1.0.0",
+				"refs/tags/v1.1.0",
+				"refs/tags/v1.1.0^{}"),
+			want:     []string{"v1.1.0", "v1.0.0"},
+			errRegex: `^$`},
+		"dumb-http": {
+			body: strings.Repeat("a", 40) + "\trefs/heads/main\n" +
+				strings.Repeat("b", 40) + "\trefs/tags/2.0.0\n" +
+				strings.Repeat("c", 40) + "\trefs/tags/2.0.0^{}\n",
+			want:     []string{"2.0.0"},
+			errRegex: `^$`},
+		"no tags": {
+			body:     testGitPktLines("HEAD", "refs/heads/main"),
+			want:     []string{},
+			errRegex: `^$`},
+		"not a ref advertisement": {
+			body:     `<html>Not Found</html>`,
+			errRegex: `git query for "[^"]+" failed - body is not a ref advertisement`},
+		"invalid pkt-line": {
+			body:     "001e# service=git-upload-pack\n0000zzzz",
+			errRegex: `invalid pkt-line length "zzzz"`},
+		"truncated pkt-line": {
+			body:     "001e# service=git-upload-pack\n0000ffff",
+			errRegex: `invalid pkt-line length 65535`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			body := []byte(tc.body)
+			lookup := Lookup{
+				Type: "git",
+				URL:  "https://git.example.com/project.git"}
+
+			// WHEN checkGitRefsBody is called on this body
+			releases, err := lookup.checkGitRefsBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the tags are converted to releases
+			if len(releases) != len(tc.want) {
+				t.Fatalf("want %d releases, got %d\n%v",
+					len(tc.want), len(releases), releases)
+			}
+			for i := range tc.want {
+				if releases[i].TagName != tc.want[i] {
+					t.Errorf("release %d - want %q, got %q",
+						i, tc.want[i], releases[i].TagName)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryGit(t *testing.T) {
+	// GIVEN a git Lookup and a git server
+	tests := map[string]struct {
+		path        string
+		username    string
+		accessToken string
+		wantVersion string
+		errRegex    string
+	}{
+		"public repository": {
+			path:        "/project.git",
+			wantVersion: "1.10.0",
+			errRegex:    `^$`},
+		"private repository with credentials": {
+			path:        "/private.git",
+			username:    "user",
+			accessToken: "pass",
+			wantVersion: "1.10.0",
+			errRegex:    `^$`},
+		"private repository without credentials": {
+			path:     "/private.git",
+			errRegex: `git query for "[^"]+/private.git" failed: 401 Unauthorized`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("service") != "git-upload-pack" {
+					http.NotFound(w, r)
+					return
+				}
+				if strings.HasPrefix(r.URL.Path, "/private.git") {
+					if username, password, ok := r.BasicAuth(); !ok || username != "user" || password != "pass" {
+						w.WriteHeader(http.StatusUnauthorized)
+						return
+					}
+				}
+				w.Write([]byte(testGitPktLines(
+					"HEAD",
+					"refs/tags/v1.2.0",
+					"refs/tags/v1.10.0",
+					"refs/tags/v1.9.0")))
+			}))
+			defer server.Close()
+			lookup := testLookup(false, false)
+			lookup.Type = "git"
+			lookup.URL = server.URL + tc.path
+			lookup.Username = tc.username
+			lookup.AccessToken = &tc.accessToken
+			if err := lookup.CheckValues(""); err != nil {
+				t.Fatalf("CheckValues failed: %v", err)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the newest semantic version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantVersion {
+				t.Errorf("want version %q, got %q",
+					tc.wantVersion, got)
+			}
+		})
+	}
+}
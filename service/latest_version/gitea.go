@@ -0,0 +1,43 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"encoding/json"
+	"fmt"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// checkGiteaReleasesBody will check that the body is of the expected API format for a successful query.
+//
+// Gitea/Forgejo releases are in the same format as GitHub releases.
+func (l *Lookup) checkGiteaReleasesBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	// Errors are returned as an object, e.g. {"message":"GetUserByName","url":"..."}
+	if message, isError := apiErrorMessage(body); isError {
+		err = fmt.Errorf("gitea query for %q failed: %s",
+			l.URL, message)
+		jLog.Error(err, *logFrom, true)
+		return
+	}
+
+	if err = json.Unmarshal(*body, &releases); err != nil {
+		err = fmt.Errorf("unmarshal of Gitea API data failed\n%w",
+			err)
+		jLog.Error(err, *logFrom, true)
+	}
+	return
+}
@@ -0,0 +1,163 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/release-argus/Argus/service/latest_version/filter"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestLookup_CheckGiteaReleasesBody(t *testing.T) {
+	// GIVEN a body
+	tests := map[string]struct {
+		body     string
+		want     []string
+		errRegex string
+	}{
+		"api error": {
+			body:     `{"message":"The target couldn't be found.","url":"https://gitea.example.com/api/swagger"}`,
+			errRegex: `gitea query for "[^"]+" failed: The target couldn't be found.`},
+		"invalid json": {
+			body:     `bish bash bosh`,
+			errRegex: `unmarshal .* failed`},
+		"releases": {
+			body: `[
+				{"tag_name":"v1.2.3","assets":[{"id":1,"name":"argus-1.2.3.linux-amd64","browser_download_url":"https://gitea.example.com/attachments/1"}]},
+				{"tag_name":"v1.2.2","prerelease":true}]`,
+			want:     []string{"v1.2.3", "v1.2.2"},
+			errRegex: `^$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			body := []byte(tc.body)
+			lookup := Lookup{
+				Type: "gitea",
+				URL:  "owner/repo"}
+
+			// WHEN checkGiteaReleasesBody is called on this body
+			releases, err := lookup.checkGiteaReleasesBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the releases are parsed
+			if len(releases) != len(tc.want) {
+				t.Fatalf("want %d releases, got %d\n%v",
+					len(tc.want), len(releases), releases)
+			}
+			for i := range tc.want {
+				if releases[i].TagName != tc.want[i] {
+					t.Errorf("release %d - want %q, got %q",
+						i, tc.want[i], releases[i].TagName)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryGitea(t *testing.T) {
+	// GIVEN a Gitea Lookup and a server with releases
+	tests := map[string]struct {
+		usePreRelease       bool
+		accessToken         string
+		requireRegexContent string
+		wantVersion         string
+		errRegex            string
+	}{
+		"newest release": {
+			wantVersion: "1.2.3",
+			errRegex:    `^$`},
+		"pre-release wanted": {
+			usePreRelease: true,
+			wantVersion:   "2.0.0",
+			errRegex:      `^$`},
+		"access token": {
+			accessToken: "secret",
+			wantVersion: "3.0.0",
+			errRegex:    `^$`},
+		"regex_content matches an older release's assets": {
+			requireRegexContent: `argus-{{ version }}\.linux-arm64`,
+			wantVersion:         "1.2.2",
+			errRegex:            `^$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/gitea/api/v1/repos/owner/repo/releases" {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"message":"The target couldn't be found."}`))
+					return
+				}
+				if r.Header.Get("Authorization") == "token secret" {
+					w.Write([]byte(`[{"tag_name":"v3.0.0"}]`))
+					return
+				}
+				w.Write([]byte(`[
+					{"tag_name":"v2.0.0","prerelease":true},
+					{"tag_name":"v1.2.3","assets":[{"id":2,"name":"argus-1.2.3.linux-amd64"}]},
+					{"tag_name":"v1.2.2","assets":[{"id":1,"name":"argus-1.2.2.linux-arm64"}]}]`))
+			}))
+			defer server.Close()
+			lookup := testLookup(false, false)
+			lookup.Type = "gitea"
+			lookup.URL = server.URL + "/gitea/owner/repo/releases"
+			lookup.AccessToken = &tc.accessToken
+			lookup.UsePreRelease = &tc.usePreRelease
+			lookup.Require = &filter.Require{
+				RegexContent: tc.requireRegexContent,
+				Status:       lookup.Status}
+			if err := lookup.CheckValues(""); err != nil {
+				t.Fatalf("CheckValues failed: %v", err)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the newest valid version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantVersion {
+				t.Errorf("want version %q, got %q",
+					tc.wantVersion, got)
+			}
+		})
+	}
+}
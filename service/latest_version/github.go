@@ -21,24 +21,37 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/coreos/go-semver/semver"
 	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	opt "github.com/release-argus/Argus/service/options"
 	"github.com/release-argus/Argus/util"
 )
 
-// filterGitHubReleases will filter releases that fail the URLCommands, aren't semantic (if wanted),
+// filterGitHubReleases will filter the GitHubData releases that fail the URLCommands, aren't semantic (if wanted),
 // or are pre_release's (when they're not wanted). This list will be returned and be sorted descending.
 func (l *Lookup) filterGitHubReleases(
 	logFrom *util.LogFrom,
 ) (filteredReleases []github_types.Release) {
-	semanticVerioning := l.Options.GetSemanticVersioning()
+	filteredReleases = l.filterReleases(l.GitHubData.Releases(), logFrom)
+	return
+}
+
+// filterReleases will filter releases that fail the URLCommands, aren't semantic (if wanted),
+// or are pre_release's (when they're not wanted). This list will be returned and be sorted descending.
+func (l *Lookup) filterReleases(
+	releases []github_types.Release,
+	logFrom *util.LogFrom,
+) (filteredReleases []github_types.Release) {
 	usePreReleases := l.GetUsePreRelease()
 
-	releases := l.GitHubData.Releases()
 	// Make a slice with the same capacity as releases
 	filteredReleases = make([]github_types.Release, 0, len(releases))
 
 	for i := range releases {
+		// Drafts aren't releases yet
+		if releases[i].Draft {
+			continue
+		}
+
 		// If it's a prerelease, and they're not wanted, skip
 		if releases[i].PreRelease && !usePreReleases {
 			continue
@@ -56,37 +69,45 @@ func (l *Lookup) filterGitHubReleases(
 		release := releases[i]
 		release.TagName = tagName
 
-		// If SemVer isn't wanted, add without any sorting
-		if !semanticVerioning {
-			filteredReleases = append(filteredReleases, release)
-			continue
-		}
-
-		// Else, sort the versions
-		semVer, err := semver.NewVersion(tagName)
-		if err != nil {
-			continue
-		}
-		release.SemanticVersion = semVer
-		// If there's no other versions, just add it without insertion sort
-		if len(filteredReleases) == 0 {
-			filteredReleases = append(filteredReleases, release)
-			continue
-		}
-		// Insertion Sort
-		insertionSort(release, &filteredReleases)
+		addRelease(release, &filteredReleases, l.Options)
 	}
 	return
 }
 
+// addRelease will add release to filteredReleases.
+//
+// If semantic versioning is wanted, releases that don't follow the version_scheme are skipped
+// and the rest are insertion sorted (descending). Otherwise, it's appended.
+func addRelease(release github_types.Release, filteredReleases *[]github_types.Release, options *opt.Options) {
+	// If SemVer isn't wanted, add without any sorting
+	if !options.GetSemanticVersioning() {
+		*filteredReleases = append(*filteredReleases, release)
+		return
+	}
+
+	// Else, sort the versions
+	version, err := options.ParseVersion(release.TagName)
+	if err != nil {
+		return
+	}
+	release.SemanticVersion = version
+	// If there's no other versions, just add it without insertion sort
+	if len(*filteredReleases) == 0 {
+		*filteredReleases = append(*filteredReleases, release)
+		return
+	}
+	// Insertion Sort
+	insertionSort(release, filteredReleases)
+}
+
 // insertionSort will do an insertion sort of release on filteredReleases.
 //
-// Every GitHubRelease must be follow SemanticVersioning for this insertion
+// Every GitHubRelease must have a SemanticVersion of the same version_scheme for this insertion
 func insertionSort(release github_types.Release, filteredReleases *[]github_types.Release) {
 	n := len(*filteredReleases)
 	// find the insertion point
 	i := sort.Search(n, func(index int) bool {
-		return (*filteredReleases)[index].SemanticVersion.LessThan(*release.SemanticVersion)
+		return (*filteredReleases)[index].SemanticVersion.LessThan(release.SemanticVersion)
 	})
 
 	// append an empty release to the end of the slice
@@ -21,8 +21,8 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/coreos/go-semver/semver"
 	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	opt "github.com/release-argus/Argus/service/options"
 	"github.com/release-argus/Argus/util"
 )
 
@@ -53,13 +53,13 @@ func TestInsertionSort(t *testing.T) {
 				{TagName: "0.0.0"},
 			}
 			for i := range releases {
-				semVer, _ := semver.NewVersion(releases[i].TagName)
+				semVer, _ := opt.ParseVersion("semver", releases[i].TagName)
 				releases[i].SemanticVersion = semVer
 			}
 
 			// WHEN insertionSort is called with a release
 			release := github_types.Release{TagName: tc.release}
-			semVer, _ := semver.NewVersion(release.TagName)
+			semVer, _ := opt.ParseVersion("semver", release.TagName)
 			release.SemanticVersion = semVer
 			insertionSort(release, &releases)
 
@@ -168,6 +168,14 @@ func TestLookup_FilterGitHubReleases(t *testing.T) {
 				{TagName: "0.0.1"},
 			}, want: []string{"0.99.0", "v0.0.2", "0.0.1"},
 		},
+		"exclude drafts": {
+			usePreReleases: true,
+			releases: []github_types.Release{
+				{TagName: "0.99.0", Draft: true},
+				{TagName: "0.3.0", PreRelease: true, Draft: true},
+				{TagName: "0.0.1"},
+			}, want: []string{"0.0.1"},
+		},
 		"does sort releases": {
 			usePreReleases:     true,
 			semanticVersioning: true,
@@ -0,0 +1,155 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	net_url "net/url"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+// checkGitLabReleasesBody will check that the body is of the expected API format for a successful query
+// and convert the releases/tags to Releases.
+func (l *Lookup) checkGitLabReleasesBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	// Errors are returned as an object, e.g. {"message":"404 Project Not Found"}
+	if message, isError := apiErrorMessage(body); isError {
+		err = fmt.Errorf("gitlab query for %q failed: %s",
+			l.URL, message)
+		jLog.Error(err, *logFrom, true)
+		return
+	}
+
+	// Tags
+	if l.GetUseTags() {
+		var tags []github_types.GitLabTag
+		if err = json.Unmarshal(*body, &tags); err != nil {
+			err = fmt.Errorf("unmarshal of GitLab API data failed\n%w",
+				err)
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+
+		releases = make([]github_types.Release, len(tags))
+		for i := range tags {
+			releases[i] = github_types.Release{TagName: tags[i].Name}
+		}
+		return
+	}
+
+	// Releases
+	var gitlabReleases []github_types.GitLabRelease
+	if err = json.Unmarshal(*body, &gitlabReleases); err != nil {
+		err = fmt.Errorf("unmarshal of GitLab API data failed\n%w",
+			err)
+		jLog.Error(err, *logFrom, true)
+		return
+	}
+
+	releases = make([]github_types.Release, len(gitlabReleases))
+	for i := range gitlabReleases {
+		releases[i] = gitlabReleases[i].Release()
+	}
+	return
+}
+
+// gitLabMaxPages is the most pages of releases/tags that will be requested for a project.
+const gitLabMaxPages = 10
+
+// gitLabPages will follow the pagination of a GitLab releases/tags response,
+// returning the releases/tags of every page as a single body.
+func (l *Lookup) gitLabPages(
+	client *http.Client,
+	req *http.Request,
+	header http.Header,
+	body []byte,
+	logFrom *util.LogFrom,
+) (rawBody []byte, err error) {
+	next := gitLabNextPage(header, req.URL)
+	if next == "" {
+		return body, nil
+	}
+
+	var items []json.RawMessage
+	if err = json.Unmarshal(body, &items); err != nil {
+		// Not a list, so leave it for checkGitLabReleasesBody to report.
+		return body, nil
+	}
+	for page := 1; next != "" && page < gitLabMaxPages; page++ {
+		var pageReq *http.Request
+		pageReq, err = http.NewRequestWithContext(req.Context(), http.MethodGet, next, nil)
+		if err != nil {
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+		pageReq.Header = req.Header.Clone()
+
+		var resp *http.Response
+		resp, err = client.Do(pageReq)
+		if err != nil {
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+		if resp.StatusCode != http.StatusOK {
+			err = fmt.Errorf("gitlab query for %q failed on page %d: %s",
+				l.URL, page+1, resp.Status)
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+
+		var pageItems []json.RawMessage
+		if err = json.Unmarshal(body, &pageItems); err != nil {
+			err = fmt.Errorf("unmarshal of GitLab API data failed\n%w",
+				err)
+			jLog.Error(err, *logFrom, true)
+			return
+		}
+		items = append(items, pageItems...)
+
+		next = gitLabNextPage(resp.Header, pageReq.URL)
+	}
+
+	rawBody, err = json.Marshal(items)
+	jLog.Error(err, *logFrom, err != nil)
+	return
+}
+
+// gitLabNextPage returns the URL of the next page of a GitLab response from the `rel="next"` Link header,
+// falling back to the X-Next-Page header (the Link header is omitted for projects with many releases/tags).
+func gitLabNextPage(header http.Header, from *net_url.URL) string {
+	if next := nextLink(header.Get("Link"), from); next != "" {
+		return next
+	}
+
+	page := header.Get("X-Next-Page")
+	if page == "" {
+		return ""
+	}
+	next := *from
+	query := next.Query()
+	query.Set("page", page)
+	next.RawQuery = query.Encode()
+	return next.String()
+}
@@ -0,0 +1,225 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestLookup_CheckGitLabReleasesBody(t *testing.T) {
+	// GIVEN a body
+	tests := map[string]struct {
+		body     string
+		useTags  bool
+		want     []string
+		errRegex string
+	}{
+		"api error": {
+			body:     `{"message":"404 Project Not Found"}`,
+			errRegex: `gitlab query for "[^"]+" failed: 404 Project Not Found`},
+		"invalid json": {
+			body:     `bish bash bosh`,
+			errRegex: `unmarshal .* failed`},
+		"releases": {
+			body: `[
+				{"tag_name":"v1.2.3","_links":{"self":"https://gitlab.com/group/project/-/releases/v1.2.3"}},
+				{"tag_name":"v1.2.2"}]`,
+			want:     []string{"v1.2.3", "v1.2.2"},
+			errRegex: `^$`},
+		"tags": {
+			body:     `[{"name":"v1.2.3"},{"name":"v1.2.2"},{"name":"v1.2.1"}]`,
+			useTags:  true,
+			want:     []string{"v1.2.3", "v1.2.2", "v1.2.1"},
+			errRegex: `^$`},
+		"no releases": {
+			body:     `[]`,
+			want:     []string{},
+			errRegex: `^$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			body := []byte(tc.body)
+			lookup := Lookup{
+				Type:    "gitlab",
+				URL:     "group/project",
+				UseTags: &tc.useTags}
+
+			// WHEN checkGitLabReleasesBody is called on this body
+			releases, err := lookup.checkGitLabReleasesBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the releases are converted
+			if len(releases) != len(tc.want) {
+				t.Fatalf("want %d releases, got %d\n%v",
+					len(tc.want), len(releases), releases)
+			}
+			for i := range tc.want {
+				if releases[i].TagName != tc.want[i] {
+					t.Errorf("release %d - want %q, got %q",
+						i, tc.want[i], releases[i].TagName)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryGitLab(t *testing.T) {
+	// GIVEN a GitLab Lookup and a server with releases
+	tests := map[string]struct {
+		usePreRelease bool
+		accessToken   string
+		wantVersion   string
+		errRegex      string
+	}{
+		"newest release": {
+			wantVersion: "1.2.3",
+			errRegex:    `^$`},
+		"upcoming release wanted": {
+			usePreRelease: true,
+			wantVersion:   "2.0.0",
+			errRegex:      `^$`},
+		"private token": {
+			accessToken: "secret",
+			wantVersion: "3.0.0",
+			errRegex:    `^$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.EscapedPath() != "/api/v4/projects/group%2Fproject/releases" {
+					w.WriteHeader(http.StatusNotFound)
+					w.Write([]byte(`{"message":"404 Project Not Found"}`))
+					return
+				}
+				if r.Header.Get("PRIVATE-TOKEN") == "secret" {
+					w.Write([]byte(`[{"tag_name":"v3.0.0"}]`))
+					return
+				}
+				w.Write([]byte(`[
+					{"tag_name":"v1.2.2"},
+					{"tag_name":"v2.0.0","upcoming_release":true},
+					{"tag_name":"v1.2.3"}]`))
+			}))
+			defer server.Close()
+			lookup := testLookup(false, false)
+			lookup.Type = "gitlab"
+			lookup.URL = server.URL + "/group/project/-/releases"
+			lookup.AccessToken = &tc.accessToken
+			lookup.UsePreRelease = &tc.usePreRelease
+			if err := lookup.CheckValues(""); err != nil {
+				t.Fatalf("CheckValues failed: %v", err)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the newest version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantVersion {
+				t.Errorf("want version %q, got %q",
+					tc.wantVersion, got)
+			}
+		})
+	}
+}
+
+func TestLookup_QueryGitLabPagination(t *testing.T) {
+	// GIVEN a GitLab project whose first page of releases are all upcoming releases
+	tests := map[string]struct {
+		useLink bool
+	}{
+		"Link header": {
+			useLink: true},
+		"X-Next-Page header": {
+			useLink: false},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var server *httptest.Server
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Query().Get("page") {
+				case "":
+					if tc.useLink {
+						w.Header().Set("Link",
+							fmt.Sprintf(`<%s/api/v4/projects/group%%2Fproject/releases?page=2&per_page=100>; rel="next"`,
+								server.URL))
+					} else {
+						w.Header().Set("X-Next-Page", "2")
+					}
+					w.Write([]byte(`[
+						{"tag_name":"v3.0.0","upcoming_release":true},
+						{"tag_name":"v2.0.0","upcoming_release":true}]`))
+				case "2":
+					w.Write([]byte(`[{"tag_name":"v1.2.3"}]`))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+			lookup := testLookup(false, false)
+			lookup.Type = "gitlab"
+			lookup.URL = server.URL + "/group/project/-/releases"
+			if err := lookup.CheckValues(""); err != nil {
+				t.Fatalf("CheckValues failed: %v", err)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN the release on the next page is found
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := lookup.Status.LatestVersion(); got != "1.2.3" {
+				t.Errorf("want version %q, got %q",
+					"1.2.3", got)
+			}
+		})
+	}
+}
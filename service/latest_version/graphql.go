@@ -0,0 +1,406 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+)
+
+var (
+	// gitHubAssetURL is the REST API URL of a release asset (by owner, name and ID),
+	// which the assets of private repositories are downloaded through.
+	gitHubAssetURL = "https://api.github.com/repos/%s/%s/releases/assets/%d"
+	// gitHubRepoRegex matches the owner and name of a GitHub repository in a github type URL.
+	gitHubRepoRegex = regexp.MustCompile(`^(?:https?://(?:api\.)?github\.com/(?:repos/)?)?([\w.-]+)/([\w.-]+?)(?:\.git|/releases)?/?$`)
+	// gitHubBatches are the repositories queried through the GraphQL API, grouped by access token.
+	gitHubBatches = gitHubBatcher{
+		url:       "https://api.github.com/graphql",
+		batchSize: 50,
+		batches:   map[string]*gitHubBatch{}}
+)
+
+// gitHubGraphQLReleases is the selection of the releases of a repository,
+// with the fields of a REST Release.
+const gitHubGraphQLReleases = `fragment releases on Repository {
+  releases(first: 30, orderBy: {field: CREATED_AT, direction: DESC}) {
+    nodes {
+      tagName
+      name
+      description
+      isDraft
+      isPrerelease
+      publishedAt
+      url
+      author { login url }
+      releaseAssets(first: 100) { nodes { databaseId name downloadUrl size } }
+    }
+  }
+}`
+
+// gitHubBatcher is the GitHub repositories queried through the GraphQL API, grouped by access token.
+type gitHubBatcher struct {
+	url       string                  // Endpoint of the GraphQL API
+	batchSize int                     // Most repositories queried in a single request
+	batches   map[string]*gitHubBatch // Repositories of each access token
+	mutex     sync.Mutex              // Lock for the batches
+}
+
+// gitHubBatch is the repositories queried together with an access token.
+type gitHubBatch struct {
+	url   string                         // Endpoint of the GraphQL API
+	token string                         // Access token to query with
+	repos map[string]*gitHubRepoReleases // Releases of each repository (by lowercase "owner/name")
+	mutex sync.Mutex                     // Lock for the repos (not held whilst querying)
+}
+
+// gitHubRepoReleases is the releases of a repository from the last GraphQL query that included it.
+type gitHubRepoReleases struct {
+	owner, name string                 // Repository
+	releases    []github_types.Release // Releases from the last query
+	err         error                  // Error of the last query
+	queried     time.Time              // Time of the last query
+	requested   time.Time              // Time that a Lookup last wanted these releases
+	maxAge      time.Duration          // Oldest that the releases can be for that Lookup
+	pending     chan struct{}          // Closed when the query in flight for this repository finishes (nil when none)
+}
+
+// gitHubRepo returns the owner and name of the GitHub repository at `url`.
+func gitHubRepo(url string) (owner string, name string, ok bool) {
+	match := gitHubRepoRegex.FindStringSubmatch(url)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// batch returns the gitHubBatch of `token`.
+func (b *gitHubBatcher) batch(token string) *gitHubBatch {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	batch := b.batches[token]
+	if batch == nil {
+		batch = &gitHubBatch{
+			url:   b.url,
+			token: token,
+			repos: map[string]*gitHubRepoReleases{}}
+		b.batches[token] = batch
+	}
+	return batch
+}
+
+// releases of the `owner`/`name` repository that are no older than `maxAge`.
+//
+// When they're older, this repository is queried along with every other repository of this access token
+// that's older than its own maxAge, with up to batchSize repositories per request.
+// A repository that's already being queried waits for that query rather than querying it again.
+func (b *gitHubBatcher) releases(
+	client *http.Client,
+	token string,
+	owner string,
+	name string,
+	maxAge time.Duration,
+) ([]github_types.Release, error) {
+	batch := b.batch(token)
+	batch.mutex.Lock()
+
+	now := time.Now()
+	key := strings.ToLower(owner + "/" + name)
+	repo := batch.repos[key]
+	if repo == nil {
+		repo = &gitHubRepoReleases{
+			owner: owner,
+			name:  name}
+		batch.repos[key] = repo
+	}
+	repo.requested = now
+	repo.maxAge = maxAge
+
+	// Wait for the query of this repository that's in flight.
+	if pending := repo.pending; pending != nil {
+		batch.mutex.Unlock()
+		<-pending
+		batch.mutex.Lock()
+		defer batch.mutex.Unlock()
+		return repo.releases, repo.err
+	}
+
+	if now.Sub(repo.queried) > maxAge {
+		stale := batch.stale(now)
+		batch.mutex.Unlock()
+		for i := 0; i < len(stale); i += b.batchSize {
+			end := i + b.batchSize
+			if end > len(stale) {
+				end = len(stale)
+			}
+			batch.query(client, stale[i:end], now)
+		}
+		batch.mutex.Lock()
+	}
+
+	defer batch.mutex.Unlock()
+	return repo.releases, repo.err
+}
+
+// stale returns the repositories older than their maxAge that aren't being queried,
+// and marks them as being queried.
+//
+// (batch.mutex must be held)
+func (b *gitHubBatch) stale(now time.Time) (stale []*gitHubRepoReleases) {
+	for key, repo := range b.repos {
+		if repo.pending != nil {
+			continue
+		}
+		// Stop querying repositories that are no longer wanted.
+		if now.Sub(repo.requested) > 4*repo.maxAge {
+			delete(b.repos, key)
+			continue
+		}
+		if now.Sub(repo.queried) > repo.maxAge {
+			repo.pending = make(chan struct{})
+			stale = append(stale, repo)
+		}
+	}
+	return
+}
+
+// query the releases of `repos` in a single GraphQL request,
+// and release anything waiting for them.
+func (b *gitHubBatch) query(client *http.Client, repos []*gitHubRepoReleases, now time.Time) {
+	results, err := b.request(client, repos)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for i, repo := range repos {
+		repo.queried = now
+		repo.releases, repo.err = nil, err
+		if err == nil {
+			result := results[fmt.Sprintf("r%d", i)]
+			repo.releases, repo.err = result.releases, result.err
+		}
+		close(repo.pending)
+		repo.pending = nil
+	}
+}
+
+// gitHubGraphQLResult is the releases (or error) of a repository in a GraphQL response.
+type gitHubGraphQLResult struct {
+	releases []github_types.Release
+	err      error
+}
+
+// gitHubGraphQLRelease is the format of a release in a GraphQL response.
+type gitHubGraphQLRelease struct {
+	TagName      string `json:"tagName"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	IsDraft      bool   `json:"isDraft"`
+	IsPrerelease bool   `json:"isPrerelease"`
+	PublishedAt  string `json:"publishedAt"`
+	URL          string `json:"url"`
+	Author       *struct {
+		Login string `json:"login"`
+		URL   string `json:"url"`
+	} `json:"author"`
+	ReleaseAssets struct {
+		Nodes []struct {
+			DatabaseID  uint   `json:"databaseId"`
+			Name        string `json:"name"`
+			DownloadURL string `json:"downloadUrl"`
+			Size        uint64 `json:"size"`
+		} `json:"nodes"`
+	} `json:"releaseAssets"`
+}
+
+// gitHubGraphQLResponse is the format of a response from the GraphQL API.
+type gitHubGraphQLResponse struct {
+	Data map[string]*struct {
+		Releases struct {
+			Nodes []gitHubGraphQLRelease `json:"nodes"`
+		} `json:"releases"`
+	} `json:"data"`
+	Errors []struct {
+		Type    string        `json:"type"`
+		Message string        `json:"message"`
+		Path    []interface{} `json:"path"`
+	} `json:"errors"`
+}
+
+// request the releases of `repos`, returning the result of each by its alias (r0, r1, ...).
+//
+// (b.mutex isn't held, so only the owner/name of the repos are read)
+func (b *gitHubBatch) request(
+	client *http.Client,
+	repos []*gitHubRepoReleases,
+) (map[string]gitHubGraphQLResult, error) {
+	// Alias a repository field for each repo.
+	var params, fields strings.Builder
+	variables := make(map[string]string, 2*len(repos))
+	for i, repo := range repos {
+		fmt.Fprintf(&params, ", $o%d: String!, $n%d: String!", i, i)
+		fmt.Fprintf(&fields, "  r%d: repository(owner: $o%d, name: $n%d) { ...releases }\n", i, i, i)
+		variables[fmt.Sprintf("o%d", i)] = repo.owner
+		variables[fmt.Sprintf("n%d", i)] = repo.name
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"query": fmt.Sprintf("query(%s) {\n%s}\n%s",
+			strings.TrimPrefix(params.String(), ", "), fields.String(), gitHubGraphQLReleases),
+		"variables": variables})
+
+	req, err := http.NewRequest(http.MethodPost, b.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	req.Header.Set("Authorization", "bearer "+b.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Connection", "close")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	defer resp.Body.Close()
+	gitHubRateLimits.update(b.token, resp.Header, time.Now())
+
+	rawBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	if resp.StatusCode != http.StatusOK {
+		if strings.Contains(string(rawBody), "rate limit") {
+			return nil, errors.New("rate limit reached for GitHub GraphQL")
+		}
+		return nil, fmt.Errorf("github graphql query failed: %s",
+			resp.Status)
+	}
+
+	var response gitHubGraphQLResponse
+	if err := json.Unmarshal(rawBody, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal of GitHub GraphQL data failed\n%w",
+			err)
+	}
+
+	results := make(map[string]gitHubGraphQLResult, len(repos))
+	// Errors of specific repositories (e.g. NOT_FOUND).
+	for _, e := range response.Errors {
+		if len(e.Path) == 0 {
+			// Errors without a path fail the whole request.
+			return nil, fmt.Errorf("github graphql query failed: %s",
+				e.Message)
+		}
+		if alias, ok := e.Path[0].(string); ok {
+			results[alias] = gitHubGraphQLResult{err: errors.New(e.Message)}
+		}
+	}
+	for alias, repository := range response.Data {
+		if _, failed := results[alias]; failed {
+			continue
+		}
+		if repository == nil {
+			results[alias] = gitHubGraphQLResult{err: errors.New("repository not found")}
+			continue
+		}
+		var index int
+		if _, err := fmt.Sscanf(alias, "r%d", &index); err != nil || index < 0 || index >= len(repos) {
+			continue
+		}
+		releases := make([]github_types.Release, len(repository.Releases.Nodes))
+		for i := range repository.Releases.Nodes {
+			releases[i] = repository.Releases.Nodes[i].release(repos[index].owner, repos[index].name)
+		}
+		results[alias] = gitHubGraphQLResult{releases: releases}
+	}
+	for i := range repos {
+		alias := fmt.Sprintf("r%d", i)
+		if _, ok := results[alias]; !ok {
+			results[alias] = gitHubGraphQLResult{err: errors.New("repository missing from the response")}
+		}
+	}
+	return results, nil
+}
+
+// release converts the GraphQL release of the `owner`/`name` repository to the format of a REST Release.
+func (r *gitHubGraphQLRelease) release(owner string, name string) github_types.Release {
+	release := github_types.Release{
+		HTMLURL:     r.URL,
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Description,
+		Draft:       r.IsDraft,
+		PreRelease:  r.IsPrerelease,
+		PublishedAt: r.PublishedAt}
+	if r.Author != nil {
+		release.Author = &github_types.User{
+			Login:   r.Author.Login,
+			HTMLURL: r.Author.URL}
+	}
+	if len(r.ReleaseAssets.Nodes) != 0 {
+		release.Assets = make([]github_types.Asset, len(r.ReleaseAssets.Nodes))
+		for i, asset := range r.ReleaseAssets.Nodes {
+			release.Assets[i] = github_types.Asset{
+				ID:                 asset.DatabaseID,
+				Name:               asset.Name,
+				URL:                fmt.Sprintf(gitHubAssetURL, owner, name, asset.DatabaseID),
+				BrowserDownloadURL: asset.DownloadURL,
+				Size:               asset.Size}
+		}
+	}
+	return release
+}
+
+// usesGitHubGraphQL returns whether this Lookup's releases are queried through the batched GraphQL API.
+//
+// (The GraphQL API needs an access token)
+func (l *Lookup) usesGitHubGraphQL() bool {
+	if l.Type != "github" || !l.GetUseGraphQL() || util.DefaultIfNil(l.GetAccessToken()) == "" {
+		return false
+	}
+	_, _, ok := gitHubRepo(l.URL)
+	return ok
+}
+
+// gitHubGraphQLRequest gets the releases of this Lookup from the batched GraphQL API
+// and stores them in the GitHubData.
+func (l *Lookup) gitHubGraphQLRequest(client *http.Client, logFrom *util.LogFrom) error {
+	owner, name, _ := gitHubRepo(l.URL)
+	// Reuse releases from a query of another Lookup within half this Lookup's interval.
+	maxAge := l.Options.GetIntervalDuration() / 2
+
+	releases, err := gitHubBatches.releases(client,
+		*l.GetAccessToken(),
+		owner, name,
+		maxAge)
+	if err != nil {
+		err = fmt.Errorf("github graphql query for %q failed: %w",
+			l.URL, err)
+		jLog.Error(err, *logFrom, true)
+		return err
+	}
+
+	l.GitHubData.SetReleases(releases)
+	return nil
+}
@@ -0,0 +1,323 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGitHubRepo(t *testing.T) {
+	// GIVEN a github type URL
+	tests := map[string]struct {
+		url         string
+		owner, name string
+		ok          bool
+	}{
+		"owner/repo": {
+			url: "release-argus/Argus", owner: "release-argus", name: "Argus", ok: true},
+		"github.com URL": {
+			url: "https://github.com/release-argus/Argus", owner: "release-argus", name: "Argus", ok: true},
+		"API URL": {
+			url: "https://api.github.com/repos/release-argus/Argus/releases", owner: "release-argus", name: "Argus", ok: true},
+		"dotted name": {
+			url: "owner/repo.js", owner: "owner", name: "repo.js", ok: true},
+		"git URL": {
+			url: "https://github.com/owner/repo.git", owner: "owner", name: "repo", ok: true},
+		"other host": {
+			url: "https://example.com/owner/repo/releases", ok: false},
+		"tags API": {
+			url: "https://api.github.com/repos/owner/repo/tags", ok: false},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN gitHubRepo is called on it
+			owner, repoName, ok := gitHubRepo(tc.url)
+
+			// THEN the owner and name are extracted when it's a GitHub repository
+			if ok != tc.ok || owner != tc.owner || repoName != tc.name {
+				t.Errorf("want: %q, %q, %t\ngot:  %q, %q, %t",
+					tc.owner, tc.name, tc.ok, owner, repoName, ok)
+			}
+		})
+	}
+}
+
+// testGitHubGraphQLServer returns a GraphQL API that has a release of every repository except "missing",
+// and records the repositories of each request.
+func testGitHubGraphQLServer(t *testing.T) (*httptest.Server, *[][]string, *sync.Mutex) {
+	var (
+		requests [][]string
+		mutex    sync.Mutex
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bearer ghp_test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var body struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		data := map[string]interface{}{}
+		var errs []map[string]interface{}
+		var repos []string
+		for i := 0; ; i++ {
+			owner, ok := body.Variables[fmt.Sprintf("o%d", i)]
+			if !ok {
+				break
+			}
+			name := body.Variables[fmt.Sprintf("n%d", i)]
+			alias := fmt.Sprintf("r%d", i)
+			if !strings.Contains(body.Query, alias+": repository(owner: $o") {
+				t.Errorf("query doesn't alias %q:\n%s",
+					alias, body.Query)
+			}
+			repos = append(repos, owner+"/"+name)
+			if name == "missing" {
+				data[alias] = nil
+				errs = append(errs, map[string]interface{}{
+					"type":    "NOT_FOUND",
+					"message": fmt.Sprintf("Could not resolve to a Repository with the name '%s/%s'.", owner, name),
+					"path":    []string{alias}})
+				continue
+			}
+			data[alias] = map[string]interface{}{
+				"releases": map[string]interface{}{
+					"nodes": []map[string]interface{}{{
+						"tagName":      "v1.2.3",
+						"name":         name + " 1.2.3",
+						"description":  "notes",
+						"isDraft":      false,
+						"isPrerelease": false,
+						"publishedAt":  "2023-05-17T10:00:00Z",
+						"url":          "https://github.com/" + owner + "/" + name + "/releases/tag/v1.2.3",
+						"author":       map[string]string{"login": owner, "url": "https://github.com/" + owner},
+						"releaseAssets": map[string]interface{}{
+							"nodes": []map[string]interface{}{{
+								"databaseId":  1,
+								"name":        name + ".tar.gz",
+								"downloadUrl": "https://github.com/" + owner + "/" + name + "/releases/download/v1.2.3/" + name + ".tar.gz",
+								"size":        123}}}}}}}
+		}
+		sort.Strings(repos)
+		mutex.Lock()
+		requests = append(requests, repos)
+		mutex.Unlock()
+
+		w.Header().Set("X-RateLimit-Resource", "graphql")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":   data,
+			"errors": errs})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests, &mutex
+}
+
+func TestGitHubBatcher_Releases(t *testing.T) {
+	// GIVEN a GraphQL API and a batcher of 2 repositories per request
+	server, requests, mutex := testGitHubGraphQLServer(t)
+	batcher := gitHubBatcher{
+		url:       server.URL,
+		batchSize: 2,
+		batches:   map[string]*gitHubBatch{}}
+	client := &http.Client{}
+	maxAge := time.Minute
+
+	// WHEN the releases of a repository are wanted
+	releases, err := batcher.releases(client, "ghp_test", "owner", "a", maxAge)
+
+	// THEN they're converted from the GraphQL format
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(releases) != 1 || releases[0].TagName != "v1.2.3" || releases[0].Name != "a 1.2.3" ||
+		releases[0].Body != "notes" || releases[0].Author == nil || releases[0].Author.Login != "owner" ||
+		len(releases[0].Assets) != 1 || releases[0].Assets[0].Size != 123 ||
+		!strings.HasSuffix(releases[0].Assets[0].BrowserDownloadURL, "/a.tar.gz") ||
+		releases[0].Assets[0].URL != "https://api.github.com/repos/owner/a/releases/assets/1" {
+		t.Fatalf("unexpected releases: %+v", releases)
+	}
+
+	// WHEN more repositories of the token want their releases before they're stale
+	for _, name := range []string{"b", "c", "missing"} {
+		_, err := batcher.releases(client, "ghp_test", "owner", name, maxAge)
+		// THEN a missing repository errs
+		if (err != nil) != (name == "missing") {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+	}
+	// AND the fresh releases of the first repository are reused
+	if _, err := batcher.releases(client, "ghp_test", "owner", "a", maxAge); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mutex.Lock()
+	if len(*requests) != 4 {
+		t.Fatalf("want 4 requests (1 per new repository), got %d: %v",
+			len(*requests), *requests)
+	}
+	mutex.Unlock()
+
+	// WHEN they're all stale
+	for _, repo := range batcher.batch("ghp_test").repos {
+		repo.queried = repo.queried.Add(-2 * maxAge)
+	}
+	releases, err = batcher.releases(client, "ghp_test", "owner", "b", maxAge)
+	if err != nil || len(releases) != 1 {
+		t.Fatalf("unexpected result: %v, %v", releases, err)
+	}
+
+	// THEN every repository of the token is queried in batches of batchSize
+	mutex.Lock()
+	defer mutex.Unlock()
+	batched := (*requests)[4:]
+	if len(batched) != 2 {
+		t.Fatalf("want 2 batched requests, got %d: %v",
+			len(batched), batched)
+	}
+	var all []string
+	for _, request := range batched {
+		if len(request) > 2 {
+			t.Errorf("request of %d repositories is over the batchSize: %v",
+				len(request), request)
+		}
+		all = append(all, request...)
+	}
+	sort.Strings(all)
+	if got := strings.Join(all, ","); got != "owner/a,owner/b,owner/c,owner/missing" {
+		t.Errorf("want every repository queried, got %q",
+			got)
+	}
+	// AND the GraphQL rate limit is tracked separately to the REST one
+	if key := budgetKey("ghp_test", "graphql"); !strings.HasSuffix(key, "/graphql") {
+		t.Errorf("want a separate graphql budget key, got %q",
+			key)
+	}
+}
+
+func TestGitHubBatcher_ReleasesConcurrent(t *testing.T) {
+	// GIVEN a GraphQL API that holds the requests for "slow" until it's released
+	server, requests, mutex := testGitHubGraphQLServer(t)
+	release := make(chan struct{})
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"slow"`) {
+			<-release
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(slowServer.Close)
+	batcher := gitHubBatcher{
+		url:       slowServer.URL,
+		batchSize: 50,
+		batches:   map[string]*gitHubBatch{}}
+	client := &http.Client{}
+	maxAge := time.Minute
+
+	// WHEN the releases of "slow" are wanted twice whilst its request is in flight
+	var wg sync.WaitGroup
+	slowErrs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := batcher.releases(client, "ghp_test", "owner", "slow", maxAge)
+			slowErrs <- err
+		}()
+		// Let the first request start.
+		if i == 0 {
+			for {
+				batch := batcher.batch("ghp_test")
+				batch.mutex.Lock()
+				started := batch.repos["owner/slow"] != nil && batch.repos["owner/slow"].pending != nil
+				batch.mutex.Unlock()
+				if started {
+					break
+				}
+				time.Sleep(time.Millisecond)
+			}
+		}
+	}
+
+	// THEN the releases of another repository of that token can be queried meanwhile
+	done := make(chan error, 1)
+	go func() {
+		_, err := batcher.releases(client, "ghp_test", "owner", "fast", maxAge)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("query of another repository waited for the request in flight")
+	}
+
+	// AND "slow" is only requested once, with both getting its releases
+	close(release)
+	wg.Wait()
+	close(slowErrs)
+	for err := range slowErrs {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(*requests) != 2 {
+		t.Errorf("want 2 requests (slow, fast), got %d: %v",
+			len(*requests), *requests)
+	}
+}
+
+func TestGitHubBatcher_ReleasesRequestError(t *testing.T) {
+	// GIVEN a GraphQL API that rejects the access token
+	server, _, _ := testGitHubGraphQLServer(t)
+	batcher := gitHubBatcher{
+		url:       server.URL,
+		batchSize: 50,
+		batches:   map[string]*gitHubBatch{}}
+
+	// WHEN the releases of a repository are wanted
+	_, err := batcher.releases(&http.Client{}, "ghp_invalid", "owner", "a", time.Minute)
+
+	// THEN the error of the request is returned
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("want a 401 error, got %v",
+			err)
+	}
+}
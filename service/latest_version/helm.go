@@ -0,0 +1,99 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"fmt"
+	net_url "net/url"
+	"path"
+	"strings"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/util"
+	"gopkg.in/yaml.v3"
+)
+
+// checkHelmIndexBody will check that the body is a Helm repository index containing the Chart
+// and convert the versions of that Chart to Releases.
+//
+// The TagName of each Release is the chart `version`, or the `appVersion` if use_app_version.
+// Charts are listed newest first, so when tracking the appVersion, only the newest chart of each appVersion is kept.
+func (l *Lookup) checkHelmIndexBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	var index github_types.HelmIndex
+	if err = yaml.Unmarshal(*body, &index); err != nil {
+		err = fmt.Errorf("unmarshal of Helm repository index failed\n%w",
+			err)
+		jLog.Error(err, *logFrom, true)
+		return
+	}
+
+	chartVersions, exists := index.Entries[l.Chart]
+	if !exists {
+		err = fmt.Errorf("chart %q not found in the Helm repository index at %q",
+			l.Chart, l.GetQueryURL())
+		jLog.Error(err, *logFrom, true)
+		return
+	}
+
+	useAppVersion := l.GetUseAppVersion()
+	seen := make(map[string]bool, len(chartVersions))
+	releases = make([]github_types.Release, 0, len(chartVersions))
+	for _, chartVersion := range chartVersions {
+		version := chartVersion.Version
+		if useAppVersion {
+			version = chartVersion.AppVersion
+		}
+		if version == "" || seen[version] {
+			continue
+		}
+		seen[version] = true
+
+		assets := make([]github_types.Asset, len(chartVersion.URLs))
+		for i, url := range chartVersion.URLs {
+			assets[i] = github_types.Asset{
+				Name:               path.Base(url),
+				BrowserDownloadURL: l.helmChartURL(url)}
+		}
+		// Chart versions are SemVer 2, and the app version of a pre-release chart is a pre-release too.
+		preRelease := l.isPreRelease(version, isSemVerPreRelease) ||
+			(useAppVersion && isSemVerPreRelease(chartVersion.Version))
+		releases = append(releases, github_types.Release{
+			TagName:    version,
+			PreRelease: preRelease,
+			Assets:     assets})
+	}
+	return
+}
+
+// helmChartURL resolves the (possibly relative) URL of a chart archive against the index URL.
+func (l *Lookup) helmChartURL(url string) string {
+	indexURL, err := net_url.Parse(l.GetQueryURL())
+	if err != nil {
+		return url
+	}
+	chartURL, err := indexURL.Parse(url)
+	if err != nil {
+		return url
+	}
+	return chartURL.String()
+}
+
+// helmIndexURL returns the URL of the index.yaml of the Helm repository `url`.
+func helmIndexURL(url string) string {
+	if strings.HasSuffix(url, ".yaml") || strings.HasSuffix(url, ".yml") {
+		return url
+	}
+	return strings.TrimSuffix(url, "/") + "/index.yaml"
+}
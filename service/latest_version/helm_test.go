@@ -0,0 +1,215 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	opt "github.com/release-argus/Argus/service/options"
+	"github.com/release-argus/Argus/util"
+)
+
+var testHelmIndex = `apiVersion: v1
+entries:
+  argus:
+  - name: argus
+    version: 1.3.0-rc.1
+    appVersion: 0.12.0
+    urls:
+    - https://charts.example.com/argus-1.3.0-rc.1.tgz
+  - name: argus
+    version: 1.2.1
+    appVersion: 0.11.1
+    urls:
+    - argus-1.2.1.tgz
+  - name: argus
+    version: 1.2.0
+    appVersion: 0.11.1
+    urls:
+    - argus-1.2.0.tgz
+  - name: argus
+    version: 1.1.0
+    appVersion: 0.11.0
+  other:
+  - name: other
+    version: 9.9.9
+`
+
+func TestLookup_CheckHelmIndexBody(t *testing.T) {
+	// GIVEN a Helm repository index
+	tests := map[string]struct {
+		body           string
+		chart          string
+		useAppVersion  bool
+		scheme         string
+		want           []string
+		wantPreRelease []bool
+		wantAsset      string
+		errRegex       string
+	}{
+		"chart versions": {
+			body:           testHelmIndex,
+			chart:          "argus",
+			want:           []string{"1.3.0-rc.1", "1.2.1", "1.2.0", "1.1.0"},
+			wantPreRelease: []bool{true, false, false, false},
+			wantAsset:      "https://charts.example.com/stable/argus-1.2.1.tgz",
+			errRegex:       `^$`},
+		"app versions, newest chart of each": {
+			body:           testHelmIndex,
+			chart:          "argus",
+			useAppVersion:  true,
+			want:           []string{"0.12.0", "0.11.1", "0.11.0"},
+			wantPreRelease: []bool{true, false, false},
+			wantAsset:      "https://charts.example.com/stable/argus-1.2.1.tgz",
+			errRegex:       `^$`},
+		"app versions with the pep440 version_scheme": {
+			body: `entries:
+  argus:
+  - version: 1.1.0
+    appVersion: 2.0rc1
+  - version: 1.0.0
+    appVersion: "1.0"
+`,
+			chart:          "argus",
+			useAppVersion:  true,
+			scheme:         "pep440",
+			want:           []string{"2.0rc1", "1.0"},
+			wantPreRelease: []bool{true, false},
+			errRegex:       `^$`},
+		"chart not in index": {
+			body:     testHelmIndex,
+			chart:    "unknown",
+			errRegex: `chart "unknown" not found in the Helm repository index at "https://charts.example.com/stable/index.yaml"`},
+		"invalid yaml": {
+			body:     `entries: [`,
+			chart:    "argus",
+			errRegex: `unmarshal of Helm repository index failed`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			body := []byte(tc.body)
+			lookup := Lookup{
+				Type:          "helm",
+				URL:           "https://charts.example.com/stable",
+				Chart:         tc.chart,
+				UseAppVersion: &tc.useAppVersion,
+				Options:       &opt.Options{}}
+			lookup.Options.VersionScheme = tc.scheme
+
+			// WHEN checkHelmIndexBody is called on this body
+			releases, err := lookup.checkHelmIndexBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the chart versions are converted to releases
+			if len(releases) != len(tc.want) {
+				t.Fatalf("want %d releases, got %d\n%v",
+					len(tc.want), len(releases), releases)
+			}
+			for i := range tc.want {
+				if releases[i].TagName != tc.want[i] ||
+					releases[i].PreRelease != tc.wantPreRelease[i] {
+					t.Errorf("release %d - want %q (prerelease=%t), got %q (prerelease=%t)",
+						i, tc.want[i], tc.wantPreRelease[i], releases[i].TagName, releases[i].PreRelease)
+				}
+			}
+			// AND relative chart URLs are resolved against the index
+			if tc.wantAsset != "" && releases[1].Assets[0].BrowserDownloadURL != tc.wantAsset {
+				t.Errorf("want asset %q, got %q",
+					tc.wantAsset, releases[1].Assets[0].BrowserDownloadURL)
+			}
+		})
+	}
+}
+
+func TestLookup_QueryHelm(t *testing.T) {
+	// GIVEN a Helm Lookup and a chart repository
+	tests := map[string]struct {
+		url           string
+		useAppVersion bool
+		wantVersion   string
+		errRegex      string
+	}{
+		"chart version": {
+			url:         "/stable",
+			wantVersion: "1.2.1",
+			errRegex:    `^$`},
+		"app version": {
+			url:           "/stable/index.yaml",
+			useAppVersion: true,
+			wantVersion:   "0.11.1",
+			errRegex:      `^$`},
+		"repository not found": {
+			url:      "/unknown",
+			errRegex: `helm query for "[^"]+/unknown" failed: 404 Not Found`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/stable/index.yaml" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(testHelmIndex))
+			}))
+			defer server.Close()
+			lookup := testLookup(false, false)
+			lookup.Type = "helm"
+			lookup.URL = server.URL + tc.url
+			lookup.Chart = "argus"
+			lookup.UseAppVersion = &tc.useAppVersion
+			lookup.URLCommands = nil
+			if err := lookup.CheckValues(""); err != nil {
+				t.Fatalf("CheckValues failed: %v", err)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the newest non-prerelease version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantVersion {
+				t.Errorf("want version %q, got %q",
+					tc.wantVersion, got)
+			}
+		})
+	}
+}
@@ -69,6 +69,13 @@ func (l *Lookup) InitMetrics() {
 		"",
 		"",
 		"FAIL")
+
+	// ##########
+	// # Gauges #
+	// ##########
+	metric.SetPrometheusGauge(metric.LatestVersionQueryConsecutiveFailures,
+		*l.Status.ServiceID,
+		float64(l.Status.ConsecutiveFailures()))
 }
 
 // DeleteMetrics for this Lookup.
@@ -83,4 +90,6 @@ func (l *Lookup) DeleteMetrics() {
 		"",
 		"",
 		"FAIL")
+	metric.DeletePrometheusGauge(metric.LatestVersionQueryConsecutiveFailures,
+		*l.Status.ServiceID)
 }
@@ -15,18 +15,30 @@
 package latestver
 
 import (
+	"context"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	net_url "net/url"
 	"strings"
+	"time"
 
-	"github.com/coreos/go-semver/semver"
 	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	"github.com/release-argus/Argus/service/latest_version/filter"
 	"github.com/release-argus/Argus/util"
 	metric "github.com/release-argus/Argus/web/metrics"
 )
 
+// queryTimeout is the longest that a query of the source may take.
+const queryTimeout = 30 * time.Second
+
+// bodyErrorTypes are the types that return an error in the body of a failed query (or have no error format).
+var bodyErrorTypes = []string{
+	"gitea", "github", "gitlab", "url"}
+
 // Query queries the Service source, updating Service.LatestVersion
 // and returning true if it has changed (is a new release),
 // otherwise returns false.
@@ -36,10 +48,26 @@ func (l *Lookup) query(logFrom *util.LogFrom) (bool, error) {
 		return false, err
 	}
 
-	version, err := l.GetVersion(rawBody, logFrom)
+	version, release, asset, err := l.getRelease(rawBody, logFrom)
 	if err != nil {
 		return false, err
 	}
+	var assetInfo util.AssetInfo
+	if asset != nil {
+		assetInfo = util.AssetInfo{
+			Name: asset.Name,
+			URL:  asset.BrowserDownloadURL,
+			Size: asset.Size}
+	}
+	releaseInfo := newReleaseInfo(release)
+
+	// Drop a pending version that's no longer the version found (e.g. the release was withdrawn).
+	if pendingVersion := l.Status.PendingVersion(); pendingVersion != "" && pendingVersion != version {
+		jLog.Debug(
+			fmt.Sprintf("Dropped pending release %q", pendingVersion),
+			*logFrom, true)
+		l.Status.SetPendingVersion("", "", true)
+	}
 
 	l.Status.SetLastQueried("")
 	wantSemanticVersioning := l.Options.GetSemanticVersioning()
@@ -49,17 +77,17 @@ func (l *Lookup) query(logFrom *util.LogFrom) (bool, error) {
 	if version != latestVersion {
 		if wantSemanticVersioning {
 			// Check it's a valid semnatic version
-			newVersion, err := semver.NewVersion(version)
+			newVersion, err := l.Options.ParseVersion(version)
 			if err != nil {
-				err = fmt.Errorf("failed converting %q to a semantic version. If all versions are in this style, consider adding url_commands to get the version into the style of 'MAJOR.MINOR.PATCH' (https://semver.org/), or disabling semantic versioning (globally with defaults.service.semantic_versioning or just for this service with the semantic_versioning var)",
-					version)
+				err = fmt.Errorf("failed converting %q to a semantic version. If all versions are in this style, consider adding url_commands to get the version into the style of %s, or disabling semantic versioning (globally with defaults.service.semantic_versioning or just for this service with the semantic_versioning var)",
+					version, l.Options.GetVersionStyle())
 				jLog.Error(err, *logFrom, true)
 				return false, err
 			}
 
 			// Check for a progressive change in version.
 			if latestVersion != "" {
-				oldVersion, err := semver.NewVersion(l.Status.DeployedVersion())
+				oldVersion, err := l.Options.ParseVersion(l.Status.DeployedVersion())
 				// If the old version is not a semantic version, then we can't compare it.
 				// (if we switched to semantic versioning with non-semantic versions tracked)
 				if err == nil {
@@ -67,7 +95,7 @@ func (l *Lookup) query(logFrom *util.LogFrom) (bool, error) {
 					// newVersion = 1.2.9
 					// oldVersion = 1.2.10
 					// return false (don't notify anything and stay on oldVersion)
-					if newVersion.LessThan(*oldVersion) {
+					if newVersion.LessThan(oldVersion) {
 						err := fmt.Errorf("queried version %q is less than the deployed version %q",
 							version, l.Status.LatestVersion())
 						jLog.Warn(err, *logFrom, true)
@@ -77,12 +105,20 @@ func (l *Lookup) query(logFrom *util.LogFrom) (bool, error) {
 			}
 		}
 
+		// Hold the new version until it's old enough.
+		if latestVersion != "" && !l.checkMinAge(version, release.PublishedAt, logFrom) {
+			l.Status.AnnounceQuery()
+			return false, nil
+		}
+
 		// Found new version, so reset regex misses.
 		l.Status.ResetRegexMisses()
 
 		// First version found.
 		if l.Status.LatestVersion() == "" {
 			l.Status.SetLatestVersion(version, true)
+			l.Status.SetLatestVersionAsset(assetInfo)
+			l.Status.SetLatestVersionRelease(releaseInfo)
 			if l.Status.DeployedVersion() == "" {
 				l.Status.SetDeployedVersion(version, true)
 			}
@@ -97,11 +133,17 @@ func (l *Lookup) query(logFrom *util.LogFrom) (bool, error) {
 
 		// New version found.
 		l.Status.SetLatestVersion(version, true)
+		l.Status.SetLatestVersionAsset(assetInfo)
+		l.Status.SetLatestVersionRelease(releaseInfo)
 		msg := fmt.Sprintf("New Release - %q", version)
 		jLog.Info(msg, *logFrom, true)
 		return true, nil
 	}
 
+	// Keep the asset/release of the version (e.g. after a restart).
+	l.Status.SetLatestVersionAsset(assetInfo)
+	l.Status.SetLatestVersionRelease(releaseInfo)
+
 	// Announce `LastQueried`
 	l.Status.AnnounceQuery()
 	// No version change.
@@ -116,12 +158,18 @@ func (l *Lookup) Query(metrics bool, logFrom *util.LogFrom) (newVersion bool, er
 	newVersion, err = l.query(logFrom)
 
 	if metrics {
-		l.queryMetrics(err)
+		l.queryResult(err)
 	}
 
 	return
 }
 
+// queryResult records the result of the query on the Status, and sets the Prometheus metrics for it.
+func (l *Lookup) queryResult(err error) {
+	l.Status.SetQueryResult(err)
+	l.queryMetrics(err)
+}
+
 // queryMetrics sets the Prometheus metrics for the LatestVersion query.
 func (l *Lookup) queryMetrics(err error) {
 	// If it failed
@@ -159,9 +207,13 @@ func (l *Lookup) queryMetrics(err error) {
 			*l.Status.ServiceID,
 			1)
 	}
+	metric.SetPrometheusGauge(metric.LatestVersionQueryConsecutiveFailures,
+		*l.Status.ServiceID,
+		float64(l.Status.ConsecutiveFailures()))
 }
 
-func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBody []byte, err error) {
+// httpClient returns the HTTP client of this Lookup.
+func (l *Lookup) httpClient() *http.Client {
 	customTransport := &http.Transport{}
 	// HTTPS insecure skip verify.
 	if l.GetAllowInvalidCerts() {
@@ -169,8 +221,87 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBody []byte, err error)
 		//#nosec G402 -- explicitly wanted InsecureSkipVerify
 		customTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 	}
+	return &http.Client{Transport: customTransport}
+}
 
-	req, err := http.NewRequest(http.MethodGet, GetURL(l.URL, l.Type), nil)
+// setAuthorization will add the credentials of this Lookup to `req`.
+func (l *Lookup) setAuthorization(req *http.Request) {
+	switch l.Type {
+	case "github":
+		// Access Token
+		if util.DefaultIfNil(l.GetAccessToken()) != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("token %s", *l.GetAccessToken()))
+		}
+	case "gitea":
+		// Access Token (the defaults are GitHub tokens, so only use the one on this Lookup)
+		if util.DefaultIfNil(l.AccessToken) != "" {
+			req.Header.Set("Authorization", fmt.Sprintf("token %s", *l.AccessToken))
+		}
+	case "gitlab":
+		// Private Token (the defaults are GitHub tokens, so only use the one on this Lookup)
+		if util.DefaultIfNil(l.AccessToken) != "" {
+			req.Header.Set("PRIVATE-TOKEN", *l.AccessToken)
+		}
+	case "git":
+		// Credentials (the defaults are GitHub tokens, so only use the one on this Lookup)
+		if l.Username != "" || util.DefaultIfNil(l.AccessToken) != "" {
+			req.SetBasicAuth(l.Username, util.DefaultIfNil(l.AccessToken))
+		}
+	}
+}
+
+// assetDownload returns how the require.verify downloads the assets of a release of this Lookup.
+func (l *Lookup) assetDownload() filter.AssetDownload {
+	return filter.AssetDownload{
+		Client:  l.httpClient(),
+		Request: l.assetRequest}
+}
+
+// assetRequest returns the request to download `asset`,
+// with the credentials of this Lookup if it's on the host that's queried.
+func (l *Lookup) assetRequest(ctx context.Context, asset *github_types.Asset) (*http.Request, error) {
+	// Assets of private GitHub repositories can only be downloaded through the API.
+	if l.Type == "github" && asset.URL != "" && util.DefaultIfNil(l.GetAccessToken()) != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+		req.Header.Set("Accept", "application/octet-stream")
+		l.setAuthorization(req)
+		return req, nil
+	}
+
+	downloadURL := util.FirstNonDefault(asset.BrowserDownloadURL, asset.URL)
+	if downloadURL == "" {
+		return nil, fmt.Errorf("no download URL for %q", asset.Name)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	// Don't send the credentials to other hosts.
+	if queryURL, err := net_url.Parse(l.GetQueryURL()); err == nil && queryURL.Host == req.URL.Host {
+		l.setAuthorization(req)
+	}
+	return req, nil
+}
+
+func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBody []byte, err error) {
+	client := l.httpClient()
+	client.Timeout = queryTimeout
+
+	// Container tags may be split over multiple pages.
+	if l.Type == "container" {
+		return l.containerTags(client, logFrom)
+	}
+
+	// GitHub releases batched with other services through the GraphQL API.
+	if l.usesGitHubGraphQL() {
+		err = l.gitHubGraphQLRequest(client, logFrom)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(l.Status.Context(), http.MethodGet, l.GetQueryURL(), nil)
 	if err != nil {
 		jLog.Error(err, *logFrom, true)
 		return
@@ -178,19 +309,23 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBody []byte, err error)
 
 	// Set headers
 	req.Header.Set("Connection", "close")
-	if l.Type == "github" {
-		// Access Token
-		if util.DefaultIfNil(l.GetAccessToken()) != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("token %s", *l.GetAccessToken()))
-		}
+	l.setAuthorization(req)
+	switch l.Type {
+	case "github":
 		// Conditional requests - https://docs.github.com/en/rest/overview/resources-in-the-rest-api#conditional-requests
 		eTag := l.GitHubData.ETag()
 		if eTag != "" {
 			req.Header.Set("If-None-Match", eTag)
 		}
+	case "crates":
+		// https://crates.io/policies#crawlers
+		req.Header.Set("User-Agent", fmt.Sprintf("Argus/%s (https://release-argus.io)", util.Version))
+	case "url":
+		// Conditional requests - reuse the cached body if the page is unchanged
+		cache := l.Status.LatestVersionCache()
+		cache.SetConditionalHeaders(req)
 	}
 
-	client := &http.Client{Transport: customTransport}
 	resp, err := client.Do(req)
 	if err != nil {
 		// Don't crash on invalid certs.
@@ -207,6 +342,41 @@ func (l *Lookup) httpRequest(logFrom *util.LogFrom) (rawBody []byte, err error)
 	defer resp.Body.Close()
 	rawBody, err = io.ReadAll(resp.Body)
 	jLog.Error(err, *logFrom, err != nil)
+	if l.Type == "github" {
+		// Track the rate limit of this access token.
+		token := util.DefaultIfNil(l.GetAccessToken())
+		gitHubRateLimits.update(token, resp.Header, time.Now())
+		if err == nil &&
+			(resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests) &&
+			(resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != "") {
+			err = errors.New("rate limit reached for GitHub")
+			if reset := gitHubRateLimits.deferUntil(token, "core", "high", time.Now()); !reset.IsZero() {
+				err = fmt.Errorf("rate limit reached for GitHub (resets at %s)",
+					reset.Format(time.RFC3339))
+			}
+			jLog.Warn(err, *logFrom, true)
+			return
+		}
+	}
+	// Only the forge APIs return an error format that's checked in the body.
+	if err == nil && resp.StatusCode >= http.StatusBadRequest && !util.Contains(bodyErrorTypes, l.Type) {
+		err = fmt.Errorf("%s query for %q failed: %s",
+			l.Type, l.URL, resp.Status)
+		jLog.Error(err, *logFrom, true)
+		return
+	}
+	if l.Type == "gitlab" && err == nil && resp.StatusCode == http.StatusOK {
+		rawBody, err = l.gitLabPages(client, req, resp.Header, rawBody, logFrom)
+	}
+	if l.Type == "url" && err == nil {
+		cache := l.Status.LatestVersionCache()
+		var notModified bool
+		rawBody, cache, notModified = cache.Response(req, resp, rawBody)
+		if notModified {
+			jLog.Verbose("Using cached body (not modified)", *logFrom, true)
+		}
+		l.Status.SetLatestVersionCache(cache, true)
+	}
 	if l.Type == "github" && err == nil {
 		newETag := strings.TrimPrefix(resp.Header.Get("etag"), "W/")
 		if l.GitHubData.ETag() != newETag {
@@ -225,39 +395,127 @@ func (l *Lookup) GetVersions(
 ) (filteredReleases []github_types.Release, err error) {
 	var releases []github_types.Release
 	body := string(rawBody)
+	switch l.Type {
 	// GitHub service.
-	if l.Type == "github" {
+	case "github":
 		releases, err = l.checkGitHubReleasesBody(&rawBody, logFrom)
 		if err != nil {
 			return
 		}
 		// Store the unfiltered releases to support filter changes without a refetch
 		l.GitHubData.SetReleases(releases)
-		// Filter releases
-		filteredReleases = l.filterGitHubReleases(logFrom)
-		if len(filteredReleases) == 0 {
-			err = fmt.Errorf("no releases were found matching the url_commands")
-			jLog.Warn(err, *logFrom, true)
+	// Gitea/Forgejo service.
+	case "gitea":
+		releases, err = l.checkGiteaReleasesBody(&rawBody, logFrom)
+		if err != nil {
 			return
 		}
-
-		// url service
-	} else {
-		var version string
-		version, err = l.URLCommands.Run(body, *logFrom)
+	// GitLab service.
+	case "gitlab":
+		releases, err = l.checkGitLabReleasesBody(&rawBody, logFrom)
+		if err != nil {
+			return
+		}
+	// Container image.
+	case "container":
+		releases, err = l.checkContainerTagsBody(&rawBody, logFrom)
+		if err != nil {
+			return
+		}
+	// Git repository.
+	case "git":
+		releases, err = l.checkGitRefsBody(&rawBody, logFrom)
+		if err != nil {
+			return
+		}
+	// RSS/Atom feed.
+	case "feed":
+		releases, err = l.checkFeedBody(&rawBody, logFrom)
+		if err != nil {
+			return
+		}
+	// Helm chart repository.
+	case "helm":
+		releases, err = l.checkHelmIndexBody(&rawBody, logFrom)
+		if err != nil {
+			return
+		}
+	// Package registry.
+	case "crates", "goproxy", "npm", "pypi":
+		releases, err = l.checkRegistryBody(&rawBody, logFrom)
+		if err != nil {
+			return
+		}
+	// url service
+	default:
+		var versions []string
+		versions, err = l.URLCommands.RunAll(body, *logFrom)
 		if err != nil {
 			//nolint:wrapcheck
 			return
 		}
-		filteredReleases = []github_types.Release{{TagName: version}}
+		// A single version is used as-is
+		if len(versions) == 1 {
+			filteredReleases = []github_types.Release{{TagName: versions[0]}}
+			return
+		}
+
+		// Multiple candidates are filtered/sorted like releases
+		usePreReleases := l.GetUsePreRelease()
+		filteredReleases = make([]github_types.Release, 0, len(versions))
+		for i := range versions {
+			if !usePreReleases && l.isPreRelease(versions[i], isSemVerPreRelease) {
+				continue
+			}
+			addRelease(github_types.Release{TagName: versions[i]}, &filteredReleases, l.Options)
+		}
+		if len(filteredReleases) == 0 {
+			err = fmt.Errorf("no releases were found matching the url_commands")
+			jLog.Warn(err, *logFrom, true)
+		}
+		return
+	}
+
+	// Filter releases
+	filteredReleases = l.filterReleases(releases, logFrom)
+	if len(filteredReleases) == 0 {
+		err = fmt.Errorf("no releases were found matching the url_commands")
+		jLog.Warn(err, *logFrom, true)
+	}
+	return
+}
+
+// newReleaseInfo returns the metadata (name/notes/...) of `release`.
+func newReleaseInfo(release *github_types.Release) (info util.ReleaseInfo) {
+	if release == nil {
+		return
+	}
+
+	info = util.ReleaseInfo{
+		Name:        release.Name,
+		Notes:       release.Body,
+		URL:         release.HTMLURL,
+		PublishedAt: release.PublishedAt}
+	if release.Author != nil {
+		info.Author = release.Author.Login
 	}
 	return
 }
 
 // GetVersion will return the latest version from rawBody matching the URLCommands and Regex requirements
 func (l *Lookup) GetVersion(rawBody []byte, logFrom *util.LogFrom) (version string, err error) {
+	version, _, _, err = l.getRelease(rawBody, logFrom)
+	return
+}
+
+// getRelease will return the latest version from rawBody matching the URLCommands and Regex requirements,
+// along with the release it came from and the asset of it that satisfied the require.assets.
+func (l *Lookup) getRelease(
+	rawBody []byte,
+	logFrom *util.LogFrom,
+) (version string, release *github_types.Release, asset *github_types.Asset, err error) {
 	var filteredReleases []github_types.Release
-	// rawBody length = 0 if GitHub ETag is unchanged
+	// rawBody length = 0 if GitHub ETag is unchanged (or the releases came from GraphQL)
 	if len(rawBody) != 0 {
 		filteredReleases, err = l.GetVersions(rawBody, logFrom)
 		if err != nil {
@@ -265,16 +523,30 @@ func (l *Lookup) GetVersion(rawBody []byte, logFrom *util.LogFrom) (version stri
 		}
 	} else if l.Type == "github" {
 		// ReCheck this ETag's filteredReleases incase filters/releases changed
-		jLog.Verbose("Using cached releases (ETag unchanged)", *logFrom, true)
+		if l.usesGitHubGraphQL() {
+			jLog.Verbose("Using releases from the GraphQL batch", *logFrom, true)
+		} else {
+			jLog.Verbose("Using cached releases (ETag unchanged)", *logFrom, true)
+		}
 		filteredReleases = l.filterGitHubReleases(logFrom)
 	}
 
 	wantSemanticVersioning := l.Options.GetSemanticVersioning()
+	// Version that a patch/minor version_constraint is relative to
+	currentVersion := util.FirstNonDefault(
+		l.Status.DeployedVersion(),
+		l.Status.LatestVersion())
 	for i := range filteredReleases {
 		version = filteredReleases[i].TagName
 		if wantSemanticVersioning && l.Type != "url" {
 			version = filteredReleases[i].SemanticVersion.String()
 		}
+		release = &filteredReleases[i]
+
+		// Version constraint
+		if err = l.checkVersionConstraint(version, currentVersion, logFrom); err != nil {
+			continue
+		}
 
 		if l.Require == nil {
 			break
@@ -286,10 +558,19 @@ func (l *Lookup) GetVersion(rawBody []byte, logFrom *util.LogFrom) (version stri
 			continue
 		}
 
+		// Release notes RegEx
+		notes := filteredReleases[i].Body
+		if l.Type == "url" {
+			notes = string(rawBody)
+		}
+		if err = l.Require.RegexCheckNotes(version, notes, logFrom); err != nil {
+			continue
+		}
+
 		// Content RegEx
 		var body interface{}
-		if l.Type == "github" {
-			// GitHub service
+		if l.Type != "url" {
+			// GitHub/GitLab/Gitea service, git repository, package/chart registry, container image or feed
 			body = filteredReleases[i].Assets
 			// Web service
 		} else {
@@ -300,6 +581,11 @@ func (l *Lookup) GetVersion(rawBody []byte, logFrom *util.LogFrom) (version stri
 			continue
 		}
 
+		// If the Assets don't satisfy the requirements
+		if asset, err = l.Require.AssetsCheck(version, filteredReleases[i].Assets, logFrom); err != nil {
+			continue
+		}
+
 		// If the Command didn't return successfully
 		if err = l.Require.ExecCommand(logFrom); err != nil {
 			continue
@@ -319,6 +605,11 @@ func (l *Lookup) GetVersion(rawBody []byte, logFrom *util.LogFrom) (version stri
 					l.Require.Docker.Type, l.Require.Docker.Image, l.Require.Docker.GetTag(version)),
 				*logFrom, true)
 		}
+
+		// If the checksum/signature of the asset can't be verified
+		if err = l.Require.VerifyCheck(l.Status.Context(), l.assetDownload(), version, filteredReleases[i].Assets, logFrom); err != nil {
+			continue
+		}
 		break
 	}
 	if version == "" {
@@ -327,3 +618,78 @@ func (l *Lookup) GetVersion(rawBody []byte, logFrom *util.LogFrom) (version stri
 	}
 	return
 }
+
+// checkVersionConstraint will return an error if `version` doesn't satisfy the version_constraint.
+func (l *Lookup) checkVersionConstraint(version string, currentVersion string, logFrom *util.LogFrom) error {
+	ok, err := l.Options.CheckVersionConstraint(version, currentVersion)
+	if err != nil {
+		err = fmt.Errorf("version %q can't be checked against the version_constraint %q: %w",
+			version, l.Options.GetVersionConstraint(), err)
+		jLog.Warn(err, *logFrom, true)
+		return err
+	}
+	if ok {
+		return nil
+	}
+
+	err = fmt.Errorf("version %q doesn't satisfy the version_constraint %q",
+		version, l.Options.GetVersionConstraint())
+	jLog.Info(err, *logFrom, true)
+	return err
+}
+
+// checkMinAge will return whether `version` has been visible for at least require.min_age,
+// holding it as the PendingVersion until it has.
+//
+// The age is taken from when the version was first seen, or `publishedAt` (RFC3339) if that's earlier.
+func (l *Lookup) checkMinAge(version string, publishedAt string, logFrom *util.LogFrom) bool {
+	minAge := l.Require.GetMinAge()
+	if minAge == 0 {
+		return true
+	}
+
+	seenAt := time.Now().UTC()
+	// Persist when we first see this version, or when its timestamp is unreadable.
+	persist := l.Status.PendingVersion() != version
+	if !persist {
+		if pendingSince, err := time.Parse(time.RFC3339, l.Status.PendingVersionTimestamp()); err == nil {
+			seenAt = pendingSince
+		} else {
+			persist = true
+		}
+	}
+	if published, err := time.Parse(time.RFC3339, publishedAt); err == nil && published.Before(seenAt) {
+		seenAt = published.UTC()
+	}
+
+	// Not old enough yet.
+	if age := time.Since(seenAt); age < minAge {
+		if persist {
+			l.Status.SetPendingVersion(version, seenAt.Format(time.RFC3339), true)
+			jLog.Info(
+				fmt.Sprintf("Pending Release - %q (waiting %s for require.min_age of %s)",
+					version, (minAge-age).Round(time.Second), minAge),
+				*logFrom, true)
+		}
+		return false
+	}
+
+	l.Status.SetPendingVersion("", "", true)
+	return true
+}
+
+// apiErrorMessage returns the message of a JSON error object in `body`,
+// e.g. {"message":"404 Project Not Found"}, and whether `body` was an error.
+func apiErrorMessage(body *[]byte) (message string, isError bool) {
+	var apiErr struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(*body, &apiErr) != nil {
+		return
+	}
+
+	message = util.FirstNonDefault(apiErr.Message, apiErr.Error)
+	isError = true
+	return
+}
@@ -17,13 +17,18 @@
 package latestver
 
 import (
+	"context"
+	"errors"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"strings"
 	"testing"
 	"time"
 
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
 	"github.com/release-argus/Argus/service/latest_version/filter"
 	"github.com/release-argus/Argus/util"
 )
@@ -365,3 +370,614 @@ no releases were found matching the url_commands and/or require`},
 		})
 	}
 }
+
+func TestLookup_GetVersionURLCandidates(t *testing.T) {
+	// GIVEN a URL Lookup with url_commands that may give multiple candidate versions
+	body := `<ul>
+		<li>v1.2.0</li>
+		<li>v1.10.0</li>
+		<li>v2.0.0-rc.1</li>
+		<li>v1.9.0</li>
+		<li>vNext</li>
+	</ul>`
+	tests := map[string]struct {
+		body               string
+		urlCommands        filter.URLCommandSlice
+		usePreRelease      bool
+		semanticVersioning bool
+		regexVersion       string
+		versionConstraint  string
+		versionScheme      string
+		want               string
+		errRegex           string
+	}{
+		"single version used as-is": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`v([0-9][^<]*)`)}},
+			semanticVersioning: true,
+			want:               "1.2.0"},
+		"all - newest semantic version": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`v([0-9][^<]*)`), All: true}},
+			semanticVersioning: true,
+			want:               "1.10.0"},
+		"all - newest semantic version including prereleases": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`v([0-9][^<]*)`), All: true}},
+			usePreRelease:      true,
+			semanticVersioning: true,
+			want:               "2.0.0-rc.1"},
+		"all - falls back to an older version that passes require": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`v([0-9][^<]*)`), All: true}},
+			semanticVersioning: true,
+			regexVersion:       `^1\.[0-9]\.`,
+			want:               "1.9.0"},
+		"all - version_constraint checked before require": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`v([0-9][^<]*)`), All: true}},
+			semanticVersioning: true,
+			versionConstraint:  "~1.2",
+			want:               "1.2.0"},
+		"all - no versions satisfy the version_constraint": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`v([0-9][^<]*)`), All: true}},
+			semanticVersioning: true,
+			versionConstraint:  "^3",
+			want:               "1.2.0", // last version checked
+			errRegex:           `version "1.2.0" doesn't satisfy the version_constraint "\^3"`},
+		"all - page order without semantic versioning": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "css", Selector: stringPtr("li"), All: true},
+				{Type: "replace", Old: stringPtr("v"), New: stringPtr("")}},
+			want: "1.2.0"},
+		"all - candidates failing a later url_command are dropped": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "css", Selector: stringPtr("li"), All: true},
+				{Type: "regex", Regex: stringPtr(`^v1\.([0-9]+\.0)$`)}},
+			semanticVersioning: false,
+			want:               "2.0"},
+		"all - non-semantic candidates skipped": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "css", Selector: stringPtr("li"), All: true},
+				{Type: "split", Text: stringPtr("v"), Index: 1}},
+			semanticVersioning: true,
+			want:               "1.10.0"},
+		"all - no semantic candidates": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`<li>([^<]+)</li>`), All: true}},
+			semanticVersioning: true,
+			errRegex:           `no releases were found matching the url_commands$`},
+		"all - ordered by the version_scheme": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`<li>([^<]+)</li>`), All: true}},
+			semanticVersioning: true,
+			versionScheme:      "loose-semver",
+			want:               "v1.10.0"},
+		"all - pre-releases of the version_scheme skipped": {
+			body: `<ul>
+				<li>1.2.0-1</li>
+				<li>1.3.0~rc1-1</li>
+				<li>1.2.0-2</li>
+			</ul>`,
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`<li>([^<]+)</li>`), All: true}},
+			semanticVersioning: true,
+			versionScheme:      "debian",
+			want:               "1.2.0-2"},
+		"all - no matches": {
+			urlCommands: filter.URLCommandSlice{
+				{Type: "regex", Regex: stringPtr(`release-([0-9.]+)`), All: true}},
+			semanticVersioning: true,
+			errRegex:           `regex "release-\(\[0-9\.\]\+\)" didn't return any matches`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup(true, false)
+			lookup.URLCommands = tc.urlCommands
+			lookup.UsePreRelease = &tc.usePreRelease
+			lookup.Options.SemanticVersioning = &tc.semanticVersioning
+			lookup.Options.VersionConstraint = tc.versionConstraint
+			lookup.Options.VersionScheme = tc.versionScheme
+			lookup.Require = &filter.Require{
+				RegexVersion: tc.regexVersion,
+				Status:       lookup.Status}
+			if tc.body == "" {
+				tc.body = body
+			}
+
+			// WHEN GetVersion is called on the body
+			version, err := lookup.GetVersion([]byte(tc.body), &util.LogFrom{})
+
+			// THEN the expected version is found
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if tc.errRegex == "" {
+				re = regexp.MustCompile(`^$`)
+			}
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if version != tc.want {
+				t.Errorf("want version %q, got %q",
+					tc.want, version)
+			}
+		})
+	}
+}
+
+func TestLookup_QueryMinAge(t *testing.T) {
+	// GIVEN a Lookup with a require.min_age and a version that may be pending
+	tests := map[string]struct {
+		minAge               string
+		latestVersion        string
+		pendingVersion       string
+		pendingSince         time.Duration
+		pendingTimestamp     string
+		bodyVersion          string
+		wantNewVersion       bool
+		wantLatestVersion    string
+		wantPendingVersion   string
+		wantPendingUnchanged bool
+		wantPendingRestarted bool
+	}{
+		"no min_age acts on the new version": {
+			latestVersion:     "1.0.0",
+			bodyVersion:       "1.1.0",
+			wantNewVersion:    true,
+			wantLatestVersion: "1.1.0"},
+		"first version isn't held": {
+			minAge:            "1h",
+			bodyVersion:       "1.1.0",
+			wantLatestVersion: "1.1.0"},
+		"new version held as pending": {
+			minAge:             "1h",
+			latestVersion:      "1.0.0",
+			bodyVersion:        "1.1.0",
+			wantLatestVersion:  "1.0.0",
+			wantPendingVersion: "1.1.0"},
+		"pending version that's too young stays pending": {
+			minAge:               "1h",
+			latestVersion:        "1.0.0",
+			pendingVersion:       "1.1.0",
+			pendingSince:         30 * time.Minute,
+			bodyVersion:          "1.1.0",
+			wantLatestVersion:    "1.0.0",
+			wantPendingVersion:   "1.1.0",
+			wantPendingUnchanged: true},
+		"pending version with an unreadable timestamp restarts its wait": {
+			minAge:               "1h",
+			latestVersion:        "1.0.0",
+			pendingVersion:       "1.1.0",
+			pendingTimestamp:     "not-a-time",
+			bodyVersion:          "1.1.0",
+			wantLatestVersion:    "1.0.0",
+			wantPendingVersion:   "1.1.0",
+			wantPendingRestarted: true},
+		"pending version that's old enough is promoted": {
+			minAge:            "1h",
+			latestVersion:     "1.0.0",
+			pendingVersion:    "1.1.0",
+			pendingSince:      2 * time.Hour,
+			bodyVersion:       "1.1.0",
+			wantNewVersion:    true,
+			wantLatestVersion: "1.1.0"},
+		"withdrawn pending version is dropped": {
+			minAge:            "1h",
+			latestVersion:     "1.0.0",
+			pendingVersion:    "1.1.0",
+			pendingSince:      2 * time.Hour,
+			bodyVersion:       "1.0.0",
+			wantLatestVersion: "1.0.0"},
+		"newer version replaces the pending version": {
+			minAge:             "1h",
+			latestVersion:      "1.0.0",
+			pendingVersion:     "1.1.0",
+			pendingSince:       2 * time.Hour,
+			bodyVersion:        "1.2.0",
+			wantLatestVersion:  "1.0.0",
+			wantPendingVersion: "1.2.0"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("v" + tc.bodyVersion))
+			}))
+			defer server.Close()
+			lookup := testLookup(true, false)
+			lookup.URL = server.URL
+			lookup.Require.MinAge = tc.minAge
+			lookup.Status.SetLatestVersion(tc.latestVersion, false)
+			lookup.Status.SetDeployedVersion(tc.latestVersion, false)
+			pendingTimestamp := util.FirstNonDefault(
+				tc.pendingTimestamp,
+				time.Now().UTC().Add(-tc.pendingSince).Format(time.RFC3339))
+			lookup.Status.SetPendingVersion(tc.pendingVersion, pendingTimestamp, false)
+
+			// WHEN Query is called on it
+			newVersion, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN the query succeeds
+			if err != nil {
+				t.Fatalf("unexpected err: %v",
+					err)
+			}
+			// AND a new version is only acted on when it's old enough
+			if newVersion != tc.wantNewVersion {
+				t.Errorf("want newVersion=%t, got %t",
+					tc.wantNewVersion, newVersion)
+			}
+			if got := lookup.Status.LatestVersion(); got != tc.wantLatestVersion {
+				t.Errorf("want LatestVersion=%q, got %q",
+					tc.wantLatestVersion, got)
+			}
+			// AND the pending version is as expected
+			if got := lookup.Status.PendingVersion(); got != tc.wantPendingVersion {
+				t.Errorf("want PendingVersion=%q, got %q",
+					tc.wantPendingVersion, got)
+			}
+			if tc.wantPendingUnchanged && lookup.Status.PendingVersionTimestamp() != pendingTimestamp {
+				t.Errorf("want PendingVersionTimestamp=%q, got %q",
+					pendingTimestamp, lookup.Status.PendingVersionTimestamp())
+			}
+			if tc.wantPendingRestarted {
+				pendingSince, err := time.Parse(time.RFC3339, lookup.Status.PendingVersionTimestamp())
+				if err != nil || time.Since(pendingSince) > time.Minute {
+					t.Errorf("want PendingVersionTimestamp restarted at now, got %q",
+						lookup.Status.PendingVersionTimestamp())
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryConsecutiveFailures(t *testing.T) {
+	// GIVEN a Lookup that's failed twice in a row, and a source that responds in different ways
+	tests := map[string]struct {
+		status       int
+		body         string
+		unreachable  bool
+		regexVersion string
+		wantFailures uint
+	}{
+		"success resets the failures": {
+			status:       http.StatusOK,
+			body:         "v1.2.3",
+			wantFailures: 0},
+		"regex miss is a failure": {
+			status:       http.StatusOK,
+			body:         "no version here",
+			wantFailures: 3},
+		"require failure is a failure": {
+			status:       http.StatusOK,
+			body:         "v1.2.3",
+			regexVersion: `^2\.`,
+			wantFailures: 3},
+		"HTTP error is a failure": {
+			status:       http.StatusInternalServerError,
+			body:         "internal server error",
+			wantFailures: 3},
+		"transport error is a failure": {
+			unreachable:  true,
+			wantFailures: 3},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+			lookup := testLookup(true, false)
+			lookup.URL = server.URL
+			lookup.Require.RegexVersion = tc.regexVersion
+			lookup.Status.SetQueryResult(errors.New("fail 1"))
+			lookup.Status.SetQueryResult(errors.New("fail 2"))
+			if tc.unreachable {
+				server.Close()
+			}
+
+			// WHEN Query is called on it
+			lookup.Query(true, &util.LogFrom{})
+
+			// THEN every failed query counts towards the consecutive failures
+			if got := lookup.Status.ConsecutiveFailures(); got != tc.wantFailures {
+				t.Errorf("want %d consecutive failures, not %d",
+					tc.wantFailures, got)
+			}
+		})
+	}
+}
+
+func TestLookup_CheckMinAge(t *testing.T) {
+	// GIVEN a Lookup with a require.min_age of 1h and a version published at some time
+	tests := map[string]struct {
+		publishedAgo time.Duration // 0 = unknown
+		want         bool
+	}{
+		"unknown publish time": {
+			want: false},
+		"published long enough ago": {
+			publishedAgo: 90 * time.Minute,
+			want:         true},
+		"published recently": {
+			publishedAgo: 20 * time.Minute,
+			want:         false},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup(false, false)
+			lookup.Require.MinAge = "1h"
+			publishedAt := ""
+			if tc.publishedAgo != 0 {
+				publishedAt = time.Now().UTC().Add(-tc.publishedAgo).Format(time.RFC3339)
+			}
+
+			// WHEN checkMinAge is called on the version
+			got := lookup.checkMinAge("1.2.3", publishedAt, &util.LogFrom{})
+
+			// THEN the version is only old enough when it was published over min_age ago
+			if got != tc.want {
+				t.Errorf("want %t, got %t",
+					tc.want, got)
+			}
+			// AND versions that aren't old enough are pending from when they were published (or now)
+			wantPending, wantTimestamp := "", ""
+			if !tc.want {
+				wantPending = "1.2.3"
+				wantTimestamp = util.FirstNonDefault(publishedAt, time.Now().UTC().Format(time.RFC3339))
+			}
+			if lookup.Status.PendingVersion() != wantPending {
+				t.Errorf("want PendingVersion=%q, got %q",
+					wantPending, lookup.Status.PendingVersion())
+			}
+			if got := lookup.Status.PendingVersionTimestamp(); got != wantTimestamp &&
+				// "now" may have ticked over a second
+				tc.publishedAgo != 0 {
+				t.Errorf("want PendingVersionTimestamp=%q, got %q",
+					wantTimestamp, got)
+			}
+		})
+	}
+}
+
+func TestLookup_AssetRequest(t *testing.T) {
+	// GIVEN a Lookup and an asset of one of its releases
+	tests := map[string]struct {
+		lookupType  string
+		url         string
+		baseURL     string
+		accessToken string
+		asset       github_types.Asset
+		wantURL     string
+		wantHeaders map[string]string
+		errRegex    string
+	}{
+		"github with an access token downloads through the API": {
+			lookupType:  "github",
+			url:         "release-argus/Argus",
+			accessToken: "ghp_test",
+			asset: github_types.Asset{
+				URL:                "https://api.github.com/repos/release-argus/Argus/releases/assets/1",
+				BrowserDownloadURL: "https://github.com/release-argus/Argus/releases/download/1.2.3/argus"},
+			wantURL: "https://api.github.com/repos/release-argus/Argus/releases/assets/1",
+			wantHeaders: map[string]string{
+				"Accept":        "application/octet-stream",
+				"Authorization": "token ghp_test"},
+			errRegex: `^$`},
+		"github without an access token": {
+			lookupType: "github",
+			url:        "release-argus/Argus",
+			asset: github_types.Asset{
+				URL:                "https://api.github.com/repos/release-argus/Argus/releases/assets/1",
+				BrowserDownloadURL: "https://github.com/release-argus/Argus/releases/download/1.2.3/argus"},
+			wantURL: "https://github.com/release-argus/Argus/releases/download/1.2.3/argus",
+			wantHeaders: map[string]string{
+				"Accept":        "",
+				"Authorization": ""},
+			errRegex: `^$`},
+		"gitea asset on the queried host has the credentials": {
+			lookupType:  "gitea",
+			url:         "owner/repo",
+			baseURL:     "https://gitea.example.com",
+			accessToken: "secret",
+			asset: github_types.Asset{
+				BrowserDownloadURL: "https://gitea.example.com/owner/repo/releases/download/1.2.3/argus"},
+			wantURL: "https://gitea.example.com/owner/repo/releases/download/1.2.3/argus",
+			wantHeaders: map[string]string{
+				"Authorization": "token secret"},
+			errRegex: `^$`},
+		"gitea asset on another host doesn't have the credentials": {
+			lookupType:  "gitea",
+			url:         "owner/repo",
+			baseURL:     "https://gitea.example.com",
+			accessToken: "secret",
+			asset: github_types.Asset{
+				BrowserDownloadURL: "https://cdn.example.com/argus"},
+			wantURL: "https://cdn.example.com/argus",
+			wantHeaders: map[string]string{
+				"Authorization": ""},
+			errRegex: `^$`},
+		"no download URL": {
+			lookupType: "gitea",
+			url:        "owner/repo",
+			baseURL:    "https://gitea.example.com",
+			asset: github_types.Asset{
+				Name: "argus"},
+			errRegex: `^no download URL for "argus"$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup(false, false)
+			lookup.Type = tc.lookupType
+			lookup.URL = tc.url
+			lookup.BaseURL = tc.baseURL
+			lookup.AccessToken = stringPtr(tc.accessToken)
+
+			// WHEN assetRequest is called for the asset
+			req, err := lookup.assetRequest(context.Background(), &tc.asset)
+
+			// THEN the err is what we expect
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if err != nil {
+				return
+			}
+			// AND the asset is requested with the credentials only where they're wanted
+			if req.URL.String() != tc.wantURL {
+				t.Errorf("want URL %q, got %q",
+					tc.wantURL, req.URL.String())
+			}
+			for header, want := range tc.wantHeaders {
+				if got := req.Header.Get(header); got != want {
+					t.Errorf("want %s=%q, got %q",
+						header, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestNewReleaseInfo(t *testing.T) {
+	// GIVEN a release
+	tests := map[string]struct {
+		release *github_types.Release
+		want    util.ReleaseInfo
+	}{
+		"nil": {
+			release: nil,
+			want:    util.ReleaseInfo{}},
+		"no metadata": {
+			release: &github_types.Release{TagName: "1.2.3"},
+			want:    util.ReleaseInfo{}},
+		"all metadata": {
+			release: &github_types.Release{
+				TagName:     "v1.2.3",
+				Name:        "Argus v1.2.3",
+				Body:        "- Fixed a bug",
+				HTMLURL:     "https://github.com/release-argus/Argus/releases/tag/v1.2.3",
+				PublishedAt: "2023-01-01T00:00:00Z",
+				Author:      &github_types.User{Login: "someone"}},
+			want: util.ReleaseInfo{
+				Name:        "Argus v1.2.3",
+				Notes:       "- Fixed a bug",
+				URL:         "https://github.com/release-argus/Argus/releases/tag/v1.2.3",
+				Author:      "someone",
+				PublishedAt: "2023-01-01T00:00:00Z"}},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN newReleaseInfo is called on it
+			got := newReleaseInfo(tc.release)
+
+			// THEN the metadata of the release is returned
+			if got != tc.want {
+				t.Errorf("want %+v\ngot  %+v",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestLookup_GetVersionRegexNotes(t *testing.T) {
+	// GIVEN a Lookup and releases with release notes
+	githubBody := `[
+		{"tag_name": "1.2.0", "body": "DO NOT USE - known regression"},
+		{"tag_name": "1.1.0", "body": "Stable release"},
+		{"tag_name": "1.0.0", "body": "First release"}]`
+	urlBody := `<p>v1.2.0 - DO NOT USE</p>`
+	tests := map[string]struct {
+		urlType           bool
+		regexNotesInclude string
+		regexNotesExclude string
+		want              string
+		errRegex          string
+	}{
+		"github - no notes regex": {
+			want: "1.2.0"},
+		"github - exclude skips to the next release": {
+			regexNotesExclude: `(?i)do not use`,
+			want:              "1.1.0"},
+		"github - include skips to the next release": {
+			regexNotesInclude: `(?i)first`,
+			want:              "1.0.0"},
+		"github - no release satisfies the notes regex": {
+			regexNotesInclude: `(?i)beta`,
+			want:              "1.0.0", // last version checked
+			errRegex:          `regex_notes_include "\(\?i\)beta" not matched on the release notes for version "1.0.0"`},
+		"url - exclude checked against the page": {
+			urlType:           true,
+			regexNotesExclude: `DO NOT USE`,
+			want:              "1.2.0", // last version checked
+			errRegex:          `regex_notes_exclude "DO NOT USE" matched on the release notes for version "1.2.0"`},
+		"url - include checked against the page": {
+			urlType:           true,
+			regexNotesInclude: `v{{ version }} -`,
+			want:              "1.2.0"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			lookup := testLookup(tc.urlType, false)
+			body := githubBody
+			if tc.urlType {
+				body = urlBody
+			} else {
+				lookup.URLCommands = nil
+			}
+			lookup.Require = &filter.Require{
+				RegexNotesInclude: tc.regexNotesInclude,
+				RegexNotesExclude: tc.regexNotesExclude,
+				Status:            lookup.Status}
+
+			// WHEN GetVersion is called on the body
+			version, err := lookup.GetVersion([]byte(body), &util.LogFrom{})
+
+			// THEN the newest version whose notes satisfy the require is found
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if tc.errRegex == "" {
+				re = regexp.MustCompile(`^$`)
+			}
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if version != tc.want {
+				t.Errorf("want version %q, got %q",
+					tc.want, version)
+			}
+		})
+	}
+}
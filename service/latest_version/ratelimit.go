@@ -0,0 +1,198 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+	api_type "github.com/release-argus/Argus/web/api/types"
+	metric "github.com/release-argus/Argus/web/metrics"
+)
+
+// gitHubRateLimits is the GitHub API quota of each access token, shared by every Service.
+var gitHubRateLimits = rateLimits{
+	budgets: map[string]*rateLimit{}}
+
+// rateLimitReserve is the % of the quota that's kept back from Services of each priority.
+//
+// (Services are deferred until the reset when the quota remaining is at or below their reserve)
+var rateLimitReserve = map[string]int{
+	"low":    25,
+	"normal": 5,
+	"high":   0}
+
+// rateLimit is the API quota of an access token.
+type rateLimit struct {
+	token     string    // rateLimitKey of the access token
+	resource  string    // API resource, e.g. "core" (REST) or "graphql"
+	limit     int       // Requests allowed per window
+	remaining int       // Requests remaining in this window
+	reset     time.Time // Time the window resets
+}
+
+// rateLimits is the API quota of each access token.
+type rateLimits struct {
+	budgets map[string]*rateLimit // Quota of each access token and resource (by budgetKey)
+	mutex   sync.RWMutex          // Lock for the budgets
+}
+
+// rateLimitKey returns an identifier of `token` that's safe to expose.
+func rateLimitKey(token string) string {
+	if token == "" {
+		return "anonymous"
+	}
+	hash := sha256.Sum256([]byte(token))
+	return "token_" + hex.EncodeToString(hash[:])[:8]
+}
+
+// budgetKey returns the key of the quota of `token` for `resource`.
+func budgetKey(token string, resource string) string {
+	if resource == "" || resource == "core" {
+		return rateLimitKey(token)
+	}
+	return rateLimitKey(token) + "/" + resource
+}
+
+// update the quota of `token` from the rate limit headers of a response received at `now`.
+func (r *rateLimits) update(token string, header http.Header, now time.Time) {
+	remainingHeader := header.Get("X-RateLimit-Remaining")
+	retryAfter := parseRetryAfter(header.Get("Retry-After"), now)
+	if remainingHeader == "" && retryAfter == 0 {
+		return
+	}
+	resource := util.FirstNonDefault(header.Get("X-RateLimit-Resource"), "core")
+	key := budgetKey(token, resource)
+
+	r.mutex.Lock()
+	budget := r.budgets[key]
+	if budget == nil {
+		budget = &rateLimit{
+			token:    rateLimitKey(token),
+			resource: resource}
+		r.budgets[key] = budget
+	}
+	if remaining, err := strconv.Atoi(remainingHeader); err == nil {
+		budget.remaining = remaining
+	}
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		budget.limit = limit
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		budget.reset = time.Unix(reset, 0).UTC()
+	}
+	// Secondary rate limit - wait at least Retry-After.
+	if retryAfter != 0 {
+		budget.remaining = 0
+		if retryAt := now.Add(retryAfter).UTC(); retryAt.After(budget.reset) {
+			budget.reset = retryAt
+		}
+	}
+	remaining := budget.remaining
+	r.mutex.Unlock()
+
+	metric.SetPrometheusGauge(metric.GitHubRateLimitRemaining,
+		key,
+		float64(remaining))
+}
+
+// deferUntil returns the reset time of the quota of `token` for `resource` when it's too low
+// for a Service of `priority` to query at `now` (otherwise, the zero time).
+func (r *rateLimits) deferUntil(token string, resource string, priority string, now time.Time) time.Time {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	budget := r.budgets[budgetKey(token, resource)]
+	// Unknown, or the window has reset.
+	if budget == nil || !budget.reset.After(now) {
+		return time.Time{}
+	}
+
+	reserve := budget.limit * rateLimitReserve[priority] / 100
+	if budget.remaining > reserve {
+		return time.Time{}
+	}
+	return budget.reset
+}
+
+// summary of the quota of each access token.
+func (r *rateLimits) summary() []api_type.GitHubRateLimit {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if len(r.budgets) == 0 {
+		return nil
+	}
+	summary := make([]api_type.GitHubRateLimit, 0, len(r.budgets))
+	for _, key := range util.SortedKeys(r.budgets) {
+		budget := r.budgets[key]
+		rateLimit := api_type.GitHubRateLimit{
+			Token:     budget.token,
+			Resource:  budget.resource,
+			Limit:     budget.limit,
+			Remaining: budget.remaining}
+		if !budget.reset.IsZero() {
+			rateLimit.Reset = budget.reset.Format(time.RFC3339)
+		}
+		summary = append(summary, rateLimit)
+	}
+	return summary
+}
+
+// GitHubRateLimits returns the GitHub API quota remaining for each access token.
+func GitHubRateLimits() []api_type.GitHubRateLimit {
+	return gitHubRateLimits.summary()
+}
+
+// parseRetryAfter returns the wait of a Retry-After header (in seconds, or a HTTP date).
+func parseRetryAfter(retryAfter string, now time.Time) time.Duration {
+	if retryAfter == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// RateLimitDeferral returns the time to defer the next query of this Lookup until
+// when the GitHub API quota of its access token is too low for the priority of its Service
+// (otherwise, the zero time).
+func (l *Lookup) RateLimitDeferral() time.Time {
+	if l.Type != "github" {
+		return time.Time{}
+	}
+	token := util.DefaultIfNil(l.GetAccessToken())
+	resource := "core"
+	if l.usesGitHubGraphQL() {
+		resource = "graphql"
+	}
+	return gitHubRateLimits.deferUntil(
+		token,
+		resource,
+		l.Options.GetPriority(),
+		time.Now())
+}
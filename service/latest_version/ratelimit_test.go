@@ -0,0 +1,171 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metric "github.com/release-argus/Argus/web/metrics"
+)
+
+func TestRateLimits_DeferUntil(t *testing.T) {
+	// GIVEN the rate limit headers of a GitHub response
+	now := time.Date(2023, time.May, 17, 10, 0, 0, 0, time.UTC)
+	reset := now.Add(30 * time.Minute)
+	tests := map[string]struct {
+		headers  map[string]string
+		priority string
+		want     time.Time
+	}{
+		"no headers": {
+			priority: "low",
+			want:     time.Time{}},
+		"plenty remaining": {
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "4000",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10)},
+			priority: "low",
+			want:     time.Time{}},
+		"low remaining defers low priority": {
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "1000",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10)},
+			priority: "low",
+			want:     reset},
+		"low remaining doesn't defer normal priority": {
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "1000",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10)},
+			priority: "normal",
+			want:     time.Time{}},
+		"very low remaining defers normal priority": {
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "200",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10)},
+			priority: "normal",
+			want:     reset},
+		"very low remaining doesn't defer high priority": {
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "1",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10)},
+			priority: "high",
+			want:     time.Time{}},
+		"none remaining defers high priority": {
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10)},
+			priority: "high",
+			want:     reset},
+		"window already reset": {
+			headers: map[string]string{
+				"X-RateLimit-Limit":     "5000",
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)},
+			priority: "high",
+			want:     time.Time{}},
+		"retry-after seconds": {
+			headers: map[string]string{
+				"Retry-After": "60"},
+			priority: "high",
+			want:     now.Add(time.Minute)},
+		"retry-after date": {
+			headers: map[string]string{
+				"Retry-After": now.Add(2 * time.Minute).Format(http.TimeFormat)},
+			priority: "high",
+			want:     now.Add(2 * time.Minute)},
+		"retry-after before the reset": {
+			headers: map[string]string{
+				"X-RateLimit-Remaining": "100",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+				"Retry-After":           "60"},
+			priority: "high",
+			want:     reset},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			budgets := rateLimits{budgets: map[string]*rateLimit{}}
+			token := "ghp_" + name
+			header := http.Header{}
+			for key, value := range tc.headers {
+				header.Set(key, value)
+			}
+			budgets.update(token, header, now)
+
+			// WHEN deferUntil is called
+			got := budgets.deferUntil(token, "core", tc.priority, now)
+
+			// THEN the query is deferred until the reset when the quota is too low
+			if !got.Equal(tc.want) {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRateLimits_Summary(t *testing.T) {
+	// GIVEN rate limits for a couple of tokens
+	now := time.Date(2023, time.May, 17, 10, 0, 0, 0, time.UTC)
+	budgets := rateLimits{budgets: map[string]*rateLimit{}}
+	budgets.update("", http.Header{
+		"X-Ratelimit-Limit":     []string{"60"},
+		"X-Ratelimit-Remaining": []string{"59"},
+		"X-Ratelimit-Reset":     []string{strconv.FormatInt(now.Unix(), 10)}},
+		now)
+	token := "ghp_summary"
+	budgets.update(token, http.Header{
+		"X-Ratelimit-Limit":     []string{"5000"},
+		"X-Ratelimit-Remaining": []string{"4321"}},
+		now)
+
+	// WHEN summary is called
+	got := budgets.summary()
+
+	// THEN the quota of each token is returned without the token itself
+	if len(got) != 2 {
+		t.Fatalf("want 2 rate limits, got %d: %v",
+			len(got), got)
+	}
+	if got[0].Token != "anonymous" || got[0].Remaining != 59 || got[0].Limit != 60 ||
+		got[0].Reset != "2023-05-17T10:00:00Z" {
+		t.Errorf("unexpected anonymous rate limit: %+v",
+			got[0])
+	}
+	if got[1].Token != rateLimitKey(token) || got[1].Token == token || got[1].Remaining != 4321 {
+		t.Errorf("unexpected token rate limit: %+v",
+			got[1])
+	}
+	// AND the remaining quota is exposed as a Prometheus gauge
+	if gauge := testutil.ToFloat64(metric.GitHubRateLimitRemaining.WithLabelValues(rateLimitKey(token))); gauge != 4321 {
+		t.Errorf("want GitHubRateLimitRemaining gauge of 4321, not %v",
+			gauge)
+	}
+}
@@ -96,8 +96,17 @@ func (l *Lookup) applyOverrides(
 		useUsePreRelease,
 		l.Defaults,
 		l.HardDefaults)
+	lookup.BaseURL = l.BaseURL
+	lookup.UseTags = l.UseTags
+	lookup.UseGraphQL = l.UseGraphQL
+	lookup.Username = l.Username
+	lookup.Chart = l.Chart
+	lookup.UseAppVersion = l.UseAppVersion
+	lookup.FeedElement = l.FeedElement
 	lookup.Status = &svcstatus.Status{
 		ServiceID: serviceID}
+	lookup.Options.VersionConstraint = l.Options.VersionConstraint
+	lookup.Options.VersionScheme = l.Options.VersionScheme
 	lookup.Options.Defaults = l.Options.Defaults
 	lookup.Options.HardDefaults = l.Options.HardDefaults
 	lookup.Status.Init(
@@ -105,6 +114,9 @@ func (l *Lookup) applyOverrides(
 		serviceID,
 		nil)
 	lookup.Status.SetLatestVersion(l.Status.LatestVersion(), false)
+	lookup.Status.SetPendingVersion(l.Status.PendingVersion(), l.Status.PendingVersionTimestamp(), false)
+	// Use the cached body of the URL (if unchanged, the new require/url_commands are applied without a refetch)
+	lookup.Status.SetLatestVersionCache(l.Status.LatestVersionCache(), false)
 
 	if lookup.Type == "github" {
 		// Use the current ETag/releases
@@ -180,7 +192,11 @@ func (l *Lookup) Refresh(
 		usePreRelease != nil
 
 	// Query the lookup.
-	_, err = lookup.Query(!overrides, &logFrom)
+	_, err = lookup.Query(false, &logFrom)
+	// Count the query on this Lookup rather than the temporary one.
+	if !overrides {
+		l.queryResult(err)
+	}
 	if err != nil {
 		return
 	}
@@ -225,11 +241,15 @@ func (l *Lookup) updateFromRefresh(newLookup *Lookup, changingOverrides bool) (a
 
 	// Update the last queried time.
 	l.Status.SetLastQueried(newLookup.Status.LastQueried())
+	// Update the version waiting for require.min_age.
+	l.Status.SetPendingVersion(newLookup.Status.PendingVersion(), newLookup.Status.PendingVersionTimestamp(), true)
 	// Update the latest version if it has changed.
 	newLatestVersion := newLookup.Status.LatestVersion()
 	if newLatestVersion != l.Status.LatestVersion() {
 		announceUpdate = true
 		l.Status.SetLatestVersion(newLatestVersion, true)
 	}
+	l.Status.SetLatestVersionAsset(newLookup.Status.LatestVersionAsset())
+	l.Status.SetLatestVersionRelease(newLookup.Status.LatestVersionRelease())
 	return
 }
@@ -275,6 +275,18 @@ func TestLookup_ApplyOverrides(t *testing.T) {
 				&LookupDefaults{},
 				&LookupDefaults{}),
 		},
+		"use_graphql carried over": {
+			previous: func() *Lookup {
+				lookup := testLookup(false, true)
+				lookup.UseGraphQL = boolPtr(true)
+				return lookup
+			}(),
+			want: func() *Lookup {
+				lookup := testLookup(false, true)
+				lookup.UseGraphQL = boolPtr(true)
+				return lookup
+			}(),
+		},
 		"override with invalid (empty) url": {
 			url:      stringPtr(""),
 			previous: testLookup(true, true),
@@ -0,0 +1,250 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package latestver
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	github_types "github.com/release-argus/Argus/service/latest_version/api_type"
+	opt "github.com/release-argus/Argus/service/options"
+	"github.com/release-argus/Argus/util"
+)
+
+// pep440PreReleaseRegex matches PEP 440 pre-releases and dev-releases, e.g. 1.0a1, 1.0rc1, 1.0.dev2.
+var pep440PreReleaseRegex = regexp.MustCompile(`(?i)[0-9][._-]?(a|alpha|b|beta|c|rc|pre|preview|dev)[._-]?[0-9]*`)
+
+// checkRegistryBody will check that the body is of the expected format for the registry type
+// and convert every version of the package to a Release.
+func (l *Lookup) checkRegistryBody(body *[]byte, logFrom *util.LogFrom) (releases []github_types.Release, err error) {
+	switch l.Type {
+	case "crates":
+		releases, err = l.cratesReleases(body)
+	case "goproxy":
+		releases = l.goProxyReleases(body)
+	case "npm":
+		releases, err = l.npmReleases(body)
+	case "pypi":
+		releases, err = l.pypiReleases(body)
+	}
+	if err != nil {
+		err = fmt.Errorf("unmarshal of %s registry data failed\n%w",
+			l.Type, err)
+		jLog.Error(err, *logFrom, true)
+	}
+	return
+}
+
+// cratesReleases converts a crates.io crate into Releases (excluding yanked versions).
+func (l *Lookup) cratesReleases(body *[]byte) (releases []github_types.Release, err error) {
+	var crate github_types.CratesCrate
+	if err = json.Unmarshal(*body, &crate); err != nil {
+		return
+	}
+
+	// Versions are newest published first.
+	releases = make([]github_types.Release, 0, len(crate.Versions))
+	for _, version := range crate.Versions {
+		if version.Yanked {
+			continue
+		}
+		release := github_types.Release{
+			TagName:    version.Num,
+			PreRelease: l.isPreRelease(version.Num, isSemVerPreRelease)}
+		if version.DLPath != "" {
+			release.Assets = []github_types.Asset{{
+				ID:                 version.ID,
+				Name:               fmt.Sprintf("%s-%s.crate", l.URL, version.Num),
+				BrowserDownloadURL: l.GetBaseURL() + version.DLPath}}
+		}
+		releases = append(releases, release)
+	}
+	l.sortReleases(releases)
+	return
+}
+
+// goProxyReleases converts the newline-separated version list of a Go module proxy into Releases.
+//
+// Go module versions are always prefixed with a 'v', which is removed.
+func (l *Lookup) goProxyReleases(body *[]byte) (releases []github_types.Release) {
+	// Proxies don't order the list, and have no publish times.
+	versions := strings.Fields(string(*body))
+
+	releases = make([]github_types.Release, len(versions))
+	for i := range versions {
+		version := strings.TrimPrefix(versions[i], "v")
+		releases[i] = github_types.Release{
+			TagName:    version,
+			PreRelease: l.isPreRelease(version, isSemVerPreRelease)}
+	}
+	l.sortReleases(releases)
+	return
+}
+
+// npmReleases converts an npm package into Releases (excluding deprecated versions).
+//
+// The version with the 'latest' dist-tag will be first.
+func (l *Lookup) npmReleases(body *[]byte) (releases []github_types.Release, err error) {
+	var pkg github_types.NPMPackage
+	if err = json.Unmarshal(*body, &pkg); err != nil {
+		return
+	}
+	if len(pkg.Versions) == 0 {
+		err = fmt.Errorf("no versions found for %q", l.URL)
+		return
+	}
+
+	versions := util.SortedKeys(pkg.Versions)
+	sortNewestPublished(versions, pkg.Time)
+
+	releases = make([]github_types.Release, 0, len(versions))
+	for _, version := range versions {
+		npmVersion := pkg.Versions[version]
+		if npmVersion.Deprecated != nil {
+			continue
+		}
+		release := github_types.Release{
+			TagName:    version,
+			PreRelease: l.isPreRelease(version, isSemVerPreRelease)}
+		if npmVersion.Dist.Tarball != "" {
+			release.Assets = []github_types.Asset{{
+				Name:               path.Base(npmVersion.Dist.Tarball),
+				BrowserDownloadURL: npmVersion.Dist.Tarball}}
+		}
+		releases = append(releases, release)
+	}
+	l.sortReleases(releases)
+	latest := pkg.DistTags["latest"]
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].TagName == latest && releases[j].TagName != latest
+	})
+	return
+}
+
+// pypiReleases converts a PyPI package into Releases (excluding versions where every file has been yanked).
+func (l *Lookup) pypiReleases(body *[]byte) (releases []github_types.Release, err error) {
+	var pkg github_types.PyPIPackage
+	if err = json.Unmarshal(*body, &pkg); err != nil {
+		return
+	}
+	if len(pkg.Releases) == 0 {
+		err = fmt.Errorf("no releases found for %q", l.URL)
+		return
+	}
+
+	versions := util.SortedKeys(pkg.Releases)
+	uploaded := make(map[string]string, len(versions))
+	for _, version := range versions {
+		for _, file := range pkg.Releases[version] {
+			if file.UploadTime > uploaded[version] {
+				uploaded[version] = file.UploadTime
+			}
+		}
+	}
+	sortNewestPublished(versions, uploaded)
+	releases = make([]github_types.Release, 0, len(versions))
+	for _, version := range versions {
+		files := pkg.Releases[version]
+		assets := make([]github_types.Asset, 0, len(files))
+		for _, file := range files {
+			if file.Yanked {
+				continue
+			}
+			assets = append(assets, github_types.Asset{
+				Name:               file.Filename,
+				BrowserDownloadURL: file.URL})
+		}
+		// Every file was yanked.
+		if len(files) != 0 && len(assets) == 0 {
+			continue
+		}
+
+		releases = append(releases, github_types.Release{
+			TagName:    version,
+			PreRelease: l.isPreRelease(version, pep440PreReleaseRegex.MatchString),
+			Assets:     assets})
+	}
+	l.sortReleases(releases)
+	return
+}
+
+// sortNewestPublished sorts `versions` by their `published` time (RFC 3339), newest first.
+//
+// Versions without a publish time go last.
+func sortNewestPublished(versions []string, published map[string]string) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return published[versions[i]] > published[versions[j]]
+	})
+}
+
+// parseVersion will parse `version` with the version_scheme of this Lookup.
+func (l *Lookup) parseVersion(version string) (opt.Version, error) {
+	if l.Options == nil {
+		return opt.ParseVersion("", version) //nolint:wrapcheck
+	}
+	return l.Options.ParseVersion(version) //nolint:wrapcheck
+}
+
+// sortReleases sorts `releases` newest first by the version_scheme of this Lookup.
+//
+// If the scheme can't parse every version, they're left in the order given (the registry's publish order).
+func (l *Lookup) sortReleases(releases []github_types.Release) {
+	parsed := make(map[string]opt.Version, len(releases))
+	for _, release := range releases {
+		version, err := l.parseVersion(release.TagName)
+		if err != nil {
+			return
+		}
+		parsed[release.TagName] = version
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return parsed[releases[j].TagName].LessThan(parsed[releases[i].TagName])
+	})
+}
+
+// isPreRelease returns whether `version` is a pre-release in the version_scheme of this Lookup,
+// or by `fallback` when the scheme can't parse it.
+func (l *Lookup) isPreRelease(version string, fallback func(string) bool) bool {
+	if parsed, err := l.parseVersion(version); err == nil {
+		return parsed.PreRelease()
+	}
+	return fallback(version)
+}
+
+// isSemVerPreRelease returns whether `version` has a pre-release suffix, e.g. 1.2.3-rc.1.
+func isSemVerPreRelease(version string) bool {
+	version, _, _ = strings.Cut(version, "+")
+	return strings.Contains(version, "-")
+}
+
+// goModuleEscape the module path for use in a Go module proxy URL.
+//
+// Uppercase letters are replaced with an exclamation mark followed by the lowercase letter.
+func goModuleEscape(module string) string {
+	var escaped strings.Builder
+	for _, r := range module {
+		if unicode.IsUpper(r) {
+			escaped.WriteRune('!')
+			r = unicode.ToLower(r)
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
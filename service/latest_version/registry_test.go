@@ -0,0 +1,278 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package latestver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	opt "github.com/release-argus/Argus/service/options"
+	"github.com/release-argus/Argus/util"
+)
+
+func TestLookup_CheckRegistryBody(t *testing.T) {
+	// GIVEN a registry body
+	tests := map[string]struct {
+		lType          string
+		scheme         string
+		body           string
+		want           []string
+		wantPreRelease []bool
+		errRegex       string
+	}{
+		"crates - yanked excluded": {
+			lType: "crates",
+			body: `{"versions":[
+				{"id":3,"num":"1.1.0-beta.1","yanked":false,"dl_path":"/api/v1/crates/serde/1.1.0-beta.1/download"},
+				{"id":2,"num":"1.0.1","yanked":true},
+				{"id":1,"num":"1.0.0","yanked":false}]}`,
+			want:           []string{"1.1.0-beta.1", "1.0.0"},
+			wantPreRelease: []bool{true, false},
+			errRegex:       `^$`},
+		"crates - invalid json": {
+			lType:    "crates",
+			body:     `not found`,
+			errRegex: `unmarshal of crates registry data failed`},
+		"goproxy - ordered by version, not lexically": {
+			lType:          "goproxy",
+			body:           "v1.9.0\nv1.10.0\n",
+			want:           []string{"1.10.0", "1.9.0"},
+			wantPreRelease: []bool{false, false},
+			errRegex:       `^$`},
+		"goproxy - version_scheme decides the pre-releases": {
+			lType:          "goproxy",
+			scheme:         "debian",
+			body:           "v1.2-2\nv1.2-3\nv1.3~rc1\n",
+			want:           []string{"1.3~rc1", "1.2-3", "1.2-2"},
+			wantPreRelease: []bool{true, false, false},
+			errRegex:       `^$`},
+		"goproxy - 'v' trimmed": {
+			lType:          "goproxy",
+			body:           "v0.6.0\nv0.7.0-rc.1\nv0.5.0\n",
+			want:           []string{"0.7.0-rc.1", "0.6.0", "0.5.0"},
+			wantPreRelease: []bool{true, false, false},
+			errRegex:       `^$`},
+		"npm - latest first and deprecated excluded": {
+			lType: "npm",
+			body: `{"dist-tags":{"latest":"1.0.0","next":"2.0.0-rc.1"},"versions":{
+				"0.9.0":{"version":"0.9.0","deprecated":"broken"},
+				"1.0.0":{"version":"1.0.0","dist":{"tarball":"https://registry.npmjs.org/pkg/-/pkg-1.0.0.tgz"}},
+				"2.0.0-rc.1":{"version":"2.0.0-rc.1"}}}`,
+			want:           []string{"1.0.0", "2.0.0-rc.1"},
+			wantPreRelease: []bool{false, true},
+			errRegex:       `^$`},
+		"npm - ordered by version after latest": {
+			lType: "npm",
+			body: `{"dist-tags":{"latest":"1.10.0"},"versions":{
+				"1.9.0":{"version":"1.9.0"},
+				"1.10.0":{"version":"1.10.0"},
+				"1.11.0-rc.1":{"version":"1.11.0-rc.1"}}}`,
+			want:           []string{"1.10.0", "1.11.0-rc.1", "1.9.0"},
+			wantPreRelease: []bool{false, true, false},
+			errRegex:       `^$`},
+		"npm - publish order when the version_scheme can't parse every version": {
+			lType: "npm",
+			body: `{"dist-tags":{},"versions":{
+				"1.0":{"version":"1.0"},
+				"0.9.0":{"version":"0.9.0"}},
+				"time":{"0.9.0":"2023-05-17T10:00:00.000Z","1.0":"2023-06-17T10:00:00.000Z"}}`,
+			want:           []string{"1.0", "0.9.0"},
+			wantPreRelease: []bool{false, false},
+			errRegex:       `^$`},
+		"npm - no versions": {
+			lType:    "npm",
+			body:     `{"error":"Not found"}`,
+			errRegex: `no versions found`},
+		"pypi - fully yanked excluded": {
+			lType: "pypi",
+			body: `{"releases":{
+				"2.0.0rc1":[{"filename":"pkg-2.0.0rc1.tar.gz","url":"https://files.example.com/pkg-2.0.0rc1.tar.gz","upload_time_iso_8601":"2023-06-17T10:00:00.000000Z"}],
+				"1.1.0":[{"filename":"pkg-1.1.0.tar.gz","yanked":true,"upload_time_iso_8601":"2023-05-17T10:00:00.000000Z"}],
+				"1.0.0":[{"filename":"pkg-1.0.0.tar.gz","yanked":true,"upload_time_iso_8601":"2023-04-17T10:00:00.000000Z"},{"filename":"pkg-1.0.0-py3-none-any.whl","upload_time_iso_8601":"2023-04-17T10:00:00.000000Z"}]}}`,
+			want:           []string{"2.0.0rc1", "1.0.0"},
+			wantPreRelease: []bool{true, false},
+			errRegex:       `^$`},
+		"pypi - ordered by the pep440 version_scheme": {
+			lType:  "pypi",
+			scheme: "pep440",
+			body: `{"releases":{
+				"1.9.0":[],
+				"1.10.0":[],
+				"1.11rc1":[]}}`,
+			want:           []string{"1.11rc1", "1.10.0", "1.9.0"},
+			wantPreRelease: []bool{true, false, false},
+			errRegex:       `^$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			body := []byte(tc.body)
+			lookup := Lookup{
+				Type:    tc.lType,
+				URL:     "pkg",
+				Options: &opt.Options{}}
+			lookup.Options.VersionScheme = tc.scheme
+
+			// WHEN checkRegistryBody is called on this body
+			releases, err := lookup.checkRegistryBody(&body, &util.LogFrom{})
+
+			// THEN it err's when expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the versions are converted to releases
+			if len(releases) != len(tc.want) {
+				t.Fatalf("want %d releases, got %d\n%v",
+					len(tc.want), len(releases), releases)
+			}
+			for i := range tc.want {
+				if releases[i].TagName != tc.want[i] ||
+					releases[i].PreRelease != tc.wantPreRelease[i] {
+					t.Errorf("release %d - want %q (prerelease=%t), got %q (prerelease=%t)",
+						i, tc.want[i], tc.wantPreRelease[i], releases[i].TagName, releases[i].PreRelease)
+				}
+			}
+		})
+	}
+}
+
+func TestLookup_QueryRegistry(t *testing.T) {
+	// GIVEN a registry Lookup and a mirror of that registry
+	tests := map[string]struct {
+		lType       string
+		url         string
+		wantPath    string
+		body        string
+		wantVersion string
+		errRegex    string
+	}{
+		"crates": {
+			lType:       "crates",
+			url:         "serde",
+			wantPath:    "/api/v1/crates/serde",
+			body:        `{"versions":[{"num":"1.0.2"},{"num":"1.0.10"},{"num":"1.0.3"}]}`,
+			wantVersion: "1.0.10",
+			errRegex:    `^$`},
+		"goproxy": {
+			lType:       "goproxy",
+			url:         "github.com/BurntSushi/toml",
+			wantPath:    "/github.com/!burnt!sushi/toml/@v/list",
+			body:        "v1.2.0\nv1.10.0\nv1.3.0",
+			wantVersion: "1.10.0",
+			errRegex:    `^$`},
+		"npm": {
+			lType:       "npm",
+			url:         "@types/node",
+			wantPath:    "/@types%2Fnode",
+			body:        `{"dist-tags":{"latest":"20.1.0"},"versions":{"20.1.0":{},"20.0.9":{},"21.0.0-beta.1":{}}}`,
+			wantVersion: "20.1.0",
+			errRegex:    `^$`},
+		"pypi": {
+			lType:       "pypi",
+			url:         "requests",
+			wantPath:    "/pypi/requests/json",
+			body:        `{"releases":{"2.9.0":[],"2.31.0":[],"2.32.0rc1":[]}}`,
+			wantVersion: "2.31.0",
+			errRegex:    `^$`},
+		"package not found": {
+			lType:    "pypi",
+			url:      "does-not-exist",
+			wantPath: "/pypi/requests/json",
+			errRegex: `pypi query for "does-not-exist" failed: 404 Not Found`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.EscapedPath() != tc.wantPath {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(tc.body))
+			}))
+			defer server.Close()
+			lookup := testLookup(false, false)
+			lookup.Type = tc.lType
+			lookup.URL = tc.url
+			lookup.BaseURL = server.URL
+			lookup.URLCommands = nil
+			if err := lookup.CheckValues(""); err != nil {
+				t.Fatalf("CheckValues failed: %v", err)
+			}
+
+			// WHEN Query is called on it
+			_, err := lookup.Query(false, &util.LogFrom{})
+
+			// THEN any err is expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the newest non-prerelease version is found
+			if got := lookup.Status.LatestVersion(); got != tc.wantVersion {
+				t.Errorf("want version %q, got %q",
+					tc.wantVersion, got)
+			}
+		})
+	}
+}
+
+func TestGoModuleEscape(t *testing.T) {
+	// GIVEN a module path
+	tests := map[string]struct {
+		module string
+		want   string
+	}{
+		"lowercase": {
+			module: "golang.org/x/mod",
+			want:   "golang.org/x/mod"},
+		"uppercase": {
+			module: "github.com/BurntSushi/toml",
+			want:   "github.com/!burnt!sushi/toml"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN goModuleEscape is called on it
+			got := goModuleEscape(tc.module)
+
+			// THEN the module path is escaped
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
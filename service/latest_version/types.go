@@ -30,9 +30,10 @@ var (
 
 // LookupBase is the base struct for a Lookup.
 type LookupBase struct {
-	AccessToken       *string `yaml:"access_token,omitempty" json:"access_token,omitempty"`               // GitHub access token to use
+	AccessToken       *string `yaml:"access_token,omitempty" json:"access_token,omitempty"`               // GitHub access token to use (type:gitlab - private token, type:gitea - access token, type:container - registry password/token, type:git - password)
 	AllowInvalidCerts *bool   `yaml:"allow_invalid_certs,omitempty" json:"allow_invalid_certs,omitempty"` // default - false = Disallows invalid HTTPS certificates
 	UsePreRelease     *bool   `yaml:"use_prerelease,omitempty" json:"use_prerelease,omitempty"`           // Whether the prerelease tag should be used
+	UseGraphQL        *bool   `yaml:"use_graphql,omitempty" json:"use_graphql,omitempty"`                 // type:github - Query the releases with other services of the access_token in batched GraphQL requests
 }
 
 // LookupDefaults are the default values for a Lookup.
@@ -62,13 +63,20 @@ func NewDefaults(
 }
 
 type Lookup struct {
-	Type        string `yaml:"type,omitempty" json:"type,omitempty"` // "github"/"URL"
-	URL         string `yaml:"url,omitempty" json:"url,omitempty"`   // type:URL - "https://example.com", type:github - "owner/repo" or "https://github.com/owner/repo".
-	LookupBase  `yaml:",inline" json:",inline"`
-	URLCommands filter.URLCommandSlice `yaml:"url_commands,omitempty" json:"url_commands,omitempty"` // Commands to filter the release from the URL request
-	Require     *filter.Require        `yaml:"require,omitempty" json:"require,omitempty"`           // Options to require before a release is considered valid
-
-	GitHubData *GitHubData `yaml:"-" json:"-"` // GitHub Conditional Request vars
+	Type          string `yaml:"type,omitempty" json:"type,omitempty"`                       // "container"/"crates"/"feed"/"git"/"gitea"/"github"/"gitlab"/"goproxy"/"helm"/"npm"/"pypi"/"URL"
+	URL           string `yaml:"url,omitempty" json:"url,omitempty"`                         // type:URL - "https://example.com", type:github - "owner/repo" or "https://github.com/owner/repo", type:gitlab - "group/project" or "https://gitlab.com/group/project", type:gitea - "owner/repo" or "https://gitea.example.com/owner/repo", type:crates/goproxy/npm/pypi - package name, e.g. "serde"/"golang.org/x/mod"/"@types/node"/"requests", type:container - image, e.g. "ghcr.io/release-argus/argus" or "prometheus" (Docker Hub), type:helm - chart repository, e.g. "https://charts.example.com" (or its index.yaml), type:feed - RSS/Atom feed, e.g. "https://example.com/releases.atom", type:git - repository, e.g. "https://git.example.com/project.git".
+	BaseURL       string `yaml:"base_url,omitempty" json:"base_url,omitempty"`               // type:gitlab - "https://gitlab.example.com" (default - https://gitlab.com), type:gitea - "https://gitea.example.com", type:crates/goproxy/npm/pypi - registry mirror (default - the public registry), type:container - registry (default - the registry of the image)
+	UseTags       *bool  `yaml:"use_tags,omitempty" json:"use_tags,omitempty"`               // type:gitlab - Query the tags rather than the releases
+	Username      string `yaml:"username,omitempty" json:"username,omitempty"`               // type:container - Username to get a registry token with, type:git - Username for Basic Auth (access_token being the password)
+	Chart         string `yaml:"chart,omitempty" json:"chart,omitempty"`                     // type:helm - Chart in the repository index to track
+	UseAppVersion *bool  `yaml:"use_app_version,omitempty" json:"use_app_version,omitempty"` // type:helm - Track the appVersion of the chart rather than the chart version
+	FeedElement   string `yaml:"feed_element,omitempty" json:"feed_element,omitempty"`       // type:feed - Element of each item to get the version from, "category"/"guid"/"link"/"title" (default - title)
+	LookupBase    `yaml:",inline" json:",inline"`
+	URLCommands   filter.URLCommandSlice `yaml:"url_commands,omitempty" json:"url_commands,omitempty"` // Commands to filter the release from the URL request
+	Require       *filter.Require        `yaml:"require,omitempty" json:"require,omitempty"`           // Options to require before a release is considered valid
+
+	GitHubData   *GitHubData          `yaml:"-" json:"-"` // GitHub Conditional Request vars
+	registryAuth *filter.RegistryAuth // type:container - Registry token for queries
 
 	Options *opt.Options      `yaml:"-" json:"-"` // Options
 	Status  *svcstatus.Status `yaml:"-" json:"-"` // Service Status
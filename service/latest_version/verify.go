@@ -16,11 +16,15 @@ package latestver
 
 import (
 	"fmt"
+	net_url "net/url"
 	"strings"
 
 	"github.com/release-argus/Argus/util"
 )
 
+var lookupTypes = []string{
+	"container", "crates", "feed", "git", "gitea", "github", "gitlab", "goproxy", "helm", "npm", "pypi", "url"}
+
 // CheckValues of the LookupDefaults struct
 func (l *LookupDefaults) CheckValues(prefix string) (errs error) {
 	if requireErrs := l.Require.CheckValues(prefix + "  "); requireErrs != nil {
@@ -42,17 +46,78 @@ func (l *Lookup) CheckValues(prefix string) (errs error) {
 			errs = fmt.Errorf("%s%s  url: <required> e.g. github:'release-argus/Argus' or url:'https://example.com'\\",
 				util.ErrorToString(errs), prefix)
 		}
-	} else if l.Type != "url" && l.Type != "github" {
+	} else if !util.Contains(lookupTypes, l.Type) {
 		errType := "<required>"
 		if l.Type != "" {
 			errType = fmt.Sprintf("%q <invalid>", l.Type)
 		}
-		errs = fmt.Errorf("%s%s  type: %s e.g. github or url\\",
-			util.ErrorToString(errs), prefix, errType)
+		errs = fmt.Errorf("%s%s  type: %s e.g. github or url (supported types = [%s])\\",
+			util.ErrorToString(errs), prefix, errType, strings.Join(lookupTypes, ","))
 	}
-	if l.Type == "github" && strings.Count(l.URL, "/") > 1 {
-		parts := strings.Split(l.URL, "/")
-		l.URL = strings.Join(parts[len(parts)-2:], "/")
+	switch l.Type {
+	case "github":
+		if strings.Count(l.URL, "/") > 1 {
+			parts := strings.Split(l.URL, "/")
+			l.URL = strings.Join(parts[len(parts)-2:], "/")
+		}
+	case "gitlab":
+		// "https://gitlab.example.com/group/project" -> base_url + "group/project"
+		if parsedURL, err := net_url.Parse(l.URL); err == nil && parsedURL.Host != "" {
+			if l.BaseURL == "" {
+				l.BaseURL = fmt.Sprintf("%s://%s", parsedURL.Scheme, parsedURL.Host)
+			}
+			// Trim any "/-/releases" suffix
+			l.URL = strings.Trim(strings.Split(parsedURL.Path, "/-/")[0], "/")
+		}
+	case "container":
+		// "ghcr.io/owner/image" -> base_url + "owner/image"
+		registryURL, repository := containerImage(l.URL)
+		if l.BaseURL == "" {
+			l.BaseURL = registryURL
+		}
+		l.URL = repository
+		// e.g. prometheus = library/prometheus on Docker Hub
+		if l.BaseURL == "" && l.URL != "" && !strings.Contains(l.URL, "/") {
+			l.URL = "library/" + l.URL
+		}
+	case "feed":
+		if l.FeedElement != "" && !util.Contains(feedElements, l.FeedElement) {
+			errs = fmt.Errorf("%s%s  feed_element: %q <invalid> (supported elements = [%s])\\",
+				util.ErrorToString(errs), prefix, l.FeedElement, strings.Join(feedElements, ","))
+		}
+	case "helm":
+		if l.Chart == "" {
+			errs = fmt.Errorf("%s%s  chart: <required> (chart in the repository index to track)\\",
+				util.ErrorToString(errs), prefix)
+		}
+	case "gitea":
+		// "https://gitea.example.com/owner/repo" -> base_url + "owner/repo"
+		if parsedURL, err := net_url.Parse(l.URL); err == nil && parsedURL.Host != "" {
+			// Trim any "/releases" suffix
+			path := strings.Trim(strings.Split(parsedURL.Path, "/releases")[0], "/")
+			parts := strings.Split(path, "/")
+			if len(parts) >= 2 {
+				if l.BaseURL == "" {
+					// Gitea may be served from a sub-path
+					l.BaseURL = strings.TrimSuffix(
+						fmt.Sprintf("%s://%s/%s",
+							parsedURL.Scheme, parsedURL.Host, strings.Join(parts[:len(parts)-2], "/")),
+						"/")
+				}
+				l.URL = strings.Join(parts[len(parts)-2:], "/")
+			}
+		}
+		if l.BaseURL == "" {
+			errs = fmt.Errorf("%s%s  base_url: <required> e.g. 'https://gitea.example.com'\\",
+				util.ErrorToString(errs), prefix)
+		}
+	}
+	// Base URL
+	if l.BaseURL != "" {
+		if _, err := net_url.ParseRequestURI(l.BaseURL); err != nil {
+			errs = fmt.Errorf("%s%s  base_url: %q <invalid> (must be a URL, e.g. 'https://git.example.com')\\",
+				util.ErrorToString(errs), prefix, l.BaseURL)
+		}
 	}
 
 	if requireErrs := l.Require.CheckValues(prefix + "  "); requireErrs != nil {
@@ -85,6 +85,9 @@ func TestLookup_CheckValues(t *testing.T) {
 		lType       *string
 		url         *string
 		wantURL     *string
+		baseURL     *string
+		wantBaseURL *string
+		feedElement *string
 		require     *filter.Require
 		urlCommands *filter.URLCommandSlice
 		errRegex    []string
@@ -115,6 +118,80 @@ func TestLookup_CheckValues(t *testing.T) {
 			url:      stringPtr("https://github.com/release-argus/Argus"),
 			wantURL:  stringPtr("release-argus/Argus"),
 		},
+		"corrects gitlab url": {
+			errRegex:    []string{},
+			lType:       stringPtr("gitlab"),
+			url:         stringPtr("https://gitlab.example.com/group/sub-group/project/-/releases"),
+			wantURL:     stringPtr("group/sub-group/project"),
+			wantBaseURL: stringPtr("https://gitlab.example.com"),
+		},
+		"gitlab url doesn't override base_url": {
+			errRegex:    []string{},
+			lType:       stringPtr("gitlab"),
+			url:         stringPtr("https://gitlab.com/group/project"),
+			baseURL:     stringPtr("https://gitlab.example.com/gitlab"),
+			wantURL:     stringPtr("group/project"),
+			wantBaseURL: stringPtr("https://gitlab.example.com/gitlab"),
+		},
+		"invalid gitlab base_url": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  base_url: "[^"]+" <invalid>`},
+			lType:   stringPtr("gitlab"),
+			url:     stringPtr("group/project"),
+			baseURL: stringPtr("gitlab.example.com"),
+		},
+		"corrects gitea url": {
+			errRegex:    []string{},
+			lType:       stringPtr("gitea"),
+			url:         stringPtr("https://git.example.com/gitea/owner/repo/releases"),
+			wantURL:     stringPtr("owner/repo"),
+			wantBaseURL: stringPtr("https://git.example.com/gitea"),
+		},
+		"gitea requires base_url": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  base_url: <required>`},
+			lType: stringPtr("gitea"),
+			url:   stringPtr("owner/repo"),
+		},
+		"corrects container image": {
+			errRegex:    []string{},
+			lType:       stringPtr("container"),
+			url:         stringPtr("ghcr.io/release-argus/argus"),
+			wantURL:     stringPtr("release-argus/argus"),
+			wantBaseURL: stringPtr("https://ghcr.io"),
+		},
+		"container official docker hub image": {
+			errRegex:    []string{},
+			lType:       stringPtr("container"),
+			url:         stringPtr("docker.io/prometheus"),
+			wantURL:     stringPtr("library/prometheus"),
+			wantBaseURL: stringPtr(""),
+		},
+		"container image on base_url registry": {
+			errRegex:    []string{},
+			lType:       stringPtr("container"),
+			url:         stringPtr("argus"),
+			baseURL:     stringPtr("http://localhost:5000"),
+			wantURL:     stringPtr("argus"),
+			wantBaseURL: stringPtr("http://localhost:5000"),
+		},
+		"invalid feed_element": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  feed_element: "description" <invalid>`},
+			lType:       stringPtr("feed"),
+			url:         stringPtr("https://example.com/feed"),
+			feedElement: stringPtr("description"),
+		},
+		"helm requires chart": {
+			errRegex: []string{
+				`^latest_version:$`,
+				`^  chart: <required>`},
+			lType: stringPtr("helm"),
+			url:   stringPtr("https://charts.example.com"),
+		},
 		"invalid require": {
 			errRegex: []string{
 				`^latest_version:$`,
@@ -157,6 +234,12 @@ func TestLookup_CheckValues(t *testing.T) {
 			if tc.url != nil {
 				lookup.URL = *tc.url
 			}
+			if tc.baseURL != nil {
+				lookup.BaseURL = *tc.baseURL
+			}
+			if tc.feedElement != nil {
+				lookup.FeedElement = *tc.feedElement
+			}
 			if tc.require != nil {
 				lookup.Require = tc.require
 			}
@@ -182,6 +265,15 @@ func TestLookup_CheckValues(t *testing.T) {
 						lines[i], tc.errRegex[i], e)
 				}
 			}
+			// AND the url/base_url are corrected when expected
+			if tc.wantURL != nil && lookup.URL != *tc.wantURL {
+				t.Errorf("want url %q, got %q",
+					*tc.wantURL, lookup.URL)
+			}
+			if tc.wantBaseURL != nil && lookup.BaseURL != *tc.wantBaseURL {
+				t.Errorf("want base_url %q, got %q",
+					*tc.wantBaseURL, lookup.BaseURL)
+			}
 		})
 	}
 }
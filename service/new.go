@@ -325,6 +325,10 @@ func (s *Service) giveSecrets(oldService *Service, secretRefs oldSecretRefs) {
 	// Command
 	s.CommandController.CopyFailsFrom(oldService.CommandController)
 
+	// Keep the cached responses (they're only used for requests of the same URL)
+	s.Status.SetLatestVersionCache(oldService.Status.LatestVersionCache(), false)
+	s.Status.SetDeployedVersionCache(oldService.Status.DeployedVersionCache(), false)
+
 	// Keep LatestVersion if the LatestVersion lookup is unchanged
 	if s.LatestVersion.IsEqual(&oldService.LatestVersion) {
 		s.Status.SetApprovedVersion(oldService.Status.ApprovedVersion(), false)
@@ -334,7 +338,8 @@ func (s *Service) giveSecrets(oldService *Service, secretRefs oldSecretRefs) {
 	}
 	// Keep DeployedVersion if the DeployedVersionLookup is unchanged
 	if s.DeployedVersionLookup.IsEqual(oldService.DeployedVersionLookup) &&
-		oldService.Options.SemanticVersioning == s.Options.SemanticVersioning {
+		oldService.Options.SemanticVersioning == s.Options.SemanticVersioning &&
+		oldService.Options.VersionScheme == s.Options.VersionScheme {
 		s.Status.SetDeployedVersion(oldService.Status.DeployedVersion(), false)
 		s.Status.SetDeployedVersionTimestamp(oldService.Status.DeployedVersionTimestamp())
 	}
@@ -0,0 +1,337 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionConstraint is a parsed `version_constraint`.
+//
+// Clauses separated by commas (or spaces) must all be satisfied, and groups of those separated by '||' are alternatives.
+// Versions are compared with the version_scheme of the Service, so the full versions of a clause are only parsed by Check.
+type VersionConstraint struct {
+	groups   [][]versionClause
+	relative bool // Whether a clause is relative to the current version ("patch"/"minor")
+}
+
+// versionClause is a single term of a VersionConstraint, e.g. ">=1.4.0".
+type versionClause struct {
+	operator string  // "", "=", "==", "<", "<=", ">", ">=", "!=", "~", "^", "patch" or "minor"
+	version  string  // Full version to compare against (empty when partial, e.g. "1.4" or "1.4.x")
+	release  []int64 // Release parts given of a partial version, e.g. [1 4] for 1.4.x
+}
+
+// ParseVersionConstraint parses a version constraint, e.g.
+//
+//	"~1.4"           - >=1.4.0, <1.5.0
+//	"^2"             - >=2.0.0, <3.0.0
+//	"1.4.x"          - >=1.4.0, <1.5.0
+//	"<3.0.0"         - less than 3.0.0
+//	">=1.2, <2 || 3" - at least 1.2.0 but less than 2.0.0, or 3.x
+//	"patch"          - same MAJOR.MINOR as the current version
+//	"minor"          - same MAJOR as the current version
+//
+// Partial versions (e.g. "<3" or "1.4") compare the release parts of a version, so they work with any version_scheme
+// (e.g. pep440's 1.0rc1 and 1.2.3.4 are both <3).
+func ParseVersionConstraint(constraint string) (*VersionConstraint, error) {
+	parsed := &VersionConstraint{}
+	for _, group := range strings.Split(constraint, "||") {
+		var clauses []versionClause
+		for _, term := range constraintTerms(group) {
+			clause, err := parseConstraintTerm(term)
+			if err != nil {
+				return nil, err
+			}
+			if clause.operator == "patch" || clause.operator == "minor" {
+				parsed.relative = true
+			}
+			clauses = append(clauses, clause)
+		}
+		if len(clauses) == 0 {
+			return nil, fmt.Errorf("empty constraint")
+		}
+		parsed.groups = append(parsed.groups, clauses)
+	}
+	return parsed, nil
+}
+
+// constraintTerms splits `group` on commas/spaces, keeping any operator with its version (e.g. ">= 1.2" -> ">=1.2").
+func constraintTerms(group string) (terms []string) {
+	fields := strings.FieldsFunc(group, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+	for i := 0; i < len(fields); i++ {
+		term := fields[i]
+		if strings.Trim(term, "<>=!~^") == "" && i+1 < len(fields) {
+			i++
+			term += fields[i]
+		}
+		terms = append(terms, term)
+	}
+	return
+}
+
+// parseConstraintTerm parses a single term of a constraint.
+func parseConstraintTerm(term string) (versionClause, error) {
+	switch strings.ToLower(term) {
+	case "patch", "minor":
+		return versionClause{operator: strings.ToLower(term)}, nil
+	}
+
+	operator := term[:len(term)-len(strings.TrimLeft(term, "<>=!~^"))]
+	switch operator {
+	case "", "=", "==", "<", "<=", ">", ">=", "!=", "~", "^":
+	default:
+		return versionClause{}, fmt.Errorf("%q - unknown operator %q", term, operator)
+	}
+	version := term[len(operator):]
+	if strings.HasPrefix(version, "v") || strings.HasPrefix(version, "V") {
+		version = version[1:]
+	}
+	release, wildcards, full, err := parseConstraintVersion(version)
+	if err != nil {
+		return versionClause{}, fmt.Errorf("%q - %w", term, err)
+	}
+	if wildcards != 0 && strings.ContainsAny(operator, "<>!") {
+		return versionClause{}, fmt.Errorf("%q - wildcards can't be used with %q", term, operator)
+	}
+
+	clause := versionClause{operator: operator, release: release}
+	if full {
+		clause.version = version
+	}
+	return clause, nil
+}
+
+// parseConstraintVersion parses a (possibly partial/wildcard) version, e.g. "1", "1.4", "1.4.x", "1.2.3-rc.1" or "1.0rc1",
+// returning the numeric release parts given, the count of wildcards, and whether it's a full version
+// (3+ parts, or a suffix that only the version_scheme can parse).
+func parseConstraintVersion(version string) (release []int64, wildcards int, full bool, err error) {
+	if version == "" {
+		err = fmt.Errorf("no version")
+		return
+	}
+	core := version[:len(version)-len(strings.TrimLeft(version, "0123456789.xX*"))]
+	suffix := version[len(core):]
+	if core == "" {
+		err = fmt.Errorf("%q is not a version", version)
+		return
+	}
+
+	for _, field := range strings.Split(core, ".") {
+		if field == "x" || field == "X" || field == "*" {
+			wildcards++
+			continue
+		}
+		if wildcards != 0 {
+			err = fmt.Errorf("%q has a number after a wildcard", version)
+			return
+		}
+		var part int64
+		if part, err = strconv.ParseInt(field, 10, 64); err != nil || part < 0 {
+			err = fmt.Errorf("%q is not a version", version)
+			return
+		}
+		release = append(release, part)
+	}
+	switch {
+	case len(release) == 0:
+		err = fmt.Errorf("%q has no major version", version)
+	case wildcards != 0 && suffix != "":
+		err = fmt.Errorf("%q has a suffix after a wildcard", version)
+	}
+	full = wildcards == 0 && (len(release) >= 3 || suffix != "")
+	return
+}
+
+// Check returns whether `version` satisfies the constraint, parsing and comparing versions with the `scheme` version_scheme
+// (the same way that versions are ordered and checked to be newer).
+//
+// `current` is the version that "patch"/"minor" are relative to (any version is allowed when there's no current version).
+// An error is returned when a version can't be parsed with the scheme.
+func (c *VersionConstraint) Check(version string, current string, scheme string) (bool, error) {
+	v, err := ParseVersion(scheme, version)
+	if err != nil {
+		return false, err
+	}
+	var currentVersion Version
+	if c.relative && current != "" {
+		if currentVersion, err = ParseVersion(scheme, current); err != nil {
+			return false, fmt.Errorf("current version: %w", err)
+		}
+	}
+
+	for _, group := range c.groups {
+		satisfied := true
+		for _, clause := range group {
+			ok, err := clause.check(v, currentVersion, scheme)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkScheme returns an error if a full version of the constraint can't be parsed with the `scheme` version_scheme.
+func (c *VersionConstraint) checkScheme(scheme string) error {
+	for _, group := range c.groups {
+		for _, clause := range group {
+			if clause.version == "" {
+				continue
+			}
+			if _, err := ParseVersion(scheme, clause.version); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// check returns whether `v` satisfies this clause.
+func (c versionClause) check(v Version, current Version, scheme string) (bool, error) {
+	switch c.operator {
+	case "patch":
+		return current == nil || sameRelease(v, current, 2), nil
+	case "minor":
+		return current == nil || sameRelease(v, current, 1), nil
+	}
+
+	var bound Version
+	release := c.release
+	if c.version != "" {
+		var err error
+		if bound, err = ParseVersion(scheme, c.version); err != nil {
+			return false, fmt.Errorf("version_constraint %q: %w", c.version, err)
+		}
+		release = bound.Release()
+	}
+
+	switch c.operator {
+	case "~":
+		// ~1 = 1.x, ~1.4 = ~1.4.0 = 1.4.x
+		if len(release) == 1 {
+			return inRange(v, bound, release, 1), nil
+		}
+		return inRange(v, bound, release, 2), nil
+	case "^":
+		// ^1.2.3 = 1.x, ^0.2.3 = 0.2.x, ^0.0.3 = 0.0.3.x
+		specified := len(release)
+		for i := 0; i < len(release)-1; i++ {
+			if release[i] != 0 {
+				specified = i + 1
+				break
+			}
+		}
+		return inRange(v, bound, release, specified), nil
+	}
+
+	if bound != nil {
+		switch c.operator {
+		case "", "=", "==":
+			return equalVersions(v, bound), nil
+		case "!=":
+			return !equalVersions(v, bound), nil
+		case "<":
+			return v.LessThan(bound), nil
+		case "<=":
+			return !bound.LessThan(v), nil
+		case ">":
+			return bound.LessThan(v), nil
+		}
+		return !v.LessThan(bound), nil
+	}
+
+	// Partial versions cover every version of that release, e.g.
+	// 1.4 = 1.4.x, <=1.2 = <1.3.0, >1 = >=2.0.0, !=1.2 = <1.2.0 || >=1.3.0
+	next := nextRelease(release, len(release))
+	switch c.operator {
+	case "", "=", "==":
+		return inRange(v, nil, release, len(release)), nil
+	case "!=":
+		return !inRange(v, nil, release, len(release)), nil
+	case "<":
+		return !fromRelease(v, release), nil
+	case "<=":
+		return compareParts(v.Release(), next) < 0, nil
+	case ">":
+		return compareParts(v.Release(), next) >= 0, nil
+	}
+	return fromRelease(v, release), nil
+}
+
+// inRange returns whether `v` is at least `lower` (or the final `release` when `lower` is nil),
+// and before the next release of the first `specified` parts of `release`.
+func inRange(v Version, lower Version, release []int64, specified int) bool {
+	if lower != nil {
+		if v.LessThan(lower) {
+			return false
+		}
+	} else if !fromRelease(v, release) {
+		return false
+	}
+	return compareParts(v.Release(), nextRelease(release, specified)) < 0
+}
+
+// fromRelease returns whether `v` is the final `release`, or newer
+// (pre-releases of the release are older, e.g. 1.0rc1 is <1.0).
+func fromRelease(v Version, release []int64) bool {
+	cmp := compareParts(v.Release(), release)
+	return cmp > 0 || (cmp == 0 && !v.PreRelease())
+}
+
+// nextRelease returns the first `specified` parts of `release`, with the last of those incremented, e.g. 1.4.2 -> 1.5.
+func nextRelease(release []int64, specified int) []int64 {
+	if specified > len(release) {
+		specified = len(release)
+	}
+	next := make([]int64, specified)
+	copy(next, release)
+	next[specified-1]++
+	return next
+}
+
+// sameRelease returns whether the first `parts` of the releases of `a` and `b` are equal.
+func sameRelease(a, b Version, parts int) bool {
+	aRelease, bRelease := a.Release(), b.Release()
+	for i := 0; i < parts; i++ {
+		var aPart, bPart int64
+		if i < len(aRelease) {
+			aPart = aRelease[i]
+		}
+		if i < len(bRelease) {
+			bPart = bRelease[i]
+		}
+		if aPart != bPart {
+			return false
+		}
+	}
+	return true
+}
+
+// equalVersions returns whether `a` and `b` have the same precedence.
+func equalVersions(a, b Version) bool {
+	return !a.LessThan(b) && !b.LessThan(a)
+}
@@ -0,0 +1,257 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use 10s file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package opt
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestParseVersionConstraint(t *testing.T) {
+	// GIVEN a version constraint
+	tests := map[string]struct {
+		constraint string
+		errRegex   string
+	}{
+		"tilde":                    {constraint: "~1.4"},
+		"caret":                    {constraint: "^2"},
+		"comparison":               {constraint: "<3.0.0"},
+		"comparison with space":    {constraint: ">= 1.2.0"},
+		"wildcard":                 {constraint: "1.4.x"},
+		"and":                      {constraint: ">=1.2, <2"},
+		"or":                       {constraint: "~1.4 || ^2"},
+		"patch":                    {constraint: "patch"},
+		"minor":                    {constraint: "Minor"},
+		"v prefix":                 {constraint: "^v1.2"},
+		"pre-release":              {constraint: ">=1.0.0-rc.1"},
+		"empty":                    {constraint: "", errRegex: `empty constraint`},
+		"empty alternative":        {constraint: "^1 ||", errRegex: `empty constraint`},
+		"unknown operator":         {constraint: "=>1.2", errRegex: `unknown operator "=>"`},
+		"not a version":            {constraint: "~foo", errRegex: `"foo" is not a version`},
+		"4 parts":                  {constraint: "<1.2.3.4"},
+		"pep440 pre-release":       {constraint: ">=1.0rc1"},
+		"debian epoch":             {constraint: ">=1:2.30-1"},
+		"suffix after wildcard":    {constraint: "1.x-rc.1", errRegex: `suffix after a wildcard`},
+		"number after wildcard":    {constraint: "1.x.3", errRegex: `number after a wildcard`},
+		"wildcard with comparison": {constraint: "<1.x", errRegex: `wildcards can't be used with "<"`},
+		"only wildcard":            {constraint: "*", errRegex: `no major version`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN ParseVersionConstraint is called on it
+			_, err := ParseVersionConstraint(tc.constraint)
+
+			// THEN it err's when expected
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestVersionConstraint_Check(t *testing.T) {
+	// GIVEN a version constraint and versions
+	tests := map[string]struct {
+		constraint  string
+		current     string
+		scheme      string
+		versions    map[string]bool
+		errVersions []string
+	}{
+		"~1.4": {
+			constraint: "~1.4",
+			versions: map[string]bool{
+				"1.3.9": false, "1.4.0": true, "1.4.12": true, "1.5.0": false, "1.5.0-rc.1": false}},
+		"~1": {
+			constraint: "~1",
+			versions: map[string]bool{
+				"0.9.0": false, "1.0.0": true, "1.9.0": true, "2.0.0": false}},
+		"~1.4.2": {
+			constraint: "~1.4.2",
+			versions: map[string]bool{
+				"1.4.1": false, "1.4.2": true, "1.4.9": true, "1.5.0": false}},
+		"^2": {
+			constraint: "^2",
+			versions: map[string]bool{
+				"1.9.9": false, "2.0.0": true, "2.99.1": true, "3.0.0": false, "3.0.0-beta.1": false}},
+		"^0.3": {
+			constraint: "^0.3",
+			versions: map[string]bool{
+				"0.2.0": false, "0.3.0": true, "0.3.5": true, "0.4.0": false}},
+		"^0.0.3": {
+			constraint: "^0.0.3",
+			versions: map[string]bool{
+				"0.0.3": true, "0.0.4": false}},
+		"<3.0.0": {
+			constraint: "<3.0.0",
+			versions: map[string]bool{
+				"2.99.99": true, "3.0.0": false, "10.0.0": false}},
+		">=1.2, <2": {
+			constraint: ">=1.2, <2",
+			versions: map[string]bool{
+				"1.1.9": false, "1.2.0": true, "1.99.0": true, "2.0.0": false}},
+		"!=1.2.3": {
+			constraint: "!=1.2.3",
+			versions: map[string]bool{
+				"1.2.3": false, "1.2.4": true}},
+		"<=1.2.0, >1.0.0": {
+			constraint: "<=1.2.0 >1.0.0",
+			versions: map[string]bool{
+				"1.0.0": false, "1.0.1": true, "1.2.0": true, "1.2.1": false}},
+		"partial versions": {
+			constraint: ">1 <=2.3",
+			versions: map[string]bool{
+				"1.9.0": false, "2.0.0": true, "2.3.9": true, "2.4.0": false}},
+		"!=1.2": {
+			constraint: "!=1.2",
+			versions: map[string]bool{
+				"1.1.9": true, "1.2.0": false, "1.2.7": false, "1.3.0": true}},
+		"1.4.x": {
+			constraint: "1.4.x",
+			versions: map[string]bool{
+				"1.4.0": true, "1.4.7": true, "1.5.0": false}},
+		"exact": {
+			constraint: "1.2.3",
+			versions: map[string]bool{
+				"1.2.3": true, "1.2.4": false}},
+		"or": {
+			constraint: "~1.4 || ^3",
+			versions: map[string]bool{
+				"1.4.2": true, "2.0.0": false, "3.1.0": true}},
+		"patch": {
+			constraint: "patch",
+			current:    "1.4.2",
+			versions: map[string]bool{
+				"1.4.3": true, "1.5.0": false, "2.0.0": false}},
+		"minor": {
+			constraint: "minor",
+			current:    "1.4.2",
+			versions: map[string]bool{
+				"1.4.3": true, "1.5.0": true, "2.0.0": false}},
+		"patch without a current version": {
+			constraint: "patch",
+			versions: map[string]bool{
+				"2.0.0": true}},
+		"non-semantic versions": {
+			constraint: "<3",
+			errVersions: []string{
+				"foo", "1.2", "v1.2.3"}},
+		"v prefix on the constraint": {
+			constraint: "^v1.2",
+			versions: map[string]bool{
+				"1.1.9": false, "1.2.0": true, "1.9.9": true, "2.0.0": false}},
+		"v prefix with loose-semver": {
+			constraint: "<v2",
+			scheme:     "loose-semver",
+			versions: map[string]bool{
+				"v1.2.3": true, "V1.9": true, "v2.0.0": false, "v2.0.0-rc.1": true}},
+		"pep440 partial": {
+			constraint: "<3",
+			scheme:     "pep440",
+			versions: map[string]bool{
+				"1.0rc1": true, "1.2.3.4": true, "2.99.post1": true, "3.0rc1": true, "3.0": false, "v3.0.1": false}},
+		"pep440 full": {
+			constraint: ">=1.0rc1, <1.0.1",
+			scheme:     "pep440",
+			versions: map[string]bool{
+				"1.0b2": false, "1.0rc1": true, "1.0": true, "1.0.post1": true, "1.0.1.dev1": true, "1.0.1": false}},
+		"pep440 tilde": {
+			constraint: "~1.4",
+			scheme:     "pep440",
+			versions: map[string]bool{
+				"1.4rc1": false, "1.4": true, "1.4.2.1": true, "1.5a1": false},
+			errVersions: []string{
+				"1.4-foo"}},
+		"debian": {
+			constraint: ">=2.30, <1:0",
+			scheme:     "debian",
+			versions: map[string]bool{
+				"2.29-1": false, "2.30~rc1-1": false, "2.30-1": true, "2.31+dfsg-2": true, "1:0.1-1": false},
+			errVersions: []string{
+				"v2.30-1"}},
+		"debian partial equal": {
+			constraint: "2.30",
+			scheme:     "debian",
+			versions: map[string]bool{
+				"2.30-1": true, "2.30.1-1": true, "2.31-1": false}},
+		"patch with pep440": {
+			constraint: "patch",
+			current:    "1.4.2",
+			scheme:     "pep440",
+			versions: map[string]bool{
+				"1.4.3rc1": true, "1.4": true, "1.5.0": false}},
+		"patch with an invalid current version": {
+			constraint: "patch",
+			current:    "foo",
+			errVersions: []string{
+				"1.4.3"}},
+		"invalid version in the constraint for the scheme": {
+			constraint: ">=1.0rc1",
+			errVersions: []string{
+				"1.0.0"}},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			constraint, err := ParseVersionConstraint(tc.constraint)
+			if err != nil {
+				t.Fatalf("unexpected err: %v", err)
+			}
+
+			for version, want := range tc.versions {
+				// WHEN Check is called on the version
+				got, err := constraint.Check(version, tc.current, tc.scheme)
+
+				// THEN it's satisfied when expected
+				if err != nil {
+					t.Errorf("%q on %q - unexpected err: %v",
+						tc.constraint, version, err)
+				}
+				if got != want {
+					t.Errorf("%q on %q - want: %t, got: %t",
+						tc.constraint, version, want, got)
+				}
+			}
+			for _, version := range tc.errVersions {
+				// WHEN Check is called on a version that can't be compared
+				got, err := constraint.Check(version, tc.current, tc.scheme)
+
+				// THEN it err's, rather than passing/failing the version
+				if err == nil || got {
+					t.Errorf("%q on %q - want an err, got: %t, %v",
+						tc.constraint, version, got, err)
+				}
+			}
+		})
+	}
+}
@@ -16,16 +16,32 @@ package opt
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/release-argus/Argus/util"
 )
 
+// DefaultPriority of queries when API rate limits run low.
+const DefaultPriority = "normal"
+
+// Priorities of queries when API rate limits run low.
+var Priorities = []string{"low", "normal", "high"}
+
 // OptionsBase is the base struct for Options.
 type OptionsBase struct {
 	Interval           string `yaml:"interval,omitempty" json:"interval,omitempty"`                       // AhBmCs = Sleep A hours, B minutes and C seconds between queries.
+	Schedule           string `yaml:"schedule,omitempty" json:"schedule,omitempty"`                       // e.g. "*/15 9-17 * * mon-fri" - Cron expression of when to query (instead of every Interval).
+	Jitter             string `yaml:"jitter,omitempty" json:"jitter,omitempty"`                           // e.g. "10%" - Random delay of up to this % of the Interval/Schedule period added to each query.
+	Backoff            string `yaml:"backoff,omitempty" json:"backoff,omitempty"`                         // e.g. "2" - Multiply the wait between queries by this for each consecutive failed query.
+	BackoffMax         string `yaml:"backoff_max,omitempty" json:"backoff_max,omitempty"`                 // e.g. "12h" - Longest wait between queries when backing off.
+	Priority           string `yaml:"priority,omitempty" json:"priority,omitempty"`                       // default - normal = Priority of queries when API rate limits run low (low/normal/high).
 	SemanticVersioning *bool  `yaml:"semantic_versioning,omitempty" json:"semantic_versioning,omitempty"` // default - true = Version has to follow semantic versioning (https://semver.org/) and be greater than the previous to trigger anything.
+	VersionConstraint  string `yaml:"version_constraint,omitempty" json:"version_constraint,omitempty"`   // e.g. "~1.4", "^2", "<3.0.0", "patch" - Versions must satisfy this to be considered.
+	VersionScheme      string `yaml:"version_scheme,omitempty" json:"version_scheme,omitempty"`           // default - semver = Scheme to parse and order versions with when SemanticVersioning (semver/loose-semver/calver/pep440/debian).
 }
 
 // OptionsDefaults are the default values for Options.
@@ -90,6 +106,125 @@ func (o *Options) GetInterval() string {
 		o.HardDefaults.Interval)
 }
 
+// GetSchedule (cron expression) of queries for this Service's latest version.
+func (o *Options) GetSchedule() string {
+	schedules := []string{o.Schedule}
+	for _, defaults := range []*OptionsDefaults{o.Defaults, o.HardDefaults} {
+		if defaults != nil {
+			schedules = append(schedules, defaults.Schedule)
+		}
+	}
+	return util.FirstNonDefault(schedules...)
+}
+
+// GetJitter percentage of the wait between queries that's randomly added to it.
+func (o *Options) GetJitter() int {
+	jitters := []string{o.Jitter}
+	for _, defaults := range []*OptionsDefaults{o.Defaults, o.HardDefaults} {
+		if defaults != nil {
+			jitters = append(jitters, defaults.Jitter)
+		}
+	}
+	jitter, _ := parseJitter(util.FirstNonDefault(jitters...))
+	return jitter
+}
+
+// NextQuery returns the time of the query following one at `from`.
+//
+// This is the next time on the Schedule, or `from`+Interval when there's no Schedule,
+// delayed by a random amount of up to Jitter% of the Interval/Schedule period.
+// The period of a Schedule is the shorter of the gaps either side of that next time,
+// so the jitter can't carry a query past the time that follows it (e.g. into a weekend).
+func (o *Options) NextQuery(from time.Time) time.Time {
+	period := o.GetIntervalDuration()
+	next := from.Add(period)
+	if scheduleStr := o.GetSchedule(); scheduleStr != "" {
+		// Never matches, fall back to the Interval
+		if schedule, err := ParseSchedule(scheduleStr); err == nil && !schedule.Next(from).IsZero() {
+			next = schedule.Next(from)
+			period = schedule.Next(next).Sub(next)
+			if since := next.Sub(from); since < period {
+				period = since
+			}
+		}
+	}
+
+	if maxJitter := int64(period) * int64(o.GetJitter()) / 100; maxJitter > 0 {
+		//#nosec G404 -- jitter doesn't need to be cryptographically secure
+		next = next.Add(time.Duration(rand.Int63n(maxJitter)))
+	}
+	return next
+}
+
+// GetBackoff multiplier of the wait between queries for each consecutive failed query.
+func (o *Options) GetBackoff() float64 {
+	backoffs := []string{o.Backoff}
+	for _, defaults := range []*OptionsDefaults{o.Defaults, o.HardDefaults} {
+		if defaults != nil {
+			backoffs = append(backoffs, defaults.Backoff)
+		}
+	}
+	backoff, _ := parseBackoff(util.FirstNonDefault(backoffs...))
+	return backoff
+}
+
+// backoffMaxWait is the longest wait between queries when backing off without a BackoffMax.
+const backoffMaxWait = 7 * 24 * time.Hour
+
+// GetBackoffMax is the longest wait between queries when backing off (0 = backoffMaxWait).
+func (o *Options) GetBackoffMax() time.Duration {
+	backoffMaxes := []string{o.BackoffMax}
+	for _, defaults := range []*OptionsDefaults{o.Defaults, o.HardDefaults} {
+		if defaults != nil {
+			backoffMaxes = append(backoffMaxes, defaults.BackoffMax)
+		}
+	}
+	backoffMax, _ := time.ParseDuration(util.FirstNonDefault(backoffMaxes...))
+	return backoffMax
+}
+
+// NextQueryAfterFailures returns the time of the query following one at `from` that was the `failures`-th consecutive failure.
+//
+// `next` is the NextQuery after `from`, and the wait until it is multiplied by Backoff for each failure,
+// up to BackoffMax, or backoffMaxWait without one (but never less than the wait until `next`).
+// With a Schedule, this is the next time on the Schedule after that wait.
+func (o *Options) NextQueryAfterFailures(from, next time.Time, failures uint) time.Time {
+	backoff := o.GetBackoff()
+	if failures == 0 || backoff <= 1 {
+		return next
+	}
+
+	wait := next.Sub(from)
+	backoffMax := o.GetBackoffMax()
+	if backoffMax <= 0 || backoffMax > backoffMaxWait {
+		backoffMax = backoffMaxWait
+	}
+	// Clamped before converting to a Duration, as Pow reaches +Inf after enough failures.
+	backedOff := float64(wait) * math.Pow(backoff, float64(failures))
+	if backedOff > float64(backoffMax) {
+		backedOff = float64(backoffMax)
+	}
+	if backedOff <= float64(wait) {
+		return next
+	}
+
+	if o.GetSchedule() != "" {
+		return o.NextQuery(from.Add(time.Duration(backedOff)))
+	}
+	return from.Add(time.Duration(backedOff))
+}
+
+// GetPriority of the queries of this Service when API rate limits run low.
+func (o *Options) GetPriority() string {
+	priorities := []string{o.Priority}
+	for _, defaults := range []*OptionsDefaults{o.Defaults, o.HardDefaults} {
+		if defaults != nil {
+			priorities = append(priorities, defaults.Priority)
+		}
+	}
+	return util.FirstNonDefault(append(priorities, DefaultPriority)...)
+}
+
 // GetSemanticVersioning will return whether Semantic Versioning should be used for this Service.
 func (o *Options) GetSemanticVersioning() bool {
 	return *util.FirstNonNilPtr(
@@ -98,6 +233,58 @@ func (o *Options) GetSemanticVersioning() bool {
 		o.HardDefaults.SemanticVersioning)
 }
 
+// GetVersionConstraint will return the constraint that versions must satisfy for this Service.
+func (o *Options) GetVersionConstraint() string {
+	constraints := []string{o.VersionConstraint}
+	for _, defaults := range []*OptionsDefaults{o.Defaults, o.HardDefaults} {
+		if defaults != nil {
+			constraints = append(constraints, defaults.VersionConstraint)
+		}
+	}
+	return util.FirstNonDefault(constraints...)
+}
+
+// CheckVersionConstraint returns whether `version` satisfies the version_constraint of this Service.
+//
+// `current` is the version that a "patch"/"minor" constraint is relative to.
+// Versions always satisfy an empty constraint, and an error is returned when the constraint is invalid,
+// or a version can't be compared with the version_scheme of this Service.
+func (o *Options) CheckVersionConstraint(version string, current string) (bool, error) {
+	versionConstraint := o.GetVersionConstraint()
+	if versionConstraint == "" {
+		return true, nil
+	}
+	constraint, err := ParseVersionConstraint(versionConstraint)
+	if err != nil {
+		return false, err
+	}
+	return constraint.Check(version, current, o.GetVersionScheme())
+}
+
+// GetVersionScheme will return the scheme to parse and order versions with for this Service.
+func (o *Options) GetVersionScheme() string {
+	schemes := []string{o.VersionScheme}
+	for _, defaults := range []*OptionsDefaults{o.Defaults, o.HardDefaults} {
+		if defaults != nil {
+			schemes = append(schemes, defaults.VersionScheme)
+		}
+	}
+	return util.FirstNonDefault(append(schemes, DefaultVersionScheme)...)
+}
+
+// ParseVersion will parse `version` with the version_scheme of this Service.
+func (o *Options) ParseVersion(version string) (Version, error) {
+	return ParseVersion(o.GetVersionScheme(), version)
+}
+
+// GetVersionStyle returns a description of the style of versions in the version_scheme of this Service.
+func (o *Options) GetVersionStyle() string {
+	if scheme := VersionSchemes[o.GetVersionScheme()]; scheme != nil {
+		return scheme.Style()
+	}
+	return ""
+}
+
 // GetIntervalPointer returns a pointer to the interval between queries on this Service's version.
 func (o *Options) GetIntervalPointer() *string {
 	if o.Interval != "" {
@@ -129,6 +316,70 @@ func (o *OptionsBase) CheckValues(prefix string) (errs error) {
 		}
 	}
 
+	// Schedule
+	if o.Schedule != "" {
+		if schedule, err := ParseSchedule(o.Schedule); err != nil {
+			errs = fmt.Errorf("%s%s  schedule: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, o.Schedule, err)
+		} else if schedule.Next(time.Now()).IsZero() {
+			errs = fmt.Errorf("%s%s  schedule: %q <invalid> (never matches a date)\\",
+				util.ErrorToString(errs), prefix, o.Schedule)
+		}
+	}
+
+	// Jitter
+	if o.Jitter != "" {
+		if _, err := parseJitter(o.Jitter); err != nil {
+			errs = fmt.Errorf("%s%s  jitter: %q <invalid> (Use a percentage between 0 and 100, e.g. '10%%')\\",
+				util.ErrorToString(errs), prefix, o.Jitter)
+		}
+	}
+
+	// Backoff
+	if o.Backoff != "" {
+		if _, err := parseBackoff(o.Backoff); err != nil {
+			errs = fmt.Errorf("%s%s  backoff: %q <invalid> (Use a multiplier greater than 1, e.g. '2')\\",
+				util.ErrorToString(errs), prefix, o.Backoff)
+		}
+	}
+
+	// BackoffMax
+	if o.BackoffMax != "" {
+		// Default to seconds when an integer is provided
+		if _, err := strconv.Atoi(o.BackoffMax); err == nil {
+			o.BackoffMax += "s"
+		}
+		if _, err := time.ParseDuration(o.BackoffMax); err != nil {
+			errs = fmt.Errorf("%s%s  backoff_max: %q <invalid> (Use 'AhBmCs' duration format)\\",
+				util.ErrorToString(errs), prefix, o.BackoffMax)
+		}
+	}
+
+	// Priority
+	if o.Priority != "" && !util.Contains(Priorities, o.Priority) {
+		errs = fmt.Errorf("%s%s  priority: %q <invalid> (supported priorities = %s)\\",
+			util.ErrorToString(errs), prefix, o.Priority, Priorities)
+	}
+
+	// VersionConstraint
+	if o.VersionConstraint != "" {
+		constraint, err := ParseVersionConstraint(o.VersionConstraint)
+		// Full versions can only be checked here when the scheme is also set at this level.
+		if err == nil && VersionSchemes[o.VersionScheme] != nil {
+			err = constraint.checkScheme(o.VersionScheme)
+		}
+		if err != nil {
+			errs = fmt.Errorf("%s%s  version_constraint: %q <invalid> (%s)\\",
+				util.ErrorToString(errs), prefix, o.VersionConstraint, err)
+		}
+	}
+
+	// VersionScheme
+	if o.VersionScheme != "" && VersionSchemes[o.VersionScheme] == nil {
+		errs = fmt.Errorf("%s%s  version_scheme: %q <invalid> (supported schemes = %s)\\",
+			util.ErrorToString(errs), prefix, o.VersionScheme, util.SortedKeys(VersionSchemes))
+	}
+
 	if errs != nil {
 		errs = fmt.Errorf("%soptions:\\%w",
 			prefix, errs)
@@ -136,3 +387,34 @@ func (o *OptionsBase) CheckValues(prefix string) (errs error) {
 
 	return
 }
+
+// parseJitter returns the percentage of a jitter, e.g. "10%" or "10" = 10.
+func parseJitter(jitter string) (int, error) {
+	if jitter == "" {
+		return 0, nil
+	}
+	percentage, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(jitter), "%"))
+	if err != nil {
+		return 0, err //nolint:wrapcheck
+	}
+	if percentage < 0 || percentage > 100 {
+		return 0, fmt.Errorf("%d is not in 0-100", percentage)
+	}
+	return percentage, nil
+}
+
+// parseBackoff returns the multiplier of a backoff, e.g. "2" or "1.5".
+func parseBackoff(backoff string) (float64, error) {
+	if backoff == "" {
+		return 1, nil
+	}
+	multiplier, err := strconv.ParseFloat(strings.TrimSpace(backoff), 64)
+	if err != nil {
+		return 1, err //nolint:wrapcheck
+	}
+	// NaN fails every comparison, so check it isn't greater.
+	if !(multiplier > 1) || math.IsInf(multiplier, 0) {
+		return 1, fmt.Errorf("%s is not a finite number greater than 1", backoff)
+	}
+	return multiplier, nil
+}
@@ -162,6 +162,256 @@ func TestOptions_GetSemanticVersioning(t *testing.T) {
 	}
 }
 
+func TestOptions_GetVersionConstraint(t *testing.T) {
+	// GIVEN Options
+	tests := map[string]struct {
+		constraintRoot        string
+		constraintDefault     string
+		constraintHardDefault string
+		nilDefaults           bool
+		want                  string
+	}{
+		"root overrides all": {
+			want:                  "~1.4",
+			constraintRoot:        "~1.4",
+			constraintDefault:     "^2",
+			constraintHardDefault: "patch",
+		},
+		"default overrides hardDefault": {
+			want:                  "^2",
+			constraintDefault:     "^2",
+			constraintHardDefault: "patch",
+		},
+		"hardDefault is last resort": {
+			want:                  "patch",
+			constraintHardDefault: "patch",
+		},
+		"nil defaults": {
+			want:           "<3",
+			constraintRoot: "<3",
+			nilDefaults:    true,
+		},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := testOptions()
+			options.VersionConstraint = tc.constraintRoot
+			options.Defaults.VersionConstraint = tc.constraintDefault
+			options.HardDefaults.VersionConstraint = tc.constraintHardDefault
+			if tc.nilDefaults {
+				options.Defaults = nil
+				options.HardDefaults = nil
+			}
+
+			// WHEN GetVersionConstraint is called
+			got := options.GetVersionConstraint()
+
+			// THEN the function returns the correct result
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestOptions_GetVersionScheme(t *testing.T) {
+	// GIVEN Options
+	tests := map[string]struct {
+		schemeRoot        string
+		schemeDefault     string
+		schemeHardDefault string
+		nilDefaults       bool
+		want              string
+	}{
+		"root overrides all": {
+			want:              "pep440",
+			schemeRoot:        "pep440",
+			schemeDefault:     "calver",
+			schemeHardDefault: "debian",
+		},
+		"default overrides hardDefault": {
+			want:              "calver",
+			schemeDefault:     "calver",
+			schemeHardDefault: "debian",
+		},
+		"hardDefault is last resort": {
+			want:              "debian",
+			schemeHardDefault: "debian",
+		},
+		"semver when none are set": {
+			want: "semver",
+		},
+		"nil defaults": {
+			want:        "semver",
+			nilDefaults: true,
+		},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := testOptions()
+			options.VersionScheme = tc.schemeRoot
+			options.Defaults.VersionScheme = tc.schemeDefault
+			options.HardDefaults.VersionScheme = tc.schemeHardDefault
+			if tc.nilDefaults {
+				options.Defaults = nil
+				options.HardDefaults = nil
+			}
+
+			// WHEN GetVersionScheme is called
+			got := options.GetVersionScheme()
+
+			// THEN the function returns the correct result
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+			// AND ParseVersion uses that scheme
+			if _, err := options.ParseVersion("24.04.1.5"); (err == nil) != (got != "semver") {
+				t.Errorf("ParseVersion with %q on %q gave err=%v",
+					got, "24.04.1.5", err)
+			}
+		})
+	}
+}
+
+func TestOptions_GetPriority(t *testing.T) {
+	// GIVEN Options
+	tests := map[string]struct {
+		priorityRoot, priorityDefault, priorityHardDefault string
+		nilDefaults                                        bool
+		want                                               string
+	}{
+		"root overrides all": {
+			want:                "high",
+			priorityRoot:        "high",
+			priorityDefault:     "low",
+			priorityHardDefault: "normal",
+		},
+		"default overrides hardDefault": {
+			want:                "low",
+			priorityDefault:     "low",
+			priorityHardDefault: "high",
+		},
+		"normal when none are set": {
+			want: "normal",
+		},
+		"nil defaults": {
+			want:        "normal",
+			nilDefaults: true,
+		},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := testOptions()
+			options.Priority = tc.priorityRoot
+			options.Defaults.Priority = tc.priorityDefault
+			options.HardDefaults.Priority = tc.priorityHardDefault
+			if tc.nilDefaults {
+				options.Defaults = nil
+				options.HardDefaults = nil
+			}
+
+			// WHEN GetPriority is called
+			got := options.GetPriority()
+
+			// THEN the function returns the correct result
+			if got != tc.want {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestOptions_CheckVersionConstraint(t *testing.T) {
+	// GIVEN Options with a version_constraint
+	tests := map[string]struct {
+		constraint string
+		version    string
+		current    string
+		scheme     string
+		want       bool
+		errRegex   string
+	}{
+		"no constraint": {
+			constraint: "",
+			version:    "3.0.0",
+			want:       true},
+		"satisfied": {
+			constraint: "~1.4",
+			version:    "1.4.9",
+			want:       true},
+		"not satisfied": {
+			constraint: "~1.4",
+			version:    "1.5.0",
+			want:       false},
+		"patch relative to current": {
+			constraint: "patch",
+			version:    "1.4.10",
+			current:    "1.4.2",
+			want:       true},
+		"patch rejects minor": {
+			constraint: "patch",
+			version:    "1.5.0",
+			current:    "1.4.2",
+			want:       false},
+		"uses the version_scheme": {
+			constraint: "<3",
+			scheme:     "pep440",
+			version:    "1.0rc1",
+			want:       true},
+		"version not of the version_scheme": {
+			constraint: "<3",
+			version:    "1.0rc1",
+			errRegex:   `Invalid|not`},
+		"invalid constraint": {
+			constraint: "=>1",
+			version:    "1.0.0",
+			errRegex:   `unknown operator`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := testOptions()
+			options.VersionConstraint = tc.constraint
+			options.VersionScheme = tc.scheme
+
+			// WHEN CheckVersionConstraint is called
+			got, err := options.CheckVersionConstraint(tc.version, tc.current)
+
+			// THEN the function returns the correct result
+			if tc.errRegex == "" {
+				tc.errRegex = "^$"
+			}
+			e := util.ErrorToString(err)
+			if !regexp.MustCompile(tc.errRegex).MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			if got != tc.want {
+				t.Errorf("want: %t\ngot:  %t",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestOptions_GetIntervalPointer(t *testing.T) {
 	// GIVEN options
 	tests := map[string]struct {
@@ -222,6 +472,173 @@ func TestOptions_GetIntervalDuration(t *testing.T) {
 	}
 }
 
+func TestOptions_NextQuery(t *testing.T) {
+	// GIVEN Options with an Interval/Schedule and Jitter
+	from := time.Date(2023, time.May, 17, 10, 7, 30, 0, time.UTC)
+	tests := map[string]struct {
+		interval, schedule, jitter string
+		scheduleDefault            string
+		from                       time.Time
+		wantMin, wantMax           time.Time
+	}{
+		"interval": {
+			interval: "10m",
+			wantMin:  from.Add(10 * time.Minute),
+			wantMax:  from.Add(10 * time.Minute)},
+		"interval with jitter": {
+			interval: "10m",
+			jitter:   "50%",
+			wantMin:  from.Add(10 * time.Minute),
+			wantMax:  from.Add(15 * time.Minute)},
+		"schedule": {
+			interval: "10m",
+			schedule: "0 * * * *",
+			wantMin:  time.Date(2023, time.May, 17, 11, 0, 0, 0, time.UTC),
+			wantMax:  time.Date(2023, time.May, 17, 11, 0, 0, 0, time.UTC)},
+		"schedule from defaults": {
+			interval:        "10m",
+			scheduleDefault: "0 * * * *",
+			wantMin:         time.Date(2023, time.May, 17, 11, 0, 0, 0, time.UTC),
+			wantMax:         time.Date(2023, time.May, 17, 11, 0, 0, 0, time.UTC)},
+		"schedule with jitter of its period": {
+			interval: "10m",
+			schedule: "0 * * * *",
+			jitter:   "10",
+			wantMin:  time.Date(2023, time.May, 17, 11, 0, 0, 0, time.UTC),
+			wantMax:  time.Date(2023, time.May, 17, 11, 6, 0, 0, time.UTC)},
+		"schedule with jitter stays before the following time": {
+			interval: "10m",
+			schedule: "*/15 9-17 * * mon-fri",
+			jitter:   "50%",
+			from:     time.Date(2023, time.May, 19, 17, 40, 0, 0, time.UTC), // Friday
+			wantMin:  time.Date(2023, time.May, 19, 17, 45, 0, 0, time.UTC),
+			wantMax:  time.Date(2023, time.May, 19, 17, 47, 30, 0, time.UTC)},
+		"schedule with jitter after a weekend": {
+			interval: "10m",
+			schedule: "*/15 9-17 * * mon-fri",
+			jitter:   "50%",
+			from:     time.Date(2023, time.May, 19, 17, 50, 0, 0, time.UTC), // Friday
+			wantMin:  time.Date(2023, time.May, 22, 9, 0, 0, 0, time.UTC),
+			wantMax:  time.Date(2023, time.May, 22, 9, 7, 30, 0, time.UTC)},
+		"schedule that never matches uses the interval": {
+			interval: "10m",
+			schedule: "0 0 30 feb *",
+			wantMin:  from.Add(10 * time.Minute),
+			wantMax:  from.Add(10 * time.Minute)},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := testOptions()
+			options.Interval = tc.interval
+			options.Schedule = tc.schedule
+			options.Jitter = tc.jitter
+			options.Defaults.Schedule = tc.scheduleDefault
+			queryFrom := from
+			if !tc.from.IsZero() {
+				queryFrom = tc.from
+			}
+
+			for i := 0; i < 25; i++ {
+				// WHEN NextQuery is called
+				got := options.NextQuery(queryFrom)
+
+				// THEN the next query is within the jitter of the Interval/Schedule
+				if got.Before(tc.wantMin) || got.After(tc.wantMax) {
+					t.Fatalf("want: %s - %s\ngot:  %s",
+						tc.wantMin, tc.wantMax, got)
+				}
+			}
+		})
+	}
+}
+
+func TestOptions_NextQueryAfterFailures(t *testing.T) {
+	// GIVEN Options with a Backoff and a query that failed
+	from := time.Date(2023, time.May, 17, 10, 7, 30, 0, time.UTC)
+	tests := map[string]struct {
+		schedule            string
+		backoff, backoffMax string
+		failures            uint
+		want                time.Time
+	}{
+		"no failures": {
+			backoff:  "2",
+			failures: 0,
+			want:     from.Add(10 * time.Minute)},
+		"no backoff": {
+			backoff:  "",
+			failures: 3,
+			want:     from.Add(10 * time.Minute)},
+		"1 failure": {
+			backoff:  "2",
+			failures: 1,
+			want:     from.Add(20 * time.Minute)},
+		"3 failures": {
+			backoff:  "2",
+			failures: 3,
+			want:     from.Add(80 * time.Minute)},
+		"fractional backoff": {
+			backoff:  "1.5",
+			failures: 2,
+			want:     from.Add(22*time.Minute + 30*time.Second)},
+		"capped at backoff_max": {
+			backoff:    "2",
+			backoffMax: "1h",
+			failures:   10,
+			want:       from.Add(time.Hour)},
+		"backoff_max less than the interval": {
+			backoff:    "2",
+			backoffMax: "5m",
+			failures:   10,
+			want:       from.Add(10 * time.Minute)},
+		"capped without backoff_max": {
+			backoff:  "2",
+			failures: 20,
+			want:     from.Add(7 * 24 * time.Hour)},
+		"capped when the backoff overflows": {
+			backoff:  "10",
+			failures: 2000,
+			want:     from.Add(7 * 24 * time.Hour)},
+		"backoff_max longer than the cap": {
+			backoff:    "2",
+			backoffMax: "8760h",
+			failures:   20,
+			want:       from.Add(7 * 24 * time.Hour)},
+		"schedule waits for the next time after the backoff": {
+			schedule: "*/10 * * * *",
+			backoff:  "2",
+			failures: 2,
+			want:     time.Date(2023, time.May, 17, 10, 20, 0, 0, time.UTC)},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			options := testOptions()
+			options.Interval = "10m"
+			options.Schedule = tc.schedule
+			options.Backoff = tc.backoff
+			options.BackoffMax = tc.backoffMax
+			next := options.NextQuery(from)
+
+			// WHEN NextQueryAfterFailures is called
+			got := options.NextQueryAfterFailures(from, next, tc.failures)
+
+			// THEN the wait until the next query is backed off
+			if !got.Equal(tc.want) {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
+
 func TestOptions_CheckValues(t *testing.T) {
 	// GIVEN Options
 	tests := map[string]struct {
@@ -241,6 +658,118 @@ func TestOptions_CheckValues(t *testing.T) {
 				boolPtr(false), "10x", boolPtr(false),
 				nil, nil),
 		},
+		"valid version_constraint": {
+			errRegex: `^$`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					VersionConstraint: ">=1.2, <2 || ^3"}},
+		},
+		"invalid version_constraint": {
+			errRegex: `version_constraint: "~1.x.3" <invalid>`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					VersionConstraint: "~1.x.3"}},
+		},
+		"version_constraint of the version_scheme": {
+			errRegex: `^$`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					VersionConstraint: ">=1.0rc1, <2",
+					VersionScheme:     "pep440"}},
+		},
+		"version_constraint not of the version_scheme": {
+			errRegex: `version_constraint: ">=1.0rc1, <2" <invalid>`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					VersionConstraint: ">=1.0rc1, <2",
+					VersionScheme:     "semver"}},
+		},
+		"valid version_scheme": {
+			errRegex: `^$`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					VersionScheme: "pep440"}},
+		},
+		"invalid version_scheme": {
+			errRegex: `version_scheme: "romver" <invalid> \(supported schemes = \[calver debian loose-semver pep440 semver\]\)`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					VersionScheme: "romver"}},
+		},
+		"valid schedule and jitter": {
+			errRegex: `^$`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Schedule: "*/15 9-17 * * mon-fri",
+					Jitter:   "10%"}},
+		},
+		"invalid schedule": {
+			errRegex: `schedule: "\* \* \*" <invalid> \(expected 5 fields`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Schedule: "* * *"}},
+		},
+		"schedule that never matches": {
+			errRegex: `schedule: "0 0 31 apr \*" <invalid> \(never matches a date\)`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Schedule: "0 0 31 apr *"}},
+		},
+		"invalid jitter": {
+			errRegex: `jitter: "150%" <invalid> \(Use a percentage between 0 and 100, e.g. '10%'\)`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Jitter: "150%"}},
+		},
+		"valid backoff": {
+			errRegex: `^$`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Backoff:    "1.5",
+					BackoffMax: "12h"}},
+		},
+		"invalid backoff": {
+			errRegex: `backoff: "0.5" <invalid> \(Use a multiplier greater than 1, e.g. '2'\)`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Backoff: "0.5"}},
+		},
+		"invalid backoff of 1": {
+			errRegex: `backoff: "1" <invalid>`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Backoff: "1"}},
+		},
+		"invalid backoff of NaN": {
+			errRegex: `backoff: "NaN" <invalid>`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Backoff: "NaN"}},
+		},
+		"invalid backoff of Inf": {
+			errRegex: `backoff: "Inf" <invalid>`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Backoff: "Inf"}},
+		},
+		"invalid backoff_max": {
+			errRegex: `backoff_max: "1x" <invalid> \(Use 'AhBmCs' duration format\)`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					BackoffMax: "1x"}},
+		},
+		"valid priority": {
+			errRegex: `^$`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Priority: "high"}},
+		},
+		"invalid priority": {
+			errRegex: `priority: "urgent" <invalid> \(supported priorities = \[low normal high\]\)`,
+			options: &Options{
+				OptionsBase: OptionsBase{
+					Priority: "urgent"}},
+		},
 		"seconds get appended to pure decimal interval": {
 			errRegex:     `^$`,
 			wantInterval: "10s",
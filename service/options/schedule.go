@@ -0,0 +1,188 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64 // Bitsets of the values allowed for each field
+	domStar, dowStar              bool   // Whether day-of-month/day-of-week start with '*'
+}
+
+// scheduleField is the range of values (and the names) allowed for a field of a cron expression.
+type scheduleField struct {
+	name     string
+	min, max uint
+	names    map[string]uint
+}
+
+var scheduleFields = []scheduleField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}},
+	// 7 is also Sunday
+	{name: "day of week", min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}},
+}
+
+// scheduleDescriptors are the shorthands for common cron expressions.
+var scheduleDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *"}
+
+// scheduleMaxYears is how far ahead Next will look for a time that matches the Schedule.
+const scheduleMaxYears = 5
+
+// ParseSchedule parses a standard (5 field) cron expression, e.g. "*/15 9-17 * * mon-fri",
+// or one of the @hourly/@daily/@weekly/@monthly/@yearly descriptors.
+func ParseSchedule(expression string) (*Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if descriptor, ok := scheduleDescriptors[strings.ToLower(expression)]; ok {
+		expression = descriptor
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != len(scheduleFields) {
+		return nil, fmt.Errorf("expected %d fields (minute hour day-of-month month day-of-week), got %d",
+			len(scheduleFields), len(fields))
+	}
+
+	schedule := &Schedule{}
+	bits := []*uint64{&schedule.minute, &schedule.hour, &schedule.dom, &schedule.month, &schedule.dow}
+	for i, field := range fields {
+		var err error
+		if *bits[i], err = scheduleFields[i].parse(field); err != nil {
+			return nil, err
+		}
+	}
+	// Sunday is 0 or 7
+	if schedule.dow&(1<<7) != 0 {
+		schedule.dow |= 1
+	}
+	// As in cron, a day field starting with '*' (e.g. "*/2") must match as well as the other day field.
+	schedule.domStar = strings.HasPrefix(fields[2], "*") || fields[2] == "?"
+	schedule.dowStar = strings.HasPrefix(fields[4], "*") || fields[4] == "?"
+
+	return schedule, nil
+}
+
+// parse a field of a cron expression into the bitset of values it allows.
+func (f scheduleField) parse(field string) (bits uint64, err error) {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := uint(1)
+		if hasStep {
+			parsed, err := strconv.ParseUint(stepPart, 10, 8)
+			if err != nil || parsed == 0 {
+				return 0, fmt.Errorf("%s: invalid step %q", f.name, stepPart)
+			}
+			step = uint(parsed)
+		}
+
+		var low, high uint
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			low, high = f.min, f.max
+		case strings.Contains(rangePart, "-"):
+			lowPart, highPart, _ := strings.Cut(rangePart, "-")
+			if low, err = f.value(lowPart); err != nil {
+				return
+			}
+			if high, err = f.value(highPart); err != nil {
+				return
+			}
+			if low > high {
+				return 0, fmt.Errorf("%s: range %q is backwards", f.name, rangePart)
+			}
+		default:
+			if low, err = f.value(rangePart); err != nil {
+				return
+			}
+			high = low
+			// e.g. 5/15 = 5-59/15
+			if hasStep {
+				high = f.max
+			}
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << value
+		}
+	}
+	return
+}
+
+// value of a number/name in this field.
+func (f scheduleField) value(str string) (uint, error) {
+	if value, ok := f.names[strings.ToLower(str)]; ok {
+		return value, nil
+	}
+	value, err := strconv.ParseUint(str, 10, 8)
+	if err != nil || uint(value) < f.min || uint(value) > f.max {
+		return 0, fmt.Errorf("%s: %q is not in %d-%d", f.name, str, f.min, f.max)
+	}
+	return uint(value), nil
+}
+
+// Next returns the first time after `from` that matches the Schedule (in the location of `from`).
+//
+// Returns the zero time if there's no match within the next few years (e.g. 30th February).
+func (s *Schedule) Next(from time.Time) time.Time {
+	loc := from.Location()
+	t := from.Truncate(time.Minute).Add(time.Minute)
+	limit := from.Year() + scheduleMaxYears
+
+	for t.Year() <= limit {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches returns whether the day of `t` matches the day-of-month and day-of-week of the Schedule.
+//
+// When both are restricted, either can match (as with cron).
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
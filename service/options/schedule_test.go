@@ -0,0 +1,136 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package opt
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/release-argus/Argus/util"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// GIVEN a cron expression
+	tests := map[string]struct {
+		expression string
+		errRegex   string
+	}{
+		"every minute":        {expression: "* * * * *", errRegex: `^$`},
+		"business hours":      {expression: "*/15 9-17 * * mon-fri", errRegex: `^$`},
+		"lists and steps":     {expression: "0,30 */2 1-15/2 jan,jul 0", errRegex: `^$`},
+		"sunday as 7":         {expression: "0 0 * * 7", errRegex: `^$`},
+		"descriptor":          {expression: "@daily", errRegex: `^$`},
+		"too few fields":      {expression: "* * * *", errRegex: `^expected 5 fields .*, got 4$`},
+		"too many fields":     {expression: "0 * * * * *", errRegex: `^expected 5 fields .*, got 6$`},
+		"minute out of range": {expression: "60 * * * *", errRegex: `^minute: "60" is not in 0-59$`},
+		"hour out of range":   {expression: "0 24 * * *", errRegex: `^hour: "24" is not in 0-23$`},
+		"day of month 0":      {expression: "0 0 0 * *", errRegex: `^day of month: "0" is not in 1-31$`},
+		"unknown month name":  {expression: "0 0 * foo *", errRegex: `^month: "foo" is not in 1-12$`},
+		"backwards range":     {expression: "0 17-9 * * *", errRegex: `^hour: range "17-9" is backwards$`},
+		"zero step":           {expression: "*/0 * * * *", errRegex: `^minute: invalid step "0"$`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN ParseSchedule is called on it
+			_, err := ParseSchedule(tc.expression)
+
+			// THEN it errs when expected
+			e := util.ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			if !re.MatchString(e) {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// GIVEN a Schedule and a time
+	// Wednesday
+	from := time.Date(2023, time.May, 17, 10, 7, 30, 0, time.UTC)
+	tests := map[string]struct {
+		expression string
+		from       time.Time
+		want       time.Time
+	}{
+		"every minute": {
+			expression: "* * * * *",
+			want:       time.Date(2023, time.May, 17, 10, 8, 0, 0, time.UTC)},
+		"every 15 minutes in business hours": {
+			expression: "*/15 9-17 * * mon-fri",
+			want:       time.Date(2023, time.May, 17, 10, 15, 0, 0, time.UTC)},
+		"after business hours": {
+			expression: "*/15 9-17 * * mon-fri",
+			from:       time.Date(2023, time.May, 19, 17, 45, 0, 0, time.UTC),
+			want:       time.Date(2023, time.May, 22, 9, 0, 0, 0, time.UTC)},
+		"hourly": {
+			expression: "@hourly",
+			want:       time.Date(2023, time.May, 17, 11, 0, 0, 0, time.UTC)},
+		"next month": {
+			expression: "0 6 1 * *",
+			want:       time.Date(2023, time.June, 1, 6, 0, 0, 0, time.UTC)},
+		"next year": {
+			expression: "0 0 1 jan *",
+			want:       time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		"sunday as 7": {
+			expression: "30 8 * * 7",
+			want:       time.Date(2023, time.May, 21, 8, 30, 0, 0, time.UTC)},
+		"day of month or day of week": {
+			expression: "0 0 20 * fri",
+			want:       time.Date(2023, time.May, 19, 0, 0, 0, 0, time.UTC)},
+		"day of month step and day of week": {
+			expression: "0 0 */2 * mon",
+			want:       time.Date(2023, time.May, 29, 0, 0, 0, 0, time.UTC)},
+		"29th February": {
+			expression: "0 0 29 2 *",
+			want:       time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		"never": {
+			expression: "0 0 30 feb *",
+			want:       time.Time{}},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := ParseSchedule(tc.expression)
+			if err != nil {
+				t.Fatalf("invalid expression %q: %v",
+					tc.expression, err)
+			}
+			if tc.from.IsZero() {
+				tc.from = from
+			}
+
+			// WHEN Next is called
+			got := schedule.Next(tc.from)
+
+			// THEN the next matching time is returned
+			if !got.Equal(tc.want) {
+				t.Errorf("want: %s\ngot:  %s",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,613 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package opt
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+	"github.com/release-argus/Argus/util"
+)
+
+// DefaultVersionScheme is the version_scheme used when one isn't given.
+const DefaultVersionScheme = "semver"
+
+// VersionSchemes are the supported version_scheme's.
+var VersionSchemes = map[string]VersionScheme{
+	"calver":       calVerScheme{},
+	"debian":       debianScheme{},
+	"loose-semver": looseSemVerScheme{},
+	"pep440":       pep440Scheme{},
+	"semver":       semVerScheme{}}
+
+// Version is a version parsed by a VersionScheme.
+type Version interface {
+	LessThan(other Version) bool // Whether this version is older than `other` (of the same VersionScheme)
+	Release() []int64            // The numeric release parts, e.g. [1 2 3] for 1.2.3-rc.1
+	PreRelease() bool            // Whether this is a pre-release of its Release
+	String() string              // The version
+}
+
+// VersionScheme parses the versions of a versioning style.
+type VersionScheme interface {
+	Parse(version string) (Version, error) // Parse `version`, erroring if it doesn't follow this scheme
+	Style() string                         // Description of the style of versions
+}
+
+// ParseVersion will parse `version` with the version_scheme `scheme` (DefaultVersionScheme if empty).
+func ParseVersion(scheme string, version string) (Version, error) {
+	versionScheme, ok := VersionSchemes[util.FirstNonDefault(scheme, DefaultVersionScheme)]
+	if !ok {
+		return nil, fmt.Errorf("unknown version_scheme %q", scheme)
+	}
+	return versionScheme.Parse(version)
+}
+
+// lessThanString orders versions of different schemes by their string.
+func lessThanString(a, b Version) bool {
+	return a.String() < b.String()
+}
+
+// compareInt returns -1/0/1 when a is less than/equal to/greater than b.
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareParts compares numeric version parts, treating missing parts as 0 (1.2 == 1.2.0).
+func compareParts(a, b []int64) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var aPart, bPart int64
+		if i < len(a) {
+			aPart = a[i]
+		}
+		if i < len(b) {
+			bPart = b[i]
+		}
+		if c := compareInt(aPart, bPart); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// comparePreRelease compares the pre-release identifiers of versions with semver precedence.
+//
+// No pre-release is greater than any pre-release, numeric identifiers are lower than alphanumeric ones,
+// and a larger set of identifiers is greater if all the preceding ones are equal.
+func comparePreRelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	aIDs, bIDs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		aNum, aErr := strconv.ParseInt(aIDs[i], 10, 64)
+		bNum, bErr := strconv.ParseInt(bIDs[i], 10, 64)
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareInt(aNum, bNum)
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(aIDs[i], bIDs[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInt(int64(len(aIDs)), int64(len(bIDs)))
+}
+
+// semVerScheme is Semantic Versioning 2.0.0 (https://semver.org/), e.g. 1.2.3, 1.2.3-rc.1+build.5.
+type semVerScheme struct{}
+
+// semVerVersion is a version of the semVerScheme.
+type semVerVersion struct {
+	*semver.Version
+}
+
+// Parse `version` as a semantic version.
+func (semVerScheme) Parse(version string) (Version, error) {
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	return semVerVersion{v}, nil
+}
+
+// Style of semantic versions.
+func (semVerScheme) Style() string {
+	return "'MAJOR.MINOR.PATCH' (https://semver.org/)"
+}
+
+// LessThan returns whether this version is older than `other`.
+func (v semVerVersion) LessThan(other Version) bool {
+	o, ok := other.(semVerVersion)
+	if !ok {
+		return lessThanString(v, other)
+	}
+	return v.Version.LessThan(*o.Version)
+}
+
+// Release returns the MAJOR.MINOR.PATCH of this version.
+func (v semVerVersion) Release() []int64 {
+	return []int64{v.Major, v.Minor, v.Patch}
+}
+
+// PreRelease returns whether this version has a pre-release.
+func (v semVerVersion) PreRelease() bool {
+	return v.Version.PreRelease != ""
+}
+
+// numericVersion is a version of numeric parts with an optional pre-release, e.g. 1.2.3.4, 2024.10.1-beta.1.
+type numericVersion struct {
+	raw        string
+	parts      []int64
+	preRelease string
+}
+
+// parseNumericVersion parses `version` as numeric parts separated by '.',
+// optionally prefixed with a 'v', and followed by a '-' pre-release and/or '+' build metadata.
+func parseNumericVersion(version string) (parsed numericVersion, err error) {
+	parsed.raw = version
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	version, _, _ = strings.Cut(version, "+")
+	version, parsed.preRelease, _ = strings.Cut(version, "-")
+
+	for _, part := range strings.Split(version, ".") {
+		var number int64
+		if number, err = strconv.ParseInt(part, 10, 64); err != nil || number < 0 || part[0] == '+' {
+			err = fmt.Errorf("%q is not a version of numeric parts separated by '.'", parsed.raw)
+			return
+		}
+		parsed.parts = append(parsed.parts, number)
+	}
+	return
+}
+
+// compare returns -1/0/1 when this version is less than/equal to/greater than `other`.
+func (v numericVersion) compare(other numericVersion) int {
+	if c := compareParts(v.parts, other.parts); c != 0 {
+		return c
+	}
+	return comparePreRelease(v.preRelease, other.preRelease)
+}
+
+// Release returns the numeric parts of this version.
+func (v numericVersion) Release() []int64 {
+	return v.parts
+}
+
+// PreRelease returns whether this version has a pre-release.
+func (v numericVersion) PreRelease() bool {
+	return v.preRelease != ""
+}
+
+// looseSemVerScheme is Semantic Versioning with any number of parts, an optional 'v' prefix and leading zeros allowed,
+// e.g. v1.2, 1.2.3.4, 1.02.3-rc.1.
+type looseSemVerScheme struct{}
+
+// looseSemVerVersion is a version of the looseSemVerScheme.
+type looseSemVerVersion struct {
+	numericVersion
+}
+
+// Parse `version` as a loose semantic version.
+func (looseSemVerScheme) Parse(version string) (Version, error) {
+	v, err := parseNumericVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	return looseSemVerVersion{v}, nil
+}
+
+// Style of loose semantic versions.
+func (looseSemVerScheme) Style() string {
+	return "'[v]MAJOR[.MINOR[.PATCH[...]]][-PRERELEASE]'"
+}
+
+// LessThan returns whether this version is older than `other`.
+func (v looseSemVerVersion) LessThan(other Version) bool {
+	o, ok := other.(looseSemVerVersion)
+	if !ok {
+		return lessThanString(v, other)
+	}
+	return v.compare(o.numericVersion) < 0
+}
+
+// String returns the version.
+func (v looseSemVerVersion) String() string {
+	return v.raw
+}
+
+// calVerScheme is Calendar Versioning (https://calver.org/), e.g. 2024.10.1, 24.04, 2024.01.15-beta.
+type calVerScheme struct{}
+
+// calVerVersion is a version of the calVerScheme.
+type calVerVersion struct {
+	numericVersion
+}
+
+// Parse `version` as a calendar version.
+func (calVerScheme) Parse(version string) (Version, error) {
+	v, err := parseNumericVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	year, _, _ := strings.Cut(strings.TrimLeft(version, "vV"), ".")
+	if len(v.parts) < 2 || (len(year) != 2 && len(year) != 4) {
+		return nil, fmt.Errorf("%q is not a calendar version (YYYY.MM[.MICRO] or YY.MM[.MICRO])", version)
+	}
+	return calVerVersion{v}, nil
+}
+
+// Style of calendar versions.
+func (calVerScheme) Style() string {
+	return "'YYYY.MM[.MICRO]' or 'YY.MM[.MICRO]' (https://calver.org/)"
+}
+
+// LessThan returns whether this version is older than `other`.
+func (v calVerVersion) LessThan(other Version) bool {
+	o, ok := other.(calVerVersion)
+	if !ok {
+		return lessThanString(v, other)
+	}
+	return v.compare(o.numericVersion) < 0
+}
+
+// String returns the version.
+func (v calVerVersion) String() string {
+	return v.raw
+}
+
+// pep440Regex matches a PEP 440 version (https://peps.python.org/pep-0440/#appendix-b-parsing-version-strings-with-regular-expressions).
+var pep440Regex = regexp.MustCompile(`(?i)^\s*v?` +
+	`(?:([0-9]+)!)?` + // epoch
+	`([0-9]+(?:\.[0-9]+)*)` + // release
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?([0-9]+)?)?` + // pre-release
+	`(?:-([0-9]+)|[-_.]?(post|rev|r)[-_.]?([0-9]+)?)?` + // post-release
+	`(?:[-_.]?(dev)[-_.]?([0-9]+)?)?` + // dev-release
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?` + // local
+	`\s*$`)
+
+// pep440Scheme is Python's PEP 440 (https://peps.python.org/pep-0440/), e.g. 1.0rc1, 1.0.post2, 2!1.0.dev3.
+type pep440Scheme struct{}
+
+// pep440Version is a version of the pep440Scheme.
+type pep440Version struct {
+	raw     string
+	epoch   int64
+	release []int64
+	pre     *[2]int64 // phase (a=0, b=1, rc=2), number
+	post    *int64
+	dev     *int64
+	local   string
+}
+
+// Parse `version` as a PEP 440 version.
+func (pep440Scheme) Parse(version string) (Version, error) {
+	match := pep440Regex.FindStringSubmatch(version)
+	if match == nil {
+		return nil, fmt.Errorf("%q is not a PEP 440 version", version)
+	}
+
+	parsed := pep440Version{
+		raw:   version,
+		local: strings.ToLower(match[10])}
+	parsed.epoch, _ = strconv.ParseInt(util.FirstNonDefault(match[1], "0"), 10, 64)
+	for _, part := range strings.Split(match[2], ".") {
+		number, _ := strconv.ParseInt(part, 10, 64)
+		parsed.release = append(parsed.release, number)
+	}
+	if match[3] != "" {
+		phase := int64(2)
+		switch strings.ToLower(match[3]) {
+		case "a", "alpha":
+			phase = 0
+		case "b", "beta":
+			phase = 1
+		}
+		number, _ := strconv.ParseInt(util.FirstNonDefault(match[4], "0"), 10, 64)
+		parsed.pre = &[2]int64{phase, number}
+	}
+	if match[5] != "" || match[6] != "" {
+		number, _ := strconv.ParseInt(util.FirstNonDefault(match[5], match[7], "0"), 10, 64)
+		parsed.post = &number
+	}
+	if match[8] != "" {
+		number, _ := strconv.ParseInt(util.FirstNonDefault(match[9], "0"), 10, 64)
+		parsed.dev = &number
+	}
+	return parsed, nil
+}
+
+// Style of PEP 440 versions.
+func (pep440Scheme) Style() string {
+	return "'[N!]N(.N)*[{a|b|rc}N][.postN][.devN]' (https://peps.python.org/pep-0440/)"
+}
+
+// LessThan returns whether this version is older than `other`.
+func (v pep440Version) LessThan(other Version) bool {
+	o, ok := other.(pep440Version)
+	if !ok {
+		return lessThanString(v, other)
+	}
+	return v.compare(o) < 0
+}
+
+// String returns the version.
+func (v pep440Version) String() string {
+	return v.raw
+}
+
+// Release returns the release segment of this version.
+func (v pep440Version) Release() []int64 {
+	return v.release
+}
+
+// PreRelease returns whether this is a pre-release or dev-release.
+func (v pep440Version) PreRelease() bool {
+	return v.pre != nil || v.dev != nil
+}
+
+// compare returns -1/0/1 when this version is less than/equal to/greater than `other`.
+//
+// Ordered by epoch, release, then dev-only releases < pre-releases < final releases < post-releases,
+// with dev-releases before their pre/final/post release, and local versions after the public version.
+func (v pep440Version) compare(other pep440Version) int {
+	if c := compareInt(v.epoch, other.epoch); c != 0 {
+		return c
+	}
+	if c := compareParts(v.release, other.release); c != 0 {
+		return c
+	}
+	vPhase, vPre := v.preKey()
+	oPhase, oPre := other.preKey()
+	if c := compareInt(vPhase, oPhase); c != 0 {
+		return c
+	}
+	if c := compareInt(vPre, oPre); c != 0 {
+		return c
+	}
+	if c := compareInt(optionalInt(v.post, -1), optionalInt(other.post, -1)); c != 0 {
+		return c
+	}
+	if c := compareInt(optionalInt(v.dev, 1<<62), optionalInt(other.dev, 1<<62)); c != 0 {
+		return c
+	}
+	return compareLocal(v.local, other.local)
+}
+
+// preKey returns the phase and number to order the pre-release of this version by.
+func (v pep440Version) preKey() (phase int64, number int64) {
+	switch {
+	// Dev-releases of the final release are before its pre-releases, e.g. 1.0.dev1 < 1.0a1
+	case v.pre == nil && v.post == nil && v.dev != nil:
+		return -1, 0
+	// Final releases are after their pre-releases
+	case v.pre == nil:
+		return 3, 0
+	}
+	return v.pre[0], v.pre[1]
+}
+
+// optionalInt returns the value of `number`, or `nilValue` if it's nil.
+func optionalInt(number *int64, nilValue int64) int64 {
+	if number == nil {
+		return nilValue
+	}
+	return *number
+}
+
+// compareLocal compares PEP 440 local version labels.
+//
+// No label is less than any label, and numeric segments are greater than alphanumeric ones.
+func compareLocal(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return -1
+	case b == "":
+		return 1
+	}
+
+	separators := func(r rune) bool { return r == '.' || r == '-' || r == '_' }
+	aSegments, bSegments := strings.FieldsFunc(a, separators), strings.FieldsFunc(b, separators)
+	for i := 0; i < len(aSegments) && i < len(bSegments); i++ {
+		aNum, aErr := strconv.ParseInt(aSegments[i], 10, 64)
+		bNum, bErr := strconv.ParseInt(bSegments[i], 10, 64)
+		var c int
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareInt(aNum, bNum)
+		case aErr == nil:
+			c = 1
+		case bErr == nil:
+			c = -1
+		default:
+			c = strings.Compare(aSegments[i], bSegments[i])
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compareInt(int64(len(aSegments)), int64(len(bSegments)))
+}
+
+// debianVersionRegex matches a Debian package version, [EPOCH:]UPSTREAM_VERSION[-DEBIAN_REVISION].
+var debianVersionRegex = regexp.MustCompile(`^(?:([0-9]+):)?([0-9][A-Za-z0-9.+~:-]*?)(?:-([A-Za-z0-9.+~]+))?$`)
+
+// debianScheme is the Debian package version format (https://www.debian.org/doc/debian-policy/ch-controlfields.html#version),
+// e.g. 1:2.30-1ubuntu1, 1.2.3~rc1.
+type debianScheme struct{}
+
+// debianVersion is a version of the debianScheme.
+type debianVersion struct {
+	raw      string
+	epoch    int64
+	upstream string
+	revision string
+}
+
+// Parse `version` as a Debian version.
+func (debianScheme) Parse(version string) (Version, error) {
+	match := debianVersionRegex.FindStringSubmatch(version)
+	if match == nil {
+		return nil, fmt.Errorf("%q is not a Debian version", version)
+	}
+	// Colons are only allowed in the upstream version when there's an epoch,
+	// and hyphens only when there's a revision.
+	if (match[1] == "" && strings.Contains(match[2], ":")) ||
+		(match[3] == "" && strings.Contains(match[2], "-")) {
+		return nil, fmt.Errorf("%q is not a Debian version", version)
+	}
+
+	parsed := debianVersion{
+		raw:      version,
+		upstream: match[2],
+		revision: match[3]}
+	parsed.epoch, _ = strconv.ParseInt(util.FirstNonDefault(match[1], "0"), 10, 64)
+	return parsed, nil
+}
+
+// Style of Debian versions.
+func (debianScheme) Style() string {
+	return "'[EPOCH:]UPSTREAM_VERSION[-DEBIAN_REVISION]' (https://www.debian.org/doc/debian-policy/ch-controlfields.html#version)"
+}
+
+// LessThan returns whether this version is older than `other`.
+func (v debianVersion) LessThan(other Version) bool {
+	o, ok := other.(debianVersion)
+	if !ok {
+		return lessThanString(v, other)
+	}
+
+	if c := compareInt(v.epoch, o.epoch); c != 0 {
+		return c < 0
+	}
+	if c := debianCompare(v.upstream, o.upstream); c != 0 {
+		return c < 0
+	}
+	return debianCompare(v.revision, o.revision) < 0
+}
+
+// String returns the version.
+func (v debianVersion) String() string {
+	return v.raw
+}
+
+// Release returns the leading numeric parts of the upstream version, e.g. [2 30] for 2.30+dfsg-1.
+func (v debianVersion) Release() (release []int64) {
+	for _, part := range strings.Split(v.upstream, ".") {
+		digits := part[:len(part)-len(strings.TrimLeft(part, "0123456789"))]
+		if digits == "" {
+			break
+		}
+		number, _ := strconv.ParseInt(digits, 10, 64)
+		release = append(release, number)
+		if len(digits) != len(part) {
+			break
+		}
+	}
+	return
+}
+
+// PreRelease returns whether the upstream version is a pre-release ('~' sorts before the release, e.g. 1.2~rc1).
+func (v debianVersion) PreRelease() bool {
+	return strings.Contains(v.upstream, "~")
+}
+
+// debianOrder returns the sort weight of a character in the non-digit part of a Debian version.
+//
+// '~' sorts before everything (even the end of the part), then letters, then all other characters.
+func debianOrder(c byte) int {
+	switch {
+	case c == '~':
+		return -1
+	case '0' <= c && c <= '9':
+		return 0
+	case ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z'):
+		return int(c)
+	}
+	return int(c) + 256
+}
+
+// debianCompare compares two Debian upstream versions/revisions with the dpkg algorithm,
+// comparing alternating non-digit and digit parts.
+func debianCompare(a, b string) int {
+	isDigit := func(s string, i int) bool { return i < len(s) && '0' <= s[i] && s[i] <= '9' }
+	at := func(s string, i int) int {
+		if i < len(s) {
+			return debianOrder(s[i])
+		}
+		return 0
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		// Non-digit part
+		for (i < len(a) && !isDigit(a, i)) || (j < len(b) && !isDigit(b, j)) {
+			if ac, bc := at(a, i), at(b, j); ac != bc {
+				return compareInt(int64(ac), int64(bc))
+			}
+			i++
+			j++
+		}
+
+		// Digit part (ignoring leading zeros)
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		firstDiff := 0
+		for isDigit(a, i) && isDigit(b, j) {
+			if firstDiff == 0 {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		if isDigit(a, i) {
+			return 1
+		}
+		if isDigit(b, j) {
+			return -1
+		}
+		if firstDiff != 0 {
+			return compareInt(int64(firstDiff), 0)
+		}
+	}
+	return 0
+}
@@ -0,0 +1,182 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use 10s file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package opt
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	// GIVEN a version_scheme and versions
+	tests := map[string]struct {
+		scheme   string
+		versions map[string]bool // version: valid
+	}{
+		"default (semver)": {
+			scheme: "",
+			versions: map[string]bool{
+				"1.2.3": true, "1.2.3-rc.1+build.5": true, "1.2": false, "v1.2.3": false, "2024.1": false}},
+		"semver": {
+			scheme: "semver",
+			versions: map[string]bool{
+				"0.0.1": true, "1.2.3.4": false, "1.2.x": false}},
+		"loose-semver": {
+			scheme: "loose-semver",
+			versions: map[string]bool{
+				"v1.2": true, "1.2.3.4": true, "1.02.3": true, "1": true, "1.2.3-rc.1+build": true,
+				"1..2": false, "1.2a": false, "": false, "release-1.2": false}},
+		"calver": {
+			scheme: "calver",
+			versions: map[string]bool{
+				"2024.10.1": true, "24.04": true, "2024.01.15-beta": true, "v2024.1": true,
+				"2024": false, "202.1": false, "2024.x": false}},
+		"pep440": {
+			scheme: "pep440",
+			versions: map[string]bool{
+				"1.0": true, "1.0rc1": true, "1.0.post2": true, "2!1.0.dev3": true, "1.0a1.dev2": true,
+				"v1.0-beta.2": true, "1.0+ubuntu.1": true, "1.0-1": true,
+				"1.0-foo": false, "1.0.x": false, "one": false}},
+		"debian": {
+			scheme: "debian",
+			versions: map[string]bool{
+				"1.2.3": true, "1:2.30-1ubuntu1": true, "1.2.3~rc1-2": true, "2:1.0:3-1": true,
+				"1.0:3": false, "a1.0": false, "1.0-": false, "x:1.0": false}},
+		"unknown scheme": {
+			scheme: "romver",
+			versions: map[string]bool{
+				"1.2.3": false}},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for version, valid := range tc.versions {
+				// WHEN ParseVersion is called on the version
+				got, err := ParseVersion(tc.scheme, version)
+
+				// THEN only versions of the scheme are parsed
+				if (err == nil) != valid {
+					t.Errorf("%q - want valid=%t, got err=%v",
+						version, valid, err)
+					continue
+				}
+				// AND the version is kept as-is
+				if valid && got.String() != version {
+					t.Errorf("%q - String() returned %q",
+						version, got.String())
+				}
+			}
+		})
+	}
+}
+
+func TestVersion_LessThan(t *testing.T) {
+	// GIVEN a version_scheme and versions in ascending order
+	tests := map[string]struct {
+		scheme   string
+		versions []string
+	}{
+		"semver": {
+			scheme: "semver",
+			versions: []string{
+				"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0",
+				"1.2.9", "1.2.10", "2.0.0"}},
+		"loose-semver": {
+			scheme: "loose-semver",
+			versions: []string{
+				"v0.9", "1", "1.0.1-rc.1", "1.0.1", "1.0.1.1", "1.02.0", "v1.10"}},
+		"calver": {
+			scheme: "calver",
+			versions: []string{
+				"2023.12.31", "2024.01.15-beta", "2024.01.15", "2024.1.16", "2024.10"}},
+		"pep440": {
+			scheme: "pep440",
+			versions: []string{
+				"1.0.dev0", "1.0a1.dev1", "1.0a1", "1.0a2", "1.0b1", "1.0rc1", "1.0", "1.0+local.1", "1.0+local.2",
+				"1.0.post1.dev1", "1.0.post1", "1.1.dev1", "1.1", "1.10", "1!0.1"}},
+		"debian": {
+			scheme: "debian",
+			versions: []string{
+				"1.0~rc1", "1.0", "1.0-1", "1.0-1ubuntu1", "1.0-2", "1.0a", "1.0+dfsg", "1.0.1", "1.10", "1:0.1"}},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			for i := range tc.versions {
+				older, err := ParseVersion(tc.scheme, tc.versions[i])
+				if err != nil {
+					t.Fatalf("%q failed to parse: %v",
+						tc.versions[i], err)
+				}
+				for _, newerVersion := range tc.versions[i+1:] {
+					newer, _ := ParseVersion(tc.scheme, newerVersion)
+
+					// WHEN LessThan is called on two versions
+					// THEN the older version is less than the newer version
+					if !older.LessThan(newer) {
+						t.Errorf("want %q < %q",
+							tc.versions[i], newerVersion)
+					}
+					// AND the newer version is not less than the older version
+					if newer.LessThan(older) {
+						t.Errorf("want %q > %q",
+							newerVersion, tc.versions[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVersion_LessThanEqual(t *testing.T) {
+	// GIVEN a version_scheme and two equal versions
+	tests := map[string]struct {
+		scheme string
+		a, b   string
+	}{
+		"loose-semver - trailing zeros": {
+			scheme: "loose-semver", a: "1.2", b: "1.2.0"},
+		"calver - leading zeros": {
+			scheme: "calver", a: "2024.01", b: "2024.1"},
+		"pep440 - aliases": {
+			scheme: "pep440", a: "1.0-alpha-1", b: "1.0a1"},
+		"debian - zero epoch": {
+			scheme: "debian", a: "0:1.0-1", b: "1.0-1"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			a, _ := ParseVersion(tc.scheme, tc.a)
+			b, _ := ParseVersion(tc.scheme, tc.b)
+
+			// WHEN LessThan is called on equal versions
+			// THEN neither is less than the other
+			if a.LessThan(b) || b.LessThan(a) {
+				t.Errorf("want %q == %q",
+					tc.a, tc.b)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultMaxConcurrent is the default limit of Jobs running at once.
+//
+// (settings.query.max_concurrent overrides this on the Default Scheduler)
+const DefaultMaxConcurrent = 10
+
+// Default Scheduler that the queries of every Service are run on.
+var Default = New(DefaultMaxConcurrent)
+
+// RunFunc is the work of a Job.
+//
+// It returns the time to run the Job next, or the zero time to stop running it.
+type RunFunc func(ctx context.Context) time.Time
+
+// Scheduler runs Jobs at their next run time from a single goroutine,
+// with no more than maxConcurrent of them running at once.
+type Scheduler struct {
+	queue     jobQueue      // Jobs waiting to run, soonest first
+	mutex     sync.Mutex    // Lock for the queue and slots
+	wake      chan struct{} // Signal that the queue has changed
+	slots     chan struct{} // Semaphore of the running Jobs
+	startOnce sync.Once     // Start the loop on the first Add
+}
+
+// New returns a Scheduler that runs at most `maxConcurrent` Jobs at once.
+func New(maxConcurrent int) *Scheduler {
+	return &Scheduler{
+		wake:  make(chan struct{}, 1),
+		slots: newSlots(maxConcurrent)}
+}
+
+// SetMaxConcurrent changes the limit of Jobs running at once to `maxConcurrent`.
+//
+// Jobs already running count towards the limit they started under.
+func (s *Scheduler) SetMaxConcurrent(maxConcurrent int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if cap(s.slots) == maxConcurrent {
+		return
+	}
+	s.slots = newSlots(maxConcurrent)
+}
+
+// newSlots returns a semaphore for `maxConcurrent` (at least 1) Jobs.
+func newSlots(maxConcurrent int) chan struct{} {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return make(chan struct{}, maxConcurrent)
+}
+
+// Add a Job, `id`, to run `run` at `at`, and then at each time it returns.
+//
+// The Job stops, and is dropped from the queue, once `ctx` is cancelled.
+func (s *Scheduler) Add(ctx context.Context, id string, at time.Time, run RunFunc) {
+	s.startOnce.Do(func() { go s.Run(context.Background()) })
+	s.push(&job{
+		id:  id,
+		ctx: ctx,
+		run: run,
+		at:  at})
+}
+
+// Len returns the number of Jobs waiting to run.
+func (s *Scheduler) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.dropCancelled()
+	return len(s.queue)
+}
+
+// Run the Jobs as they become due until `ctx` is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		jobs, wait := s.due()
+		for _, job := range jobs {
+			// Wait for a free slot.
+			slots := s.getSlots()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go s.run(job, slots)
+		}
+		// Due Jobs may have been added whilst waiting for slots.
+		if len(jobs) != 0 {
+			continue
+		}
+
+		var (
+			timer  *time.Timer
+			timerC <-chan time.Time
+		)
+		if wait >= 0 {
+			timer = time.NewTimer(wait)
+			timerC = timer.C
+		}
+		select {
+		case <-ctx.Done():
+		case <-s.wake:
+		case <-timerC:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// getSlots returns the semaphore of the running Jobs.
+func (s *Scheduler) getSlots() chan struct{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.slots
+}
+
+// due pops the Jobs that are due to run, and returns them with the wait until the next one.
+//
+// (wait is -1 when the queue is empty)
+func (s *Scheduler) due() (jobs []*job, wait time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.dropCancelled()
+	for len(s.queue) != 0 {
+		next := s.queue[0]
+		if until := time.Until(next.at); until > 0 {
+			return jobs, until
+		}
+		jobs = append(jobs, heap.Pop(&s.queue).(*job))
+	}
+	return jobs, -1
+}
+
+// dropCancelled removes the Jobs whose context has been cancelled from the queue.
+//
+// (s.mutex must be held)
+func (s *Scheduler) dropCancelled() {
+	queued := s.queue[:0]
+	for _, j := range s.queue {
+		if j.ctx.Err() == nil {
+			queued = append(queued, j)
+		}
+	}
+	if len(queued) == len(s.queue) {
+		return
+	}
+	for i := len(queued); i < len(s.queue); i++ {
+		s.queue[i] = nil
+	}
+	s.queue = queued
+	heap.Init(&s.queue)
+}
+
+// run the Job in one of `slots`, and queue it to run again at the time it returns.
+func (s *Scheduler) run(j *job, slots chan struct{}) {
+	// Cancelled whilst waiting for the slot.
+	if j.ctx.Err() != nil {
+		<-slots
+		return
+	}
+	next := j.run(j.ctx)
+	<-slots
+
+	if next.IsZero() || j.ctx.Err() != nil {
+		return
+	}
+	j.at = next
+	s.push(j)
+}
+
+// push the Job onto the queue and wake the loop.
+func (s *Scheduler) push(j *job) {
+	s.mutex.Lock()
+	heap.Push(&s.queue, j)
+	s.mutex.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// job is a RunFunc queued to run at a time.
+type job struct {
+	id  string          // ID of the Job
+	ctx context.Context // Cancelled when the Job should stop
+	run RunFunc         // Work to run
+	at  time.Time       // Time to run next
+}
+
+// jobQueue is a min-heap of Jobs by their next run time.
+type jobQueue []*job
+
+func (q jobQueue) Len() int { return len(q) }
+
+func (q jobQueue) Less(i, j int) bool {
+	if q[i].at.Equal(q[j].at) {
+		return q[i].id < q[j].id
+	}
+	return q[i].at.Before(q[j].at)
+}
+
+func (q jobQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *jobQueue) Push(x any) { *q = append(*q, x.(*job)) }
+
+func (q *jobQueue) Pop() any {
+	old := *q
+	n := len(old)
+	j := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return j
+}
@@ -0,0 +1,276 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduler_Order(t *testing.T) {
+	// GIVEN a Scheduler with Jobs added out of order
+	scheduler := New(1)
+	now := time.Now()
+	var (
+		mutex sync.Mutex
+		order []string
+		done  = make(chan struct{}, 3)
+	)
+	for _, tc := range []struct {
+		id string
+		at time.Duration
+	}{
+		{id: "c", at: 300 * time.Millisecond},
+		{id: "a", at: 100 * time.Millisecond},
+		{id: "b", at: 200 * time.Millisecond},
+	} {
+		id := tc.id
+		// WHEN they become due
+		scheduler.Add(context.Background(), id, now.Add(tc.at), func(context.Context) time.Time {
+			mutex.Lock()
+			order = append(order, id)
+			mutex.Unlock()
+			done <- struct{}{}
+			return time.Time{}
+		})
+	}
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("only %d/3 Jobs ran", i)
+		}
+	}
+
+	// THEN they run in the order of their run times
+	mutex.Lock()
+	defer mutex.Unlock()
+	if got := strings.Join(order, ","); got != "a,b,c" {
+		t.Errorf("want order a,b,c, not %q",
+			got)
+	}
+	// AND Jobs that returned the zero time aren't queued again
+	if got := scheduler.Len(); got != 0 {
+		t.Errorf("want 0 Jobs queued, not %d",
+			got)
+	}
+}
+
+func TestScheduler_Reschedule(t *testing.T) {
+	// GIVEN a Scheduler with a Job that runs 3 times
+	scheduler := New(1)
+	var runs atomic.Int32
+	done := make(chan struct{})
+
+	// WHEN it's added
+	scheduler.Add(context.Background(), "job", time.Now(), func(context.Context) time.Time {
+		if runs.Add(1) == 3 {
+			close(done)
+			return time.Time{}
+		}
+		return time.Now().Add(10 * time.Millisecond)
+	})
+
+	// THEN it runs at each time it returns
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatalf("want 3 runs, got %d",
+			runs.Load())
+	}
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	// GIVEN a Scheduler with a Job that's cancelled before it's due
+	scheduler := New(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	var ran atomic.Bool
+	scheduler.Add(ctx, "cancelled", time.Now().Add(50*time.Millisecond), func(context.Context) time.Time {
+		ran.Store(true)
+		return time.Now()
+	})
+
+	// WHEN the context is cancelled
+	cancel()
+	done := make(chan struct{})
+	scheduler.Add(context.Background(), "after", time.Now().Add(100*time.Millisecond), func(context.Context) time.Time {
+		close(done)
+		return time.Time{}
+	})
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Job after the cancelled one didn't run")
+	}
+
+	// THEN the Job is dropped without running
+	if ran.Load() {
+		t.Error("cancelled Job ran")
+	}
+	if got := scheduler.Len(); got != 0 {
+		t.Errorf("want 0 Jobs queued, not %d",
+			got)
+	}
+}
+
+func TestScheduler_CancelRemoves(t *testing.T) {
+	// GIVEN a Scheduler with a Job that isn't due for a long time
+	scheduler := New(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Add(ctx, "later", time.Now().Add(time.Hour), func(context.Context) time.Time {
+		return time.Time{}
+	})
+	if got := scheduler.Len(); got != 1 {
+		t.Fatalf("want 1 Job queued, not %d",
+			got)
+	}
+
+	// WHEN its context is cancelled
+	cancel()
+
+	// THEN the Job is no longer queued without waiting until it's due
+	if got := scheduler.Len(); got != 0 {
+		t.Errorf("want 0 Jobs queued, not %d",
+			got)
+	}
+}
+
+func TestScheduler_CancelWhileWaitingForSlot(t *testing.T) {
+	// GIVEN a Scheduler with a Job due, waiting for the only slot
+	scheduler := New(1)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	scheduler.Add(context.Background(), "a-running", time.Now(), func(context.Context) time.Time {
+		close(started)
+		<-release
+		return time.Time{}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	var ran atomic.Bool
+	scheduler.Add(ctx, "b-waiting", time.Now(), func(context.Context) time.Time {
+		ran.Store(true)
+		return time.Time{}
+	})
+	<-started
+	deadline := time.Now().Add(2 * time.Second)
+	for scheduler.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Job waiting for the slot wasn't taken off the queue")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// WHEN its context is cancelled before the slot frees up
+	cancel()
+	close(release)
+	done := make(chan struct{})
+	scheduler.Add(context.Background(), "c-after", time.Now(), func(context.Context) time.Time {
+		close(done)
+		return time.Time{}
+	})
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Job after the cancelled one didn't run")
+	}
+
+	// THEN the cancelled Job doesn't run
+	if ran.Load() {
+		t.Error("Job cancelled whilst waiting for a slot ran")
+	}
+}
+
+func TestScheduler_MaxConcurrent(t *testing.T) {
+	// GIVEN a Scheduler that runs at most 2 Jobs at once
+	maxConcurrent := 2
+	scheduler := New(maxConcurrent)
+	testMaxConcurrent(t, scheduler, maxConcurrent)
+}
+
+func TestScheduler_SetMaxConcurrent(t *testing.T) {
+	// GIVEN a Scheduler whose limit is changed to 3 Jobs at once
+	maxConcurrent := 3
+	scheduler := New(DefaultMaxConcurrent)
+	scheduler.SetMaxConcurrent(maxConcurrent)
+	testMaxConcurrent(t, scheduler, maxConcurrent)
+}
+
+// testMaxConcurrent checks that `scheduler` runs no more than `maxConcurrent` Jobs at once.
+func testMaxConcurrent(t *testing.T, scheduler *Scheduler, maxConcurrent int) {
+	t.Helper()
+	var (
+		running    atomic.Int32
+		maxRunning atomic.Int32
+		wg         sync.WaitGroup
+	)
+	jobs := 6
+	wg.Add(jobs)
+
+	// WHEN more Jobs than that are due at once
+	for i := 0; i < jobs; i++ {
+		scheduler.Add(context.Background(), string(rune('a'+i)), time.Now(), func(context.Context) time.Time {
+			defer wg.Done()
+			now := running.Add(1)
+			for {
+				max := maxRunning.Load()
+				if now <= max || maxRunning.CompareAndSwap(max, now) {
+					break
+				}
+			}
+			time.Sleep(50 * time.Millisecond)
+			running.Add(-1)
+			return time.Time{}
+		})
+	}
+	wg.Wait()
+
+	// THEN no more than that run at once
+	if got := maxRunning.Load(); got != int32(maxConcurrent) {
+		t.Errorf("want %d Jobs running at once, not %d",
+			maxConcurrent, got)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	// GIVEN a Scheduler running with a Job due later
+	scheduler := New(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	finished := make(chan struct{})
+	go func() {
+		scheduler.Run(ctx)
+		close(finished)
+	}()
+	scheduler.push(&job{
+		id:  "later",
+		ctx: context.Background(),
+		run: func(context.Context) time.Time { return time.Time{} },
+		at:  time.Now().Add(time.Hour)})
+
+	// WHEN its context is cancelled
+	cancel()
+
+	// THEN Run returns
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run didn't return after its context was cancelled")
+	}
+}
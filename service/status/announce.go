@@ -34,7 +34,8 @@ func (s *Status) AnnounceFirstVersion() {
 			WebURL: s.GetWebURL(),
 			Status: &api_type.Status{
 				LatestVersion:          s.LatestVersion(),
-				LatestVersionTimestamp: s.LatestVersionTimestamp()}}})
+				LatestVersionTimestamp: s.LatestVersionTimestamp(),
+				LatestVersionRelease:   s.LatestVersionReleaseSummary()}}})
 
 	s.SendAnnounce(&payloadData)
 }
@@ -51,7 +52,12 @@ func (s *Status) AnnounceQuery() {
 		ServiceData: &api_type.ServiceSummary{
 			ID: *s.ServiceID,
 			Status: &api_type.Status{
-				LastQueried: s.LastQueried()}}})
+				LastQueried:             s.LastQueried(),
+				NextQuery:               s.NextQuery(),
+				PendingVersion:          s.PendingVersion(),
+				PendingVersionTimestamp: s.PendingVersionTimestamp(),
+				ConsecutiveFailures:     s.ConsecutiveFailures(),
+				LastError:               s.LastError()}}})
 
 	s.SendAnnounce(&payloadData)
 }
@@ -71,7 +77,8 @@ func (s *Status) AnnounceQueryNewVersion() {
 			WebURL: s.GetWebURL(),
 			Status: &api_type.Status{
 				LatestVersion:          s.LatestVersion(),
-				LatestVersionTimestamp: s.LatestVersionTimestamp()}}})
+				LatestVersionTimestamp: s.LatestVersionTimestamp(),
+				LatestVersionRelease:   s.LatestVersionReleaseSummary()}}})
 
 	s.SendAnnounce(&payloadData)
 }
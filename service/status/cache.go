@@ -0,0 +1,135 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package svcstatus
+
+import (
+	"encoding/json"
+	"net/http"
+
+	dbtype "github.com/release-argus/Argus/db/types"
+)
+
+// HTTPCache is the last response of a URL, kept for conditional requests
+// (If-None-Match/If-Modified-Since) so that an unchanged page can be reused.
+type HTTPCache struct {
+	URL          string `json:"url"`                     // URL the response came from.
+	ETag         string `json:"etag,omitempty"`          // ETag header of the response.
+	LastModified string `json:"last_modified,omitempty"` // Last-Modified header of the response.
+	Body         string `json:"body,omitempty"`          // Body of the response.
+}
+
+// ParseHTTPCache will parse the HTTPCache stored in the database.
+func ParseHTTPCache(data string) (cache HTTPCache) {
+	if data == "" {
+		return
+	}
+	if err := json.Unmarshal([]byte(data), &cache); err != nil {
+		return HTTPCache{}
+	}
+	return
+}
+
+// String returns the HTTPCache in the format stored in the database.
+func (c *HTTPCache) String() string {
+	if c.URL == "" {
+		return ""
+	}
+	data, _ := json.Marshal(c)
+	return string(data)
+}
+
+// SetConditionalHeaders will add the validators of the cache to req
+// if it's a request of the URL that was cached.
+func (c *HTTPCache) SetConditionalHeaders(req *http.Request) {
+	if c.URL == "" || c.URL != req.URL.String() {
+		return
+	}
+
+	if c.ETag != "" {
+		req.Header.Set("If-None-Match", c.ETag)
+	}
+	if c.LastModified != "" {
+		req.Header.Set("If-Modified-Since", c.LastModified)
+	}
+}
+
+// Response returns the body to use for the response of req, and the HTTPCache for it.
+// A 304 (Not Modified) reuses the cached body, and only successful responses
+// with an ETag or Last-Modified replace the cache (any other response keeps the previous one).
+func (c *HTTPCache) Response(req *http.Request, resp *http.Response, body []byte) (useBody []byte, cache HTTPCache, notModified bool) {
+	url := req.URL.String()
+	if resp.StatusCode == http.StatusNotModified && c.URL == url {
+		return []byte(c.Body), *c, true
+	}
+
+	eTag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (eTag == "" && lastModified == "") {
+		return body, *c, false
+	}
+	return body, HTTPCache{
+			URL:          url,
+			ETag:         eTag,
+			LastModified: lastModified,
+			Body:         string(body)},
+		false
+}
+
+// sameValidators returns whether `other` is a cache of the same URL with the same ETag/Last-Modified.
+func (c *HTTPCache) sameValidators(other *HTTPCache) bool {
+	return c.URL == other.URL &&
+		c.ETag == other.ETag &&
+		c.LastModified == other.LastModified
+}
+
+// LatestVersionCache returns the HTTPCache of the LatestVersion lookup.
+func (s *Status) LatestVersionCache() HTTPCache {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.latestVersionCache
+}
+
+// SetLatestVersionCache will set the HTTPCache of the LatestVersion lookup.
+func (s *Status) SetLatestVersionCache(cache HTTPCache, writeToDB bool) {
+	s.setCache(&s.latestVersionCache, "latest_version_cache", cache, writeToDB)
+}
+
+// DeployedVersionCache returns the HTTPCache of the DeployedVersion lookup.
+func (s *Status) DeployedVersionCache() HTTPCache {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.deployedVersionCache
+}
+
+// SetDeployedVersionCache will set the HTTPCache of the DeployedVersion lookup.
+func (s *Status) SetDeployedVersionCache(cache HTTPCache, writeToDB bool) {
+	s.setCache(&s.deployedVersionCache, "deployed_version_cache", cache, writeToDB)
+}
+
+// setCache will set the HTTPCache at `field` to `cache`,
+// writing it to the `column` of the database if its validators changed.
+func (s *Status) setCache(field *HTTPCache, column string, cache HTTPCache, writeToDB bool) {
+	s.mutex.Lock()
+	changed := !field.sameValidators(&cache)
+	*field = cache
+	s.mutex.Unlock()
+
+	if writeToDB && changed {
+		s.SendDatabase(&dbtype.Message{
+			ServiceID: *s.ServiceID,
+			Cells: []dbtype.Cell{
+				{Column: column, Value: cache.String()}}})
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package svcstatus
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHTTPCache_SetConditionalHeaders(t *testing.T) {
+	// GIVEN a HTTPCache and a request
+	tests := map[string]struct {
+		cache             HTTPCache
+		url               string
+		wantNoneMatch     string
+		wantModifiedSince string
+	}{
+		"empty cache": {
+			url: "https://example.com"},
+		"etag": {
+			cache: HTTPCache{URL: "https://example.com", ETag: `"abc"`},
+			url:   "https://example.com", wantNoneMatch: `"abc"`},
+		"last-modified": {
+			cache: HTTPCache{URL: "https://example.com", LastModified: "Wed, 17 May 2023 10:00:00 GMT"},
+			url:   "https://example.com", wantModifiedSince: "Wed, 17 May 2023 10:00:00 GMT"},
+		"both": {
+			cache: HTTPCache{URL: "https://example.com", ETag: `W/"abc"`, LastModified: "Wed, 17 May 2023 10:00:00 GMT"},
+			url:   "https://example.com", wantNoneMatch: `W/"abc"`, wantModifiedSince: "Wed, 17 May 2023 10:00:00 GMT"},
+		"cache of a different URL": {
+			cache: HTTPCache{URL: "https://example.com/other", ETag: `"abc"`},
+			url:   "https://example.com"},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+
+			// WHEN SetConditionalHeaders is called
+			tc.cache.SetConditionalHeaders(req)
+
+			// THEN the validators are only sent for the URL they came from
+			if got := req.Header.Get("If-None-Match"); got != tc.wantNoneMatch {
+				t.Errorf("If-None-Match - want: %q\ngot:  %q",
+					tc.wantNoneMatch, got)
+			}
+			if got := req.Header.Get("If-Modified-Since"); got != tc.wantModifiedSince {
+				t.Errorf("If-Modified-Since - want: %q\ngot:  %q",
+					tc.wantModifiedSince, got)
+			}
+		})
+	}
+}
+
+func TestHTTPCache_Response(t *testing.T) {
+	// GIVEN a HTTPCache and a response
+	url := "https://example.com"
+	cached := HTTPCache{URL: url, ETag: `"old"`, Body: "cached"}
+	tests := map[string]struct {
+		cache           HTTPCache
+		statusCode      int
+		headers         map[string]string
+		body            string
+		wantBody        string
+		wantCache       HTTPCache
+		wantNotModified bool
+	}{
+		"304 uses the cached body": {
+			cache:           cached,
+			statusCode:      http.StatusNotModified,
+			wantBody:        "cached",
+			wantCache:       cached,
+			wantNotModified: true},
+		"304 of a different URL keeps the cache": {
+			cache:      HTTPCache{URL: url + "/other", ETag: `"old"`, Body: "cached"},
+			statusCode: http.StatusNotModified,
+			wantBody:   "",
+			wantCache:  HTTPCache{URL: url + "/other", ETag: `"old"`, Body: "cached"}},
+		"200 with an ETag is cached": {
+			cache:      cached,
+			statusCode: http.StatusOK,
+			headers:    map[string]string{"ETag": `"new"`},
+			body:       "new",
+			wantBody:   "new",
+			wantCache:  HTTPCache{URL: url, ETag: `"new"`, Body: "new"}},
+		"200 with a Last-Modified is cached": {
+			statusCode: http.StatusOK,
+			headers:    map[string]string{"Last-Modified": "Wed, 17 May 2023 10:00:00 GMT"},
+			body:       "new",
+			wantBody:   "new",
+			wantCache:  HTTPCache{URL: url, LastModified: "Wed, 17 May 2023 10:00:00 GMT", Body: "new"}},
+		"200 without validators keeps the cache": {
+			cache:      cached,
+			statusCode: http.StatusOK,
+			body:       "new",
+			wantBody:   "new",
+			wantCache:  cached},
+		"200 without validators or a cache": {
+			statusCode: http.StatusOK,
+			body:       "new",
+			wantBody:   "new"},
+		"error isn't cached and keeps the cache": {
+			cache:      cached,
+			statusCode: http.StatusInternalServerError,
+			headers:    map[string]string{"ETag": `"new"`},
+			body:       "error",
+			wantBody:   "error",
+			wantCache:  cached},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			req, _ := http.NewRequest(http.MethodGet, url, nil)
+			resp := &http.Response{StatusCode: tc.statusCode, Header: http.Header{}}
+			for key, value := range tc.headers {
+				resp.Header.Set(key, value)
+			}
+
+			// WHEN Response is called
+			body, cache, notModified := tc.cache.Response(req, resp, []byte(tc.body))
+
+			// THEN the body and cache to use are returned
+			if string(body) != tc.wantBody {
+				t.Errorf("body - want: %q\ngot:  %q",
+					tc.wantBody, string(body))
+			}
+			if cache != tc.wantCache {
+				t.Errorf("cache - want: %+v\ngot:  %+v",
+					tc.wantCache, cache)
+			}
+			if notModified != tc.wantNotModified {
+				t.Errorf("notModified - want: %t\ngot:  %t",
+					tc.wantNotModified, notModified)
+			}
+		})
+	}
+}
+
+func TestParseHTTPCache(t *testing.T) {
+	// GIVEN a HTTPCache from the database
+	valid := HTTPCache{URL: "https://example.com", ETag: `"abc"`, Body: "body\n"}
+	tests := map[string]struct {
+		data string
+		want HTTPCache
+	}{
+		"empty": {
+			data: ""},
+		"invalid": {
+			data: "{"},
+		"valid": {
+			data: valid.String(),
+			want: valid},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN ParseHTTPCache is called
+			got := ParseHTTPCache(tc.data)
+
+			// THEN the HTTPCache is parsed
+			if got != tc.want {
+				t.Errorf("want: %+v\ngot:  %+v",
+					tc.want, got)
+			}
+		})
+	}
+}
+
+func TestStatus_SetLatestVersionCache(t *testing.T) {
+	// GIVEN a Status
+	status := testStatus()
+	cache := HTTPCache{URL: "https://example.com", ETag: `"abc"`, Body: "body"}
+
+	// WHEN SetLatestVersionCache is called with a new cache
+	status.SetLatestVersionCache(cache, true)
+
+	// THEN it's set and written to the database
+	if got := status.LatestVersionCache(); got != cache {
+		t.Errorf("want: %+v\ngot:  %+v",
+			cache, got)
+	}
+	if got := len(*status.DatabaseChannel); got != 1 {
+		t.Fatalf("want 1 database message, got %d",
+			got)
+	}
+	msg := <-*status.DatabaseChannel
+	if len(msg.Cells) != 1 || msg.Cells[0].Column != "latest_version_cache" ||
+		ParseHTTPCache(msg.Cells[0].Value) != cache {
+		t.Errorf("unexpected database message: %+v",
+			msg)
+	}
+
+	// WHEN it's set again with the same cache
+	status.SetLatestVersionCache(cache, true)
+
+	// THEN the database isn't written to
+	if got := len(*status.DatabaseChannel); got != 0 {
+		t.Errorf("want no database message for an unchanged cache, got %d",
+			got)
+	}
+
+	// WHEN it's set with the same validators and another body
+	sameValidators := cache
+	sameValidators.Body = "other body"
+	status.SetLatestVersionCache(sameValidators, true)
+
+	// THEN it's set
+	if got := status.LatestVersionCache(); got != sameValidators {
+		t.Errorf("want: %+v\ngot:  %+v",
+			sameValidators, got)
+	}
+	// AND the database isn't written to
+	if got := len(*status.DatabaseChannel); got != 0 {
+		t.Errorf("want no database message for unchanged validators, got %d",
+			got)
+	}
+
+	// WHEN it's set with a new ETag
+	newETag := cache
+	newETag.ETag = `"def"`
+	status.SetLatestVersionCache(newETag, true)
+
+	// THEN the database is written to
+	if got := len(*status.DatabaseChannel); got != 1 {
+		t.Errorf("want 1 database message for changed validators, got %d",
+			got)
+	}
+}
+
+func TestStatus_SetDeployedVersionCache(t *testing.T) {
+	// GIVEN a Status
+	status := testStatus()
+	cache := HTTPCache{URL: "https://example.com", LastModified: "Wed, 17 May 2023 10:00:00 GMT", Body: "body"}
+
+	// WHEN SetDeployedVersionCache is called without writing to the database
+	status.SetDeployedVersionCache(cache, false)
+
+	// THEN it's set
+	if got := status.DeployedVersionCache(); got != cache {
+		t.Errorf("want: %+v\ngot:  %+v",
+			cache, got)
+	}
+	// AND the database isn't written to
+	if got := len(*status.DatabaseChannel); got != 0 {
+		t.Errorf("want no database message, got %d",
+			got)
+	}
+	// AND the LatestVersion cache is untouched
+	if got := status.LatestVersionCache(); got != (HTTPCache{}) {
+		t.Errorf("want an empty LatestVersion cache, got %+v",
+			got)
+	}
+}
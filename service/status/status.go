@@ -16,6 +16,7 @@ package svcstatus
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"strings"
 	"sync"
@@ -23,6 +24,7 @@ import (
 
 	dbtype "github.com/release-argus/Argus/db/types"
 	"github.com/release-argus/Argus/util"
+	api_type "github.com/release-argus/Argus/web/api/types"
 )
 
 // statusBase is the base struct for the Status struct.
@@ -58,17 +60,28 @@ type Status struct {
 	ServiceID *string `yaml:"-" json:"-"` // ID of the Service
 	WebURL    *string `yaml:"-" json:"-"` // Web URL of the Service
 
-	approvedVersion          string       // The version that's been approved
-	deployedVersion          string       // Track the deployed version of the service from the last successful WebHook.
-	deployedVersionTimestamp string       // UTC timestamp of DeployedVersion being changed.
-	latestVersion            string       // Latest version found from query().
-	latestVersionTimestamp   string       // UTC timestamp of LatestVersion being changed.
-	lastQueried              string       // UTC timestamp that version was last queried/checked.
-	regexMissesContent       uint         // Counter for the number of regex misses on URL content.
-	regexMissesVersion       uint         // Counter for the number of regex misses on version.
-	Fails                    Fails        // Track the Notify/WebHook fails
-	deleting                 bool         // Flag to indicate the service is being deleted
-	mutex                    sync.RWMutex // Lock for the Status
+	approvedVersion          string             // The version that's been approved
+	deployedVersion          string             // Track the deployed version of the service from the last successful WebHook.
+	deployedVersionTimestamp string             // UTC timestamp of DeployedVersion being changed.
+	latestVersion            string             // Latest version found from query().
+	latestVersionTimestamp   string             // UTC timestamp of LatestVersion being changed.
+	latestVersionAsset       util.AssetInfo     // Asset of LatestVersion that satisfied the require.assets.
+	latestVersionRelease     util.ReleaseInfo   // Release (name/notes/...) of LatestVersion.
+	lastQueried              string             // UTC timestamp that version was last queried/checked.
+	nextQuery                string             // UTC timestamp that version will next be queried/checked.
+	pendingVersion           string             // Version found that's waiting for require.min_age before becoming LatestVersion.
+	pendingVersionTimestamp  string             // UTC timestamp that PendingVersion was first seen (or published).
+	regexMissesContent       uint               // Counter for the number of regex misses on URL content.
+	regexMissesVersion       uint               // Counter for the number of regex misses on version.
+	consecutiveFailures      uint               // Counter for the number of latest version queries that have failed in a row.
+	lastError                string             // Error of the last failed latest version query.
+	latestVersionCache       HTTPCache          // Last response of the LatestVersion URL, for conditional requests.
+	deployedVersionCache     HTTPCache          // Last response of the DeployedVersion URL, for conditional requests.
+	Fails                    Fails              // Track the Notify/WebHook fails
+	deleting                 bool               // Flag to indicate the service is being deleted
+	ctx                      context.Context    // Context of the Service, cancelled on deletion.
+	cancel                   context.CancelFunc // Cancel the ctx
+	mutex                    sync.RWMutex       // Lock for the Status
 }
 
 // New Status struct.
@@ -107,8 +120,13 @@ func (s *Status) String() string {
 		{Name: "latest_version", Value: s.latestVersion},
 		{Name: "latest_version_timestamp", Value: s.latestVersionTimestamp},
 		{Name: "last_queried", Value: s.lastQueried},
+		{Name: "next_query", Value: s.nextQuery},
+		{Name: "pending_version", Value: s.pendingVersion},
+		{Name: "pending_version_timestamp", Value: s.pendingVersionTimestamp},
 		{Name: "regex_misses_content", Value: s.regexMissesContent},
 		{Name: "regex_misses_version", Value: s.regexMissesVersion},
+		{Name: "consecutive_failures", Value: s.consecutiveFailures},
+		{Name: "last_error", Value: s.lastError},
 		{Name: "fails", Value: &s.Fails},
 	}
 	s.mutex.RUnlock()
@@ -175,6 +193,48 @@ func (s *Status) SetLastQueried(t string) {
 	}
 }
 
+// NextQuery time of the LatestVersion.
+func (s *Status) NextQuery() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.nextQuery
+}
+
+// SetNextQuery will update NextQuery to `t`.
+func (s *Status) SetNextQuery(t time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.nextQuery = t.UTC().Format(time.RFC3339)
+}
+
+// ConsecutiveFailures returns the number of latest version queries that have failed in a row.
+func (s *Status) ConsecutiveFailures() uint {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.consecutiveFailures
+}
+
+// LastError returns the error of the last failed latest version query.
+func (s *Status) LastError() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.lastError
+}
+
+// SetQueryResult will count the latest version query as a failure if `err` is non-nil,
+// otherwise it will reset ConsecutiveFailures and LastError.
+func (s *Status) SetQueryResult(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err == nil {
+		s.consecutiveFailures = 0
+		s.lastError = ""
+		return
+	}
+	s.consecutiveFailures++
+	s.lastError = err.Error()
+}
+
 // ApprovedVersion returns the ApprovedVersion.
 func (s *Status) ApprovedVersion() string {
 	s.mutex.RLock()
@@ -260,6 +320,11 @@ func (s *Status) LatestVersion() string {
 func (s *Status) SetLatestVersion(version string, writeToDB bool) {
 	s.mutex.Lock()
 	{
+		// The asset/release was of the previous version
+		if version != s.latestVersion {
+			s.latestVersionAsset = util.AssetInfo{}
+			s.latestVersionRelease = util.ReleaseInfo{}
+		}
 		s.latestVersion = version
 		s.latestVersionTimestamp = s.lastQueried
 	}
@@ -295,6 +360,92 @@ func (s *Status) SetLatestVersionTimestamp(timestamp string) {
 	s.mutex.Unlock()
 }
 
+// LatestVersionAsset returns the asset of the LatestVersion that satisfied the require.assets.
+func (s *Status) LatestVersionAsset() util.AssetInfo {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.latestVersionAsset
+}
+
+// SetLatestVersionAsset will set LatestVersionAsset to `asset`.
+func (s *Status) SetLatestVersionAsset(asset util.AssetInfo) {
+	s.mutex.Lock()
+	{
+		s.latestVersionAsset = asset
+	}
+	s.mutex.Unlock()
+}
+
+// LatestVersionRelease returns the release (name/notes/...) of the LatestVersion.
+func (s *Status) LatestVersionRelease() util.ReleaseInfo {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.latestVersionRelease
+}
+
+// SetLatestVersionRelease will set LatestVersionRelease to `release`.
+func (s *Status) SetLatestVersionRelease(release util.ReleaseInfo) {
+	s.mutex.Lock()
+	{
+		s.latestVersionRelease = release
+	}
+	s.mutex.Unlock()
+}
+
+// LatestVersionReleaseSummary returns the release of the LatestVersion for the API,
+// or nil if there's no release metadata.
+func (s *Status) LatestVersionReleaseSummary() *api_type.StatusRelease {
+	release := s.LatestVersionRelease()
+	if release == (util.ReleaseInfo{}) {
+		return nil
+	}
+
+	return &api_type.StatusRelease{
+		Name:        release.Name,
+		Notes:       release.Notes,
+		URL:         release.URL,
+		Author:      release.Author,
+		PublishedAt: release.PublishedAt}
+}
+
+// PendingVersion returns the version that's waiting for require.min_age before becoming LatestVersion.
+func (s *Status) PendingVersion() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.pendingVersion
+}
+
+// PendingVersionTimestamp returns the timestamp that the PendingVersion was first seen (or published).
+func (s *Status) PendingVersionTimestamp() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.pendingVersionTimestamp
+}
+
+// SetPendingVersion will set PendingVersion to `version` and PendingVersionTimestamp to `timestamp`
+// (clearing both if `version` is empty), writing them to the database if they changed.
+func (s *Status) SetPendingVersion(version string, timestamp string, writeToDB bool) {
+	if version == "" {
+		timestamp = ""
+	}
+
+	s.mutex.Lock()
+	changed := s.pendingVersion != version || s.pendingVersionTimestamp != timestamp
+	{
+		s.pendingVersion = version
+		s.pendingVersionTimestamp = timestamp
+	}
+	s.mutex.Unlock()
+
+	if writeToDB && changed {
+		s.SendDatabase(&dbtype.Message{
+			ServiceID: *s.ServiceID,
+			Cells: []dbtype.Cell{
+				{Column: "pending_version", Value: version},
+				{Column: "pending_version_timestamp", Value: timestamp}}})
+	}
+}
+
 // RegexMissContent will increment the count of RegEx misses on content.
 func (s *Status) RegexMissContent() {
 	s.mutex.Lock()
@@ -342,10 +493,27 @@ func (s *Status) SetDeleting() {
 	s.mutex.Lock()
 	{
 		s.deleting = true
+		if s.cancel != nil {
+			s.cancel()
+		}
 	}
 	s.mutex.Unlock()
 }
 
+// Context of the Service, which is cancelled when the Service is being deleted.
+func (s *Status) Context() context.Context {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.ctx == nil {
+		s.ctx, s.cancel = context.WithCancel(context.Background())
+		if s.deleting {
+			s.cancel()
+		}
+	}
+	return s.ctx
+}
+
 // Deleting returns true if the Service is being deleted.
 func (s *Status) Deleting() bool {
 	s.mutex.RLock()
@@ -17,12 +17,14 @@
 package svcstatus
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
 	"time"
 
 	dbtype "github.com/release-argus/Argus/db/types"
+	"github.com/release-argus/Argus/util"
 )
 
 func TestStatus_Init(t *testing.T) {
@@ -179,6 +181,94 @@ func TestStatus_SetLastQueried(t *testing.T) {
 	}
 }
 
+func TestStatus_SetNextQuery(t *testing.T) {
+	// GIVEN we have a Status
+	var status Status
+	nextQuery := time.Date(2023, time.May, 17, 12, 15, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+
+	// WHEN we SetNextQuery
+	status.SetNextQuery(nextQuery)
+
+	// THEN NextQuery will be that time in UTC
+	want := "2023-05-17T10:15:00Z"
+	if got := status.NextQuery(); got != want {
+		t.Errorf("want: %q\ngot:  %q",
+			want, got)
+	}
+}
+
+func TestStatus_Context(t *testing.T) {
+	// GIVEN a Status and its Context
+	tests := map[string]struct {
+		deletingFirst bool
+	}{
+		"deleted after the Context is made":  {deletingFirst: false},
+		"deleted before the Context is made": {deletingFirst: true},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var status Status
+			if tc.deletingFirst {
+				status.SetDeleting()
+			}
+			ctx := status.Context()
+			if !tc.deletingFirst && ctx.Err() != nil {
+				t.Fatalf("Context was cancelled before SetDeleting: %v",
+					ctx.Err())
+			}
+
+			// WHEN SetDeleting is called
+			status.SetDeleting()
+
+			// THEN the Context is cancelled
+			if ctx.Err() == nil {
+				t.Error("Context wasn't cancelled by SetDeleting")
+			}
+			// AND the same Context is returned each time
+			if status.Context() != ctx {
+				t.Error("Context changed")
+			}
+		})
+	}
+}
+
+func TestStatus_SetQueryResult(t *testing.T) {
+	// GIVEN a Status
+	var status Status
+
+	// WHEN SetQueryResult is called with errors
+	status.SetQueryResult(errors.New("first"))
+	status.SetQueryResult(errors.New("second"))
+
+	// THEN the failures are counted
+	if got := status.ConsecutiveFailures(); got != 2 {
+		t.Errorf("want 2 ConsecutiveFailures, not %d",
+			got)
+	}
+	// AND the last error is kept
+	if got := status.LastError(); got != "second" {
+		t.Errorf("want LastError %q, not %q",
+			"second", got)
+	}
+
+	// WHEN SetQueryResult is called without an error
+	status.SetQueryResult(nil)
+
+	// THEN both are reset
+	if got := status.ConsecutiveFailures(); got != 0 {
+		t.Errorf("want 0 ConsecutiveFailures, not %d",
+			got)
+	}
+	if got := status.LastError(); got != "" {
+		t.Errorf("want LastError %q, not %q",
+			"", got)
+	}
+}
+
 func TestStatus_ApprovedVersion(t *testing.T) {
 	// GIVEN a Status
 	approvedVersion := "0.0.2"
@@ -354,6 +444,116 @@ func TestStatus_LatestVersion(t *testing.T) {
 	}
 }
 
+func TestStatus_PendingVersion(t *testing.T) {
+	// GIVEN a Status
+	status := testStatus()
+
+	// WHEN SetPendingVersion is called on it
+	status.SetPendingVersion("1.2.3", "2022-01-01T01:01:01Z", true)
+
+	// THEN PendingVersion and PendingVersionTimestamp are set
+	if got := status.PendingVersion(); got != "1.2.3" {
+		t.Errorf("want PendingVersion=%q, got %q",
+			"1.2.3", got)
+	}
+	if got := status.PendingVersionTimestamp(); got != "2022-01-01T01:01:01Z" {
+		t.Errorf("want PendingVersionTimestamp=%q, got %q",
+			"2022-01-01T01:01:01Z", got)
+	}
+	// AND they're written to the database
+	if got := len(*status.DatabaseChannel); got != 1 {
+		t.Fatalf("want 1 database message, got %d",
+			got)
+	}
+	msg := <-*status.DatabaseChannel
+	if len(msg.Cells) != 2 ||
+		msg.Cells[0].Column != "pending_version" || msg.Cells[0].Value != "1.2.3" ||
+		msg.Cells[1].Column != "pending_version_timestamp" || msg.Cells[1].Value != "2022-01-01T01:01:01Z" {
+		t.Errorf("unexpected database message: %+v",
+			msg)
+	}
+
+	// WHEN it's set again with the same version
+	status.SetPendingVersion("1.2.3", "2022-01-01T01:01:01Z", true)
+
+	// THEN the database isn't written to
+	if got := len(*status.DatabaseChannel); got != 0 {
+		t.Errorf("want no database message for an unchanged pending version, got %d",
+			got)
+	}
+
+	// WHEN SetPendingVersion is called with an empty version
+	status.SetPendingVersion("", "2022-01-01T01:01:01Z", true)
+
+	// THEN both are cleared
+	if got := status.PendingVersion(); got != "" {
+		t.Errorf("want PendingVersion cleared, got %q",
+			got)
+	}
+	if got := status.PendingVersionTimestamp(); got != "" {
+		t.Errorf("want PendingVersionTimestamp cleared, got %q",
+			got)
+	}
+	// AND the clear is written to the database
+	if got := len(*status.DatabaseChannel); got != 1 {
+		t.Errorf("want 1 database message, got %d",
+			got)
+	}
+}
+
+func TestStatus_LatestVersionRelease(t *testing.T) {
+	// GIVEN a Status with a LatestVersion
+	status := Status{}
+	status.Init(
+		0, 0, 0,
+		stringPtr("test"),
+		stringPtr("https://example.com"))
+	status.SetLatestVersion("1.2.3", false)
+	release := util.ReleaseInfo{
+		Name:   "v1.2.3",
+		Notes:  "- Fixed a bug",
+		URL:    "https://example.com/releases/1.2.3",
+		Author: "someone"}
+
+	// WHEN SetLatestVersionRelease is called on it
+	status.SetLatestVersionRelease(release)
+
+	// THEN LatestVersionRelease is set
+	if got := status.LatestVersionRelease(); got != release {
+		t.Errorf("want LatestVersionRelease=%v, got %v",
+			release, got)
+	}
+	// AND it's summarised for the API
+	summary := status.LatestVersionReleaseSummary()
+	if summary == nil || summary.Name != release.Name || summary.Notes != release.Notes ||
+		summary.URL != release.URL || summary.Author != release.Author {
+		t.Errorf("want LatestVersionReleaseSummary of %v, got %v",
+			release, summary)
+	}
+
+	// WHEN the LatestVersion is set to the same version
+	status.SetLatestVersion("1.2.3", false)
+
+	// THEN the release is kept
+	if got := status.LatestVersionRelease(); got != release {
+		t.Errorf("want LatestVersionRelease=%v kept, got %v",
+			release, got)
+	}
+
+	// WHEN the LatestVersion changes
+	status.SetLatestVersion("1.2.4", false)
+
+	// THEN the release of the previous version is cleared
+	if got := status.LatestVersionRelease(); got != (util.ReleaseInfo{}) {
+		t.Errorf("want LatestVersionRelease cleared, got %v",
+			got)
+	}
+	if got := status.LatestVersionReleaseSummary(); got != nil {
+		t.Errorf("want nil LatestVersionReleaseSummary, got %v",
+			got)
+	}
+}
+
 func TestStatus_RegexMissesContent(t *testing.T) {
 	// GIVEN a Status
 	status := Status{}
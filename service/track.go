@@ -15,10 +15,12 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/release-argus/Argus/service/scheduler"
 	"github.com/release-argus/Argus/util"
 )
 
@@ -26,6 +28,7 @@ import (
 func (s *Slice) Track(ordering *[]string, orderMutex *sync.RWMutex) {
 	orderMutex.RLock()
 	defer orderMutex.RUnlock()
+	start := time.Now()
 	for _, key := range *ordering {
 		// Skip inactive Services (and services that were deleted on startup)
 		if !(*s)[key].Options.GetActive() || (*s)[key] == nil {
@@ -33,24 +36,34 @@ func (s *Slice) Track(ordering *[]string, orderMutex *sync.RWMutex) {
 		}
 		(*s)[key].Options.Active = nil
 
+		every := "every " + (*s)[key].Options.GetInterval()
+		if schedule := (*s)[key].Options.GetSchedule(); schedule != "" {
+			every = fmt.Sprintf("on the schedule %q", schedule)
+		}
 		jLog.Verbose(
-			fmt.Sprintf("Tracking %s at %s every %s",
-				(*s)[key].ID, (*s)[key].LatestVersion.ServiceURL(true), (*s)[key].Options.GetInterval()),
+			fmt.Sprintf("Tracking %s at %s %s",
+				(*s)[key].ID, (*s)[key].LatestVersion.ServiceURL(true), every),
 			util.LogFrom{Primary: (*s)[key].ID},
 			true)
 
-		// Track this Service in a infinite loop goroutine.
-		go (*s)[key].Track()
+		// Track this Service on the Scheduler.
+		(*s)[key].track(start)
 
 		// Space out the tracking of each Service.
-		time.Sleep(time.Second / 2)
+		start = start.Add(time.Second / 2)
 	}
 }
 
 // Track the Service and send Notify messages (Service.Notify) as
 // well as WebHooks (Service.WebHook) when a new release is spotted.
-// It sleeps for Service.Interval between each check.
+// The queries are run on the Scheduler at the next query of the Service.Options (Interval/Schedule + Jitter)
+// until the Service is deleted.
 func (s *Service) Track() {
+	s.track(time.Now())
+}
+
+// track the Service from `start`.
+func (s *Service) track(start time.Time) {
 	// Skip inactive Services
 	if !s.Options.GetActive() {
 		s.DeleteMetrics()
@@ -58,36 +71,59 @@ func (s *Service) Track() {
 	}
 	s.ResetMetrics()
 
-	// If this Service was last queried less than interval ago, wait until interval has elapsed.
-	lastQueriedAt, _ := time.Parse(time.RFC3339, s.Status.LastQueried())
-	if time.Since(lastQueriedAt) < s.Options.GetIntervalDuration() {
-		time.Sleep(s.Options.GetIntervalDuration() - time.Since(lastQueriedAt))
+	// If this Service has been queried before, wait until the query that'd follow that is due.
+	firstQuery := start
+	if lastQueriedAt, err := time.Parse(time.RFC3339, s.Status.LastQueried()); err == nil {
+		firstQuery = s.Options.NextQuery(lastQueriedAt)
+		// Missed queries on a Schedule aren't caught up on, wait for the next one.
+		if s.Options.GetSchedule() != "" && firstQuery.Before(start) {
+			firstQuery = s.Options.NextQuery(start)
+		}
+		s.Status.SetNextQuery(firstQuery)
 	}
 
-	// Track the deployed version in an infinite loop goroutine.
-	go func() {
-		time.Sleep(2 * time.Second) // Give LatestVersion some time to query first.
+	scheduler.Default.Add(s.Status.Context(), s.ID, firstQuery, s.query)
 
-		go s.DeployedVersionLookup.Track()
-	}()
+	// Track the deployed version.
+	s.DeployedVersionLookup.Track()
+}
+
+// query the latest version of the Service, and return the time of the next query.
+func (s *Service) query(ctx context.Context) time.Time {
+	// If we're deleting this Service, stop tracking it.
+	if ctx.Err() != nil {
+		return time.Time{}
+	}
 
-	// Track forever.
+	// Defer the query whilst the API rate limit is too low for this Service.
 	logFrom := util.LogFrom{Primary: s.ID}
-	for {
-		// If we're deleting this Service, stop tracking it.
-		if s.Status.Deleting() {
-			return
-		}
+	if deferUntil := s.LatestVersion.RateLimitDeferral(); !deferUntil.IsZero() {
+		jLog.Verbose(
+			fmt.Sprintf("Rate limit low, deferring the query until %s",
+				deferUntil.Format(time.RFC3339)),
+			logFrom, true)
+		s.Status.SetNextQuery(deferUntil)
+		return deferUntil
+	}
 
-		// If new release found by this query.
-		newVersion, _ := s.LatestVersion.Query(true, &logFrom)
+	// Plan the query after this one.
+	start := time.Now()
+	nextQuery := s.Options.NextQuery(start)
+	s.Status.SetNextQuery(nextQuery)
 
-		// If a new version was found
-		if newVersion {
-			go s.HandleUpdateActions(true)
-		}
+	// If new release found by this query.
+	newVersion, err := s.LatestVersion.Query(true, &logFrom)
 
-		// Sleep interval between checks.
-		time.Sleep(s.Options.GetIntervalDuration())
+	// Back off after consecutive failures.
+	if err != nil {
+		nextQuery = s.Options.NextQueryAfterFailures(start, nextQuery, s.Status.ConsecutiveFailures())
+		s.Status.SetNextQuery(nextQuery)
 	}
+
+	// If a new version was found
+	if newVersion {
+		go s.HandleUpdateActions(true)
+	}
+
+	return nextQuery
 }
@@ -77,6 +77,10 @@ func (s *Service) Summary() *apitype.ServiceSummary {
 	hasDeployedVersionLookup := s.DeployedVersionLookup != nil
 	commands := len(s.Command)
 	webhooks := len(s.WebHook)
+	var versionConstraint *string
+	if constraint := s.Options.GetVersionConstraint(); constraint != "" {
+		versionConstraint = &constraint
+	}
 	return &apitype.ServiceSummary{
 		ID:                       s.ID,
 		Active:                   s.Options.Active,
@@ -85,6 +89,7 @@ func (s *Service) Summary() *apitype.ServiceSummary {
 		Icon:                     &icon,
 		IconLinkTo:               &s.Dashboard.IconLinkTo,
 		HasDeployedVersionLookup: &hasDeployedVersionLookup,
+		VersionConstraint:        versionConstraint,
 		Command:                  &commands,
 		WebHook:                  &webhooks,
 		Status: &apitype.Status{
@@ -93,5 +98,11 @@ func (s *Service) Summary() *apitype.ServiceSummary {
 			DeployedVersionTimestamp: s.Status.DeployedVersionTimestamp(),
 			LatestVersion:            s.Status.LatestVersion(),
 			LatestVersionTimestamp:   s.Status.LatestVersionTimestamp(),
-			LastQueried:              s.Status.LastQueried()}}
+			LastQueried:              s.Status.LastQueried(),
+			NextQuery:                s.Status.NextQuery(),
+			PendingVersion:           s.Status.PendingVersion(),
+			PendingVersionTimestamp:  s.Status.PendingVersionTimestamp(),
+			ConsecutiveFailures:      s.Status.ConsecutiveFailures(),
+			LastError:                s.Status.LastError(),
+			LatestVersionRelease:     s.Status.LatestVersionReleaseSummary()}}
 }
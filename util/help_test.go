@@ -26,5 +26,15 @@ func testServiceInfo() ServiceInfo {
 		URL:           "example.com",
 		WebURL:        "other.com",
 		LatestVersion: "NEW",
+		Asset: AssetInfo{
+			Name: "argus-NEW.linux-amd64",
+			URL:  "https://example.com/argus-NEW.linux-amd64",
+			Size: 1024},
+		Release: ReleaseInfo{
+			Name:        "Release NEW",
+			Notes:       "- Fixed a bug",
+			URL:         "https://example.com/releases/NEW",
+			Author:      "someone",
+			PublishedAt: "2023-01-01T00:00:00Z"},
 	}
 }
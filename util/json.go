@@ -0,0 +1,197 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathSegment is a key/index/wildcard of a JSON path.
+type jsonPathSegment struct {
+	text     string // Text of the segment in the path
+	key      string // Key of an object (or index of an array if numeric)
+	index    *int   // Index of an array
+	wildcard bool   // Every element of an array (or value of an object)
+}
+
+// parseJSONPath splits a JSON path into its segments.
+//
+// e.g. "foo.bar[0].baz", "releases[-1].tag", "assets[*].name", "data.*.version"
+func parseJSONPath(path string) (segments []jsonPathSegment, err error) {
+	if path == "" {
+		err = fmt.Errorf("path is empty")
+		return
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		// key[0][*]
+		key, brackets, _ := strings.Cut(part, "[")
+		if key == "" && brackets == "" {
+			err = fmt.Errorf("empty key in %q", path)
+			return
+		}
+		if key == "*" {
+			segments = append(segments, jsonPathSegment{text: key, wildcard: true})
+		} else if key != "" {
+			segments = append(segments, jsonPathSegment{text: key, key: key})
+		}
+		if brackets == "" {
+			continue
+		}
+
+		brackets = "[" + brackets
+		for brackets != "" {
+			var index string
+			var found bool
+			if !strings.HasPrefix(brackets, "[") {
+				err = fmt.Errorf("unexpected %q after ']' in %q", brackets, path)
+				return
+			}
+			index, brackets, found = strings.Cut(brackets[1:], "]")
+			if !found {
+				err = fmt.Errorf("missing ']' in %q", path)
+				return
+			}
+
+			segment := jsonPathSegment{text: "[" + index + "]"}
+			if index == "*" {
+				segment.wildcard = true
+			} else {
+				i, convErr := strconv.Atoi(index)
+				if convErr != nil {
+					err = fmt.Errorf("index %q is not an integer in %q", index, path)
+					return
+				}
+				segment.index = &i
+			}
+			segments = append(segments, segment)
+		}
+	}
+	return
+}
+
+// CheckJSONPath returns an error if `path` is not a valid JSON path.
+func CheckJSONPath(path string) error {
+	_, err := parseJSONPath(path)
+	return err
+}
+
+// GetValuesFromJSON returns the values at `path` in the JSON `data`.
+//
+// The path is made of dotted keys, array indexes (negative indexes count back from the end),
+// and wildcards over arrays/objects, e.g. "foo.bar[0].baz", "releases[-1].tag", "assets[*].name".
+// Strings are returned as is, and numbers/booleans as their JSON text.
+// It errors if the path resolves to a null, an object or an array.
+func GetValuesFromJSON(data []byte, path string) (values []string, err error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return
+	}
+
+	var root interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// Keep numbers as they are in the JSON, e.g. "1.10" rather than "1.1"
+	decoder.UseNumber()
+	if err = decoder.Decode(&root); err != nil {
+		err = fmt.Errorf("failed to unmarshal the JSON: %w", err)
+		return
+	}
+
+	current := []interface{}{root}
+	for _, segment := range segments {
+		var next []interface{}
+		for _, value := range current {
+			next = append(next, segment.resolve(value)...)
+		}
+		if len(next) == 0 {
+			err = fmt.Errorf("%q could not be found. Failed at %q",
+				path, segment.text)
+			return
+		}
+		current = next
+	}
+
+	values = make([]string, len(current))
+	for i := range current {
+		if values[i], err = jsonValueString(current[i]); err != nil {
+			values = nil
+			err = fmt.Errorf("%q %w", path, err)
+			return
+		}
+	}
+	return
+}
+
+// resolve the segment on `value`, returning the values it selects.
+func (s *jsonPathSegment) resolve(value interface{}) (values []interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			for _, key := range SortedKeys(v) {
+				values = append(values, v[key])
+			}
+		} else if s.index == nil {
+			if element, exists := v[s.key]; exists {
+				values = append(values, element)
+			}
+		}
+	case []interface{}:
+		if s.wildcard {
+			return v
+		}
+		index := s.index
+		// Numeric key, e.g. "foo.0"
+		if index == nil {
+			i, err := strconv.Atoi(s.key)
+			if err != nil {
+				return
+			}
+			index = &i
+		}
+		i := *index
+		// Handle negative indices.
+		if i < 0 {
+			i += len(v)
+		}
+		if i >= 0 && i < len(v) {
+			values = append(values, v[i])
+		}
+	}
+	return
+}
+
+// jsonValueString returns the string representation of a scalar JSON value.
+func jsonValueString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case nil:
+		return "", fmt.Errorf("resolved to null")
+	case map[string]interface{}:
+		return "", fmt.Errorf("resolved to an object, not a value")
+	case []interface{}:
+		return "", fmt.Errorf("resolved to an array, not a value")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright [2023] [Argus]
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unit
+
+package util
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCheckJSONPath(t *testing.T) {
+	// GIVEN a JSON path
+	tests := map[string]struct {
+		path     string
+		errRegex string
+	}{
+		"dotted keys": {
+			path:     "foo.bar.baz",
+			errRegex: `^$`},
+		"indexes and wildcards": {
+			path:     "foo[0][-1].bar[*].*",
+			errRegex: `^$`},
+		"leading index": {
+			path:     "[0].version",
+			errRegex: `^$`},
+		"empty": {
+			path:     "",
+			errRegex: `path is empty`},
+		"empty key": {
+			path:     "foo..bar",
+			errRegex: `empty key in "foo..bar"`},
+		"non-integer index": {
+			path:     "foo[bar]",
+			errRegex: `index "bar" is not an integer`},
+		"unclosed bracket": {
+			path:     "foo[0",
+			errRegex: `missing ']'`},
+		"text after bracket": {
+			path:     "foo[0]bar",
+			errRegex: `unexpected "bar" after ']'`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// WHEN CheckJSONPath is called on it
+			err := CheckJSONPath(tc.path)
+
+			// THEN it err's when expected
+			e := ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+		})
+	}
+}
+
+func TestGetValuesFromJSON(t *testing.T) {
+	// GIVEN JSON and a path
+	data := `{
+		"version": "1.2.3",
+		"build": 10,
+		"float": 1.10,
+		"stable": true,
+		"nested": {"a": {"b": "deep"}},
+		"releases": [
+			{"tag": "v1.0.0", "assets": [{"name": "a.tar.gz"}, {"name": "a.zip"}]},
+			{"tag": "v1.1.0", "assets": []},
+			{"tag": "v1.2.0", "assets": [{"name": "c.tar.gz"}]}
+		],
+		"matrix": [[1, 2], [3, 4]],
+		"platforms": {"linux": {"version": "2.0"}, "darwin": {"version": "1.9"}}
+	}`
+	tests := map[string]struct {
+		data     string
+		path     string
+		want     []string
+		errRegex string
+	}{
+		"top-level key": {
+			path:     "version",
+			want:     []string{"1.2.3"},
+			errRegex: `^$`},
+		"number": {
+			path:     "build",
+			want:     []string{"10"},
+			errRegex: `^$`},
+		"number keeps its format": {
+			path:     "float",
+			want:     []string{"1.10"},
+			errRegex: `^$`},
+		"bool": {
+			path:     "stable",
+			want:     []string{"true"},
+			errRegex: `^$`},
+		"dotted keys": {
+			path:     "nested.a.b",
+			want:     []string{"deep"},
+			errRegex: `^$`},
+		"object": {
+			path:     "nested.a",
+			errRegex: `"nested.a" resolved to an object, not a value`},
+		"array": {
+			path:     "matrix[0]",
+			errRegex: `"matrix\[0\]" resolved to an array, not a value`},
+		"null": {
+			data:     `{"version": null}`,
+			path:     "version",
+			errRegex: `"version" resolved to null`},
+		"wildcard over objects": {
+			path:     "releases[*]",
+			errRegex: `"releases\[\*\]" resolved to an object, not a value`},
+		"array index": {
+			path:     "releases[1].tag",
+			want:     []string{"v1.1.0"},
+			errRegex: `^$`},
+		"numeric key as index": {
+			path:     "releases.1.tag",
+			want:     []string{"v1.1.0"},
+			errRegex: `^$`},
+		"negative index": {
+			path:     "releases[-1].tag",
+			want:     []string{"v1.2.0"},
+			errRegex: `^$`},
+		"nested indexes": {
+			path:     "matrix[1][-2]",
+			want:     []string{"3"},
+			errRegex: `^$`},
+		"wildcard over array": {
+			path:     "releases[*].tag",
+			want:     []string{"v1.0.0", "v1.1.0", "v1.2.0"},
+			errRegex: `^$`},
+		"nested wildcards skip missing": {
+			path:     "releases[*].assets[*].name",
+			want:     []string{"a.tar.gz", "a.zip", "c.tar.gz"},
+			errRegex: `^$`},
+		"wildcard over object in key order": {
+			path:     "platforms.*.version",
+			want:     []string{"1.9", "2.0"},
+			errRegex: `^$`},
+		"root array": {
+			data:     `[{"tag":"v1"},{"tag":"v2"}]`,
+			path:     "[0].tag",
+			want:     []string{"v1"},
+			errRegex: `^$`},
+		"unknown key": {
+			path:     "nested.a.c",
+			errRegex: `"nested.a.c" could not be found. Failed at "c"`},
+		"index out of range": {
+			path:     "releases[3].tag",
+			errRegex: `"releases\[3\].tag" could not be found. Failed at "\[3\]"`},
+		"index on object": {
+			path:     "nested[0]",
+			errRegex: `Failed at "\[0\]"`},
+		"invalid path": {
+			path:     "releases[x]",
+			errRegex: `index "x" is not an integer`},
+		"invalid JSON": {
+			data:     `<html></html>`,
+			path:     "version",
+			errRegex: `failed to unmarshal the JSON`},
+	}
+
+	for name, tc := range tests {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.data == "" {
+				tc.data = data
+			}
+
+			// WHEN GetValuesFromJSON is called
+			got, err := GetValuesFromJSON([]byte(tc.data), tc.path)
+
+			// THEN it err's when expected
+			e := ErrorToString(err)
+			re := regexp.MustCompile(tc.errRegex)
+			match := re.MatchString(e)
+			if !match {
+				t.Fatalf("want match for %q\nnot: %q",
+					tc.errRegex, e)
+			}
+			// AND the values are what we expect
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Errorf("want: %q\ngot:  %q",
+					tc.want, got)
+			}
+		})
+	}
+}
@@ -20,4 +20,22 @@ type ServiceInfo struct {
 	URL           string
 	WebURL        string
 	LatestVersion string
+	Asset         AssetInfo   // Asset of the LatestVersion that satisfied the require.assets
+	Release       ReleaseInfo // Release of the LatestVersion
+}
+
+// AssetInfo is an asset of a release.
+type AssetInfo struct {
+	Name string
+	URL  string
+	Size uint64
+}
+
+// ReleaseInfo is the metadata of a release.
+type ReleaseInfo struct {
+	Name        string
+	Notes       string
+	URL         string
+	Author      string
+	PublishedAt string
 }
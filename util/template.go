@@ -51,7 +51,16 @@ func TemplateString(template string, context ServiceInfo) (result string) {
 		"service_id":  context.ID,
 		"service_url": context.URL,
 		"web_url":     context.WebURL,
-		"version":     context.LatestVersion})
+		"version":     context.LatestVersion,
+		"asset_name":  context.Asset.Name,
+		"asset_url":   context.Asset.URL,
+		"asset_size":  context.Asset.Size,
+
+		"release_name":         context.Release.Name,
+		"release_notes":        context.Release.Notes,
+		"release_url":          context.Release.URL,
+		"release_author":       context.Release.Author,
+		"release_published_at": context.Release.PublishedAt})
 	if err != nil {
 		panic(err)
 	}
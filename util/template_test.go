@@ -36,6 +36,12 @@ func TestTemplate_String(t *testing.T) {
 		"valid jinja template": {
 			tmpl: "-{% if 'a' == 'a' %}{{ service_id }}{% endif %}-{{ service_url }}-{{ web_url }}-{{ version }}",
 			want: "-something-example.com-other.com-NEW"},
+		"asset template": {
+			tmpl: "curl -o {{ asset_name }} {{ asset_url }} # {{ asset_size }} bytes",
+			want: "curl -o argus-NEW.linux-amd64 https://example.com/argus-NEW.linux-amd64 # 1024 bytes"},
+		"release template": {
+			tmpl: "{{ release_name }} by {{ release_author }} at {{ release_published_at }} ({{ release_url }})\n{{ release_notes }}",
+			want: "Release NEW by someone at 2023-01-01T00:00:00Z (https://example.com/releases/NEW)\n- Fixed a bug"},
 		"invalid jinja template panic": {
 			tmpl:       "-{% 'a' == 'a' %}{{ service_id }}{% endif %}-{{ service_url }}-{{ web_url }}-{{ version }}",
 			panicRegex: stringPtr("Tag name must be an identifier")},
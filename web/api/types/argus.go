@@ -31,6 +31,7 @@ type ServiceSummary struct {
 	Icon                     *string `json:"icon,omitempty"`                 // Service.Dashboard.Icon / Service.Notify.*.Params.Icon / Service.Notify.*.Defaults.Params.Icon
 	IconLinkTo               *string `json:"icon_link_to,omitempty"`         // URL to redirect Icon clicks to
 	HasDeployedVersionLookup *bool   `json:"has_deployed_version,omitempty"` // Whether this service has a DeployedVersionLookup
+	VersionConstraint        *string `json:"version_constraint,omitempty"`   // Constraint that versions must satisfy
 	Command                  *int    `json:"command,omitempty"`              // Number of Commands to send on a new release
 	WebHook                  *int    `json:"webhook,omitempty"`              // Number of WebHooks to send on a new release
 	Status                   *Status `json:"status,omitempty"`               // Track the Status of this source (version and regex misses)
@@ -77,6 +78,14 @@ func (s *ServiceSummary) RemoveUnchanged(other *ServiceSummary) {
 	if util.EvalNilPtr(other.HasDeployedVersionLookup, false) == util.EvalNilPtr(s.HasDeployedVersionLookup, false) {
 		s.HasDeployedVersionLookup = nil
 	}
+	// VersionConstraint
+	if util.DefaultIfNil(other.VersionConstraint) == util.DefaultIfNil(s.VersionConstraint) {
+		s.VersionConstraint = nil
+	} else if s.VersionConstraint == nil {
+		// Removed
+		removed := ""
+		s.VersionConstraint = &removed
+	}
 
 	// Status
 	statusSameCount := 0
@@ -97,22 +106,47 @@ func (s *ServiceSummary) RemoveUnchanged(other *ServiceSummary) {
 		s.Status.LatestVersionTimestamp = ""
 		statusSameCount++
 	}
+	// Status.NextQuery/ConsecutiveFailures/LastError
+	// (kept together when any changed, so that a reset of the failures isn't taken as unchanged)
+	if other.Status.NextQuery == s.Status.NextQuery &&
+		other.Status.ConsecutiveFailures == s.Status.ConsecutiveFailures &&
+		other.Status.LastError == s.Status.LastError {
+		s.Status.NextQuery = ""
+		s.Status.ConsecutiveFailures = 0
+		s.Status.LastError = ""
+		statusSameCount++
+	}
 	// nil Status if all fields are the same
-	if statusSameCount == 3 {
+	if statusSameCount == 4 {
 		s.Status = nil
 	}
 }
 
 // Status is the Status of a Service.
 type Status struct {
-	ApprovedVersion          string `json:"approved_version,omitempty"`           // The version that's been approved
-	DeployedVersion          string `json:"deployed_version,omitempty"`           // Track the deployed version of the service from the last successful WebHook
-	DeployedVersionTimestamp string `json:"deployed_version_timestamp,omitempty"` // UTC timestamp that the deployed version change was noticed
-	LatestVersion            string `json:"latest_version,omitempty"`             // Latest version found from query()
-	LatestVersionTimestamp   string `json:"latest_version_timestamp,omitempty"`   // UTC timestamp that the latest version change was noticed
-	LastQueried              string `json:"last_queried,omitempty"`               // UTC timestamp that version was last queried/checked
-	RegexMissesContent       uint   `json:"regex_misses_content,omitempty"`       // Counter for the number of regex misses on URL content
-	RegexMissesVersion       uint   `json:"regex_misses_version,omitempty"`       // Counter for the number of regex misses on version
+	ApprovedVersion          string         `json:"approved_version,omitempty"`           // The version that's been approved
+	DeployedVersion          string         `json:"deployed_version,omitempty"`           // Track the deployed version of the service from the last successful WebHook
+	DeployedVersionTimestamp string         `json:"deployed_version_timestamp,omitempty"` // UTC timestamp that the deployed version change was noticed
+	LatestVersion            string         `json:"latest_version,omitempty"`             // Latest version found from query()
+	LatestVersionTimestamp   string         `json:"latest_version_timestamp,omitempty"`   // UTC timestamp that the latest version change was noticed
+	LastQueried              string         `json:"last_queried,omitempty"`               // UTC timestamp that version was last queried/checked
+	NextQuery                string         `json:"next_query,omitempty"`                 // UTC timestamp that version will next be queried/checked
+	PendingVersion           string         `json:"pending_version,omitempty"`            // Version waiting for require.min_age before becoming the latest version
+	PendingVersionTimestamp  string         `json:"pending_version_timestamp,omitempty"`  // UTC timestamp that the pending version was first seen (or published)
+	RegexMissesContent       uint           `json:"regex_misses_content,omitempty"`       // Counter for the number of regex misses on URL content
+	RegexMissesVersion       uint           `json:"regex_misses_version,omitempty"`       // Counter for the number of regex misses on version
+	ConsecutiveFailures      uint           `json:"consecutive_failures,omitempty"`       // Counter for the number of latest version queries that have failed in a row
+	LastError                string         `json:"last_error,omitempty"`                 // Error of the last failed latest version query
+	LatestVersionRelease     *StatusRelease `json:"latest_version_release,omitempty"`     // Release (name/notes/...) of the latest version
+}
+
+// StatusRelease is the release (name/notes/...) of a version.
+type StatusRelease struct {
+	Name        string `json:"name,omitempty"`         // Name of the release
+	Notes       string `json:"notes,omitempty"`        // Release notes/changelog
+	URL         string `json:"url,omitempty"`          // Web URL of the release
+	Author      string `json:"author,omitempty"`       // Author of the release
+	PublishedAt string `json:"published_at,omitempty"` // UTC timestamp that the release was published
 }
 
 // String returns a JSON string representation of the Status.
@@ -349,7 +383,14 @@ type ServiceDefaults struct {
 type ServiceOptions struct {
 	Active             *bool  `json:"active,omitempty"`              // Active Service?
 	Interval           string `json:"interval,omitempty"`            // AhBmCs = Sleep A hours, B minutes and C seconds between queries
+	Schedule           string `json:"schedule,omitempty"`            // Cron expression of when to query (instead of every Interval)
+	Jitter             string `json:"jitter,omitempty"`              // Random delay of up to this % of the Interval/Schedule period added to each query
+	Backoff            string `json:"backoff,omitempty"`             // Multiply the wait between queries by this for each consecutive failed query
+	BackoffMax         string `json:"backoff_max,omitempty"`         // Longest wait between queries when backing off
+	Priority           string `json:"priority,omitempty"`            // Priority of queries when API rate limits run low
 	SemanticVersioning *bool  `json:"semantic_versioning,omitempty"` // default - true = Version has to be greater than the previous to trigger alerts/WebHooks
+	VersionConstraint  string `json:"version_constraint,omitempty"`  // e.g. "~1.4", "^2", "<3.0.0", "patch" - Versions must satisfy this to be considered
+	VersionScheme      string `json:"version_scheme,omitempty"`      // default - semver = Scheme to parse and order versions with (semver/loose-semver/calver/pep440/debian)
 }
 
 // DashboardOptions.
@@ -362,11 +403,18 @@ type DashboardOptions struct {
 
 // LatestVersion lookup of the service.
 type LatestVersion struct {
-	Type              string                `json:"type,omitempty"`                // Service Type, github/url
+	Type              string                `json:"type,omitempty"`                // Service Type, container/crates/feed/git/gitea/github/gitlab/goproxy/helm/npm/pypi/url
 	URL               string                `json:"url,omitempty"`                 // URL to query
+	BaseURL           string                `json:"base_url,omitempty"`            // Base URL of the GitLab/Gitea instance or package/container registry
+	UseTags           *bool                 `json:"use_tags,omitempty"`            // Whether to query the tags rather than the releases
+	Username          string                `json:"username,omitempty"`            // Username to get a container registry token with/for git Basic Auth
+	Chart             string                `json:"chart,omitempty"`               // Helm chart to track
+	UseAppVersion     *bool                 `json:"use_app_version,omitempty"`     // Whether to track the appVersion of the Helm chart
+	FeedElement       string                `json:"feed_element,omitempty"`        // Element of each feed item to get the version from
 	AccessToken       string                `json:"access_token,omitempty"`        // GitHub access token to use
 	AllowInvalidCerts *bool                 `json:"allow_invalid_certs,omitempty"` // default - false = Disallows invalid HTTPS certificates
 	UsePreRelease     *bool                 `json:"use_prerelease,omitempty"`      // Whether GitHub prereleases should be used
+	UseGraphQL        *bool                 `json:"use_graphql,omitempty"`         // Whether GitHub releases are queried in batched GraphQL requests
 	URLCommands       *URLCommandSlice      `json:"url_commands,omitempty"`        // Commands to filter the release from the URL request
 	Require           *LatestVersionRequire `json:"require,omitempty"`             // Requirements for the version to be considered valid
 }
@@ -388,10 +436,15 @@ func (slice *LatestVersionRequireDefaults) String() (str string) {
 
 // LatestVersionRequire contains commands, regex etc for the release to be considered valid.
 type LatestVersionRequire struct {
-	Command      []string            `json:"command,omitempty"`       // Require Command to pass
-	Docker       *RequireDockerCheck `json:"docker,omitempty"`        // Docker image tag requirements
-	RegexContent string              `json:"regex_content,omitempty"` // "abc-[a-z]+-{{ version }}_amd64.deb" This regex must exist in the body of the URL to trigger new version actions
-	RegexVersion string              `json:"regex_version,omitempty"` // "v*[0-9.]+" The version found must match this release to trigger new version actions
+	Command           []string            `json:"command,omitempty"`             // Require Command to pass
+	Docker            *RequireDockerCheck `json:"docker,omitempty"`              // Docker image tag requirements
+	RegexContent      string              `json:"regex_content,omitempty"`       // "abc-[a-z]+-{{ version }}_amd64.deb" This regex must exist in the body of the URL to trigger new version actions
+	RegexVersion      string              `json:"regex_version,omitempty"`       // "v*[0-9.]+" The version found must match this release to trigger new version actions
+	RegexNotesInclude string              `json:"regex_notes_include,omitempty"` // "(?i)stable" This regex must exist in the release notes to trigger new version actions
+	RegexNotesExclude string              `json:"regex_notes_exclude,omitempty"` // "(?i)do not use" This regex mustn't exist in the release notes to trigger new version actions
+	MinAge            string              `json:"min_age,omitempty"`             // "48h" The version must have been visible for this long to trigger new version actions
+	Assets            *RequireAssets      `json:"assets,omitempty"`              // Release asset requirements
+	Verify            *RequireVerify      `json:"verify,omitempty"`              // Checksum/signature verification of a release asset
 }
 
 // LatestVersionRequireDefaults for the release to be considered valid.
@@ -416,11 +469,34 @@ type RequireDockerCheckDefaults struct {
 }
 
 type RequireDockerCheck struct {
-	Type     string `json:"type,omitempty"`     // Where to check, e.g. hub (DockerHub), GHCR, Quay
-	Image    string `json:"image,omitempty"`    // Image to check
-	Tag      string `json:"tag,omitempty"`      // Tag to check for
-	Username string `json:"username,omitempty"` // Username to get a new token
-	Token    string `json:"token,omitempty"`    // Token to get the token for the queries
+	Type              string   `json:"type,omitempty"`                // Where to check, e.g. hub (DockerHub), GHCR, Quay, registry
+	Registry          string   `json:"registry,omitempty"`            // Host of the registry (type=registry)
+	CAFile            string   `json:"ca_file,omitempty"`             // PEM file of CA certificates to trust for the Registry
+	AllowInvalidCerts *bool    `json:"allow_invalid_certs,omitempty"` // Skip TLS verification of the Registry
+	Image             string   `json:"image,omitempty"`               // Image to check
+	Tag               string   `json:"tag,omitempty"`                 // Tag to check for
+	Platforms         []string `json:"platforms,omitempty"`           // Platforms the Tag must be available for
+	Username          string   `json:"username,omitempty"`            // Username to get a new token
+	Token             string   `json:"token,omitempty"`               // Token to get the token for the queries
+}
+
+type RequireAssets struct {
+	Name     string `json:"name,omitempty"`      // RegEx that the asset names must match
+	MinCount int    `json:"min_count,omitempty"` // Minimum number of (matching) assets
+	MinSize  string `json:"min_size,omitempty"`  // Minimum size of the (matching) assets
+}
+
+type RequireVerify struct {
+	Asset     string                  `json:"asset,omitempty"`     // RegEx of the asset to verify
+	Checksums string                  `json:"checksums,omitempty"` // RegEx of the asset holding the checksums
+	Signature *RequireVerifySignature `json:"signature,omitempty"` // Detached signature of the checksums asset
+}
+
+type RequireVerifySignature struct {
+	Type      string `json:"type,omitempty"`       // "minisign"/"openpgp"
+	Asset     string `json:"asset,omitempty"`      // RegEx of the asset holding the signature
+	PublicKey string `json:"public_key,omitempty"` // minisign public key
+	Keyring   string `json:"keyring,omitempty"`    // Path to the OpenPGP keyring
 }
 
 // DeployedVersionLookup of the service.
@@ -468,12 +544,16 @@ func (slice *URLCommandSlice) String() (str string) {
 
 // URLCommand is a command to be ran to filter version from the URL body.
 type URLCommand struct {
-	Type  string  `json:"type,omitempty"`  // regex/replace/split
-	Regex *string `json:"regex,omitempty"` // regex: regexp.MustCompile(Regex)
-	Index int     `json:"index,omitempty"` // regex/split: re.FindAllString(URL_content, -1)[Index]  /  strings.Split("text")[Index]
-	Text  *string `json:"text,omitempty"`  // split:       strings.Split(tgtString, "Text")
-	New   *string `json:"new,omitempty"`   // replace:     strings.ReplaceAll(tgtString, "Old", "New")
-	Old   *string `json:"old,omitempty"`   // replace:     strings.ReplaceAll(tgtString, "Old", "New")
+	Type      string  `json:"type,omitempty"`      // css/json/regex/replace/split/xpath
+	Regex     *string `json:"regex,omitempty"`     // regex: regexp.MustCompile(Regex)
+	Index     int     `json:"index,omitempty"`     // css/json/regex/split/xpath: matches[Index]  /  util.GetValuesFromJSON(URL_content, Path)[Index]  /  re.FindAllString(URL_content, -1)[Index]  /  strings.Split("text")[Index]
+	Path      *string `json:"path,omitempty"`      // json/xpath:  "foo.bar[0].version"  /  "//div[@class='release']/h2"
+	Selector  *string `json:"selector,omitempty"`  // css:         "div.release > h2"
+	Attribute *string `json:"attribute,omitempty"` // css/xpath:   attribute of the matched elements (default = text)
+	All       bool    `json:"all,omitempty"`       // css/json/regex/split/xpath: every match is a candidate version
+	Text      *string `json:"text,omitempty"`      // split:       strings.Split(tgtString, "Text")
+	New       *string `json:"new,omitempty"`       // replace:     strings.ReplaceAll(tgtString, "Old", "New")
+	Old       *string `json:"old,omitempty"`       // replace:     strings.ReplaceAll(tgtString, "Old", "New")
 }
 
 type Command []string
@@ -626,6 +626,28 @@ func TestServiceSummary_RemoveUnchanged(t *testing.T) {
 			want: &ServiceSummary{
 				HasDeployedVersionLookup: boolPtr(false)},
 		},
+		"same version_constraint": {
+			old: &ServiceSummary{
+				VersionConstraint: stringPtr("~1.4")},
+			new: &ServiceSummary{
+				VersionConstraint: stringPtr("~1.4")},
+			want: &ServiceSummary{},
+		},
+		"different version_constraint": {
+			old: &ServiceSummary{
+				VersionConstraint: stringPtr("~1.4")},
+			new: &ServiceSummary{
+				VersionConstraint: stringPtr("^2")},
+			want: &ServiceSummary{
+				VersionConstraint: stringPtr("^2")},
+		},
+		"removed version_constraint": {
+			old: &ServiceSummary{
+				VersionConstraint: stringPtr("~1.4")},
+			new: &ServiceSummary{},
+			want: &ServiceSummary{
+				VersionConstraint: stringPtr("")},
+		},
 		"same approved_version": {
 			old: &ServiceSummary{
 				Status: &Status{
@@ -714,6 +736,49 @@ func TestServiceSummary_RemoveUnchanged(t *testing.T) {
 					LatestVersion:          "4.5.6",
 					LatestVersionTimestamp: "2020-02-02T00:00:00Z"}},
 		},
+		"same query status": {
+			old: &ServiceSummary{
+				Status: &Status{
+					NextQuery:           "2020-01-01T01:00:00Z",
+					ConsecutiveFailures: 2,
+					LastError:           "x509 (certificate invalid)"}},
+			new: &ServiceSummary{
+				Status: &Status{
+					NextQuery:           "2020-01-01T01:00:00Z",
+					ConsecutiveFailures: 2,
+					LastError:           "x509 (certificate invalid)"}},
+			want: &ServiceSummary{},
+		},
+		"different next_query": {
+			old: &ServiceSummary{
+				Status: &Status{
+					NextQuery:           "2020-01-01T01:00:00Z",
+					ConsecutiveFailures: 2,
+					LastError:           "x509 (certificate invalid)"}},
+			new: &ServiceSummary{
+				Status: &Status{
+					NextQuery:           "2020-01-01T02:00:00Z",
+					ConsecutiveFailures: 2,
+					LastError:           "x509 (certificate invalid)"}},
+			want: &ServiceSummary{
+				Status: &Status{
+					NextQuery:           "2020-01-01T02:00:00Z",
+					ConsecutiveFailures: 2,
+					LastError:           "x509 (certificate invalid)"}},
+		},
+		"failures reset": {
+			old: &ServiceSummary{
+				Status: &Status{
+					NextQuery:           "2020-01-01T01:00:00Z",
+					ConsecutiveFailures: 2,
+					LastError:           "x509 (certificate invalid)"}},
+			new: &ServiceSummary{
+				Status: &Status{
+					NextQuery: "2020-01-01T01:00:00Z"}},
+			want: &ServiceSummary{
+				Status: &Status{
+					NextQuery: "2020-01-01T01:00:00Z"}},
+		},
 		"mmultiple differences": {
 			old: &ServiceSummary{
 				IconLinkTo: stringPtr("https://release-argus.io"),
@@ -18,9 +18,19 @@ import "time"
 
 // VersionAPI used in /api/v1/version
 type VersionAPI struct {
-	Version   string `json:"version"`
-	BuildDate string `json:"buildDate"`
-	GoVersion string `json:"goVersion"`
+	Version          string            `json:"version"`
+	BuildDate        string            `json:"buildDate"`
+	GoVersion        string            `json:"goVersion"`
+	GitHubRateLimits []GitHubRateLimit `json:"githubRateLimits,omitempty"`
+}
+
+// GitHubRateLimit is the GitHub API quota remaining for an access token.
+type GitHubRateLimit struct {
+	Token     string `json:"token"`           // Identifier of the access token (not the token itself)
+	Resource  string `json:"resource"`        // API resource, e.g. "core" (REST) or "graphql"
+	Limit     int    `json:"limit"`           // Requests allowed per window
+	Remaining int    `json:"remaining"`       // Requests remaining in this window
+	Reset     string `json:"reset,omitempty"` // UTC timestamp that the window resets
 }
 
 // RefreshAPI used in /api/v1/*_version/refresh
@@ -116,9 +116,10 @@ func (api *API) httpVersion(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
 	err := json.NewEncoder(w).Encode(api_type.VersionAPI{
-		Version:   util.Version,
-		BuildDate: util.BuildDate,
-		GoVersion: util.GoVersion,
+		Version:          util.Version,
+		BuildDate:        util.BuildDate,
+		GoVersion:        util.GoVersion,
+		GitHubRateLimits: latestver.GitHubRateLimits(),
 	})
 	api.Log.Error(err, logFrom, err != nil)
 }
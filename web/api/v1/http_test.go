@@ -25,6 +25,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"reflect"
 	"regexp"
 	"strings"
 	"sync"
@@ -65,7 +66,7 @@ func TestHTTP_Version(t *testing.T) {
 		BuildDate: util.BuildDate,
 		GoVersion: util.GoVersion,
 	}
-	if got != want {
+	if !reflect.DeepEqual(got, want) {
 		t.Errorf("Version HTTP should have returned %v, not %v",
 			want, got)
 	}
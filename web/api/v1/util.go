@@ -95,7 +95,14 @@ func convertAndCensorDefaults(input *config.Defaults) (defaults *api_type.Defaul
 			Service: api_type.Service{
 				Options: &api_type.ServiceOptions{
 					Interval:           input.Service.Options.Interval,
-					SemanticVersioning: input.Service.Options.SemanticVersioning},
+					Schedule:           input.Service.Options.Schedule,
+					Jitter:             input.Service.Options.Jitter,
+					Backoff:            input.Service.Options.Backoff,
+					BackoffMax:         input.Service.Options.BackoffMax,
+					Priority:           input.Service.Options.Priority,
+					SemanticVersioning: input.Service.Options.SemanticVersioning,
+					VersionConstraint:  input.Service.Options.VersionConstraint,
+					VersionScheme:      input.Service.Options.VersionScheme},
 				DeployedVersionLookup: &api_type.DeployedVersionLookup{
 					AllowInvalidCerts: input.Service.DeployedVersionLookup.AllowInvalidCerts},
 				Dashboard: &api_type.DashboardOptions{
@@ -104,7 +111,8 @@ func convertAndCensorDefaults(input *config.Defaults) (defaults *api_type.Defaul
 				LatestVersion: api_type.LatestVersion{
 					AccessToken:       util.DefaultOrValue(input.Service.LatestVersion.AccessToken, "<secret>"),
 					AllowInvalidCerts: input.Service.LatestVersion.AllowInvalidCerts,
-					UsePreRelease:     input.Service.LatestVersion.UsePreRelease},
+					UsePreRelease:     input.Service.LatestVersion.UsePreRelease,
+					UseGraphQL:        input.Service.LatestVersion.UseGraphQL},
 				Require: convertAndCensorLatestVersionRequireDefaults(&input.Service.LatestVersion.Require)}},
 		Notify: *convertAndCensorNotifySliceDefaults(&input.Notify),
 		WebHook: api_type.WebHook{
@@ -256,12 +264,16 @@ func convertURLCommandSlice(commands *filter.URLCommandSlice) *api_type.URLComma
 	slice := make(api_type.URLCommandSlice, len(*commands))
 	for index := range *commands {
 		slice[index] = api_type.URLCommand{
-			Type:  (*commands)[index].Type,
-			Regex: (*commands)[index].Regex,
-			Index: (*commands)[index].Index,
-			Text:  (*commands)[index].Text,
-			Old:   (*commands)[index].Old,
-			New:   (*commands)[index].New}
+			Type:      (*commands)[index].Type,
+			Regex:     (*commands)[index].Regex,
+			Index:     (*commands)[index].Index,
+			Path:      (*commands)[index].Path,
+			Selector:  (*commands)[index].Selector,
+			Attribute: (*commands)[index].Attribute,
+			All:       (*commands)[index].All,
+			Text:      (*commands)[index].Text,
+			Old:       (*commands)[index].Old,
+			New:       (*commands)[index].New}
 	}
 	return &slice
 }
@@ -362,30 +374,73 @@ func convertAndCensorService(service *service.Service) (apiService *api_type.Ser
 	apiService.Options = &api_type.ServiceOptions{
 		Active:             service.Options.Active,
 		Interval:           service.Options.Interval,
-		SemanticVersioning: service.Options.SemanticVersioning}
+		Schedule:           service.Options.Schedule,
+		Jitter:             service.Options.Jitter,
+		Backoff:            service.Options.Backoff,
+		BackoffMax:         service.Options.BackoffMax,
+		Priority:           service.Options.Priority,
+		SemanticVersioning: service.Options.SemanticVersioning,
+		VersionConstraint:  service.Options.VersionConstraint,
+		VersionScheme:      service.Options.VersionScheme}
 
 	apiService.LatestVersion = &api_type.LatestVersion{
 		Type:              service.LatestVersion.Type,
 		URL:               service.LatestVersion.URL,
+		BaseURL:           service.LatestVersion.BaseURL,
+		UseTags:           service.LatestVersion.UseTags,
+		Username:          service.LatestVersion.Username,
+		Chart:             service.LatestVersion.Chart,
+		UseAppVersion:     service.LatestVersion.UseAppVersion,
+		FeedElement:       service.LatestVersion.FeedElement,
 		AccessToken:       util.DefaultOrValue(service.LatestVersion.AccessToken, "<secret>"),
 		AllowInvalidCerts: service.LatestVersion.AllowInvalidCerts,
 		UsePreRelease:     service.LatestVersion.UsePreRelease,
+		UseGraphQL:        service.LatestVersion.UseGraphQL,
 		URLCommands:       convertURLCommandSlice(&service.LatestVersion.URLCommands)}
 	if service.LatestVersion.Require != nil {
 		var docker *api_type.RequireDockerCheck
 		if service.LatestVersion.Require.Docker != nil {
 			docker = &api_type.RequireDockerCheck{
-				Type:     service.LatestVersion.Require.Docker.Type,
-				Image:    service.LatestVersion.Require.Docker.Image,
-				Tag:      service.LatestVersion.Require.Docker.Tag,
-				Username: service.LatestVersion.Require.Docker.Username,
-				Token:    util.ValueIfNotDefault(service.LatestVersion.Require.Docker.Token, "<secret>")}
+				Type:              service.LatestVersion.Require.Docker.Type,
+				Registry:          service.LatestVersion.Require.Docker.Registry,
+				CAFile:            service.LatestVersion.Require.Docker.CAFile,
+				AllowInvalidCerts: service.LatestVersion.Require.Docker.AllowInvalidCerts,
+				Image:             service.LatestVersion.Require.Docker.Image,
+				Tag:               service.LatestVersion.Require.Docker.Tag,
+				Platforms:         service.LatestVersion.Require.Docker.Platforms,
+				Username:          service.LatestVersion.Require.Docker.Username,
+				Token:             util.ValueIfNotDefault(service.LatestVersion.Require.Docker.Token, "<secret>")}
+		}
+		var assets *api_type.RequireAssets
+		if service.LatestVersion.Require.Assets != nil {
+			assets = &api_type.RequireAssets{
+				Name:     service.LatestVersion.Require.Assets.Name,
+				MinCount: service.LatestVersion.Require.Assets.MinCount,
+				MinSize:  service.LatestVersion.Require.Assets.MinSize}
+		}
+		var verify *api_type.RequireVerify
+		if service.LatestVersion.Require.Verify != nil {
+			verify = &api_type.RequireVerify{
+				Asset:     service.LatestVersion.Require.Verify.Asset,
+				Checksums: service.LatestVersion.Require.Verify.Checksums}
+			if signature := service.LatestVersion.Require.Verify.Signature; signature != nil {
+				verify.Signature = &api_type.RequireVerifySignature{
+					Type:      signature.Type,
+					Asset:     signature.Asset,
+					PublicKey: signature.PublicKey,
+					Keyring:   signature.Keyring}
+			}
 		}
 		apiService.LatestVersion.Require = &api_type.LatestVersionRequire{
-			Command:      service.LatestVersion.Require.Command,
-			Docker:       docker,
-			RegexContent: service.LatestVersion.Require.RegexContent,
-			RegexVersion: service.LatestVersion.Require.RegexVersion}
+			Command:           service.LatestVersion.Require.Command,
+			Docker:            docker,
+			RegexContent:      service.LatestVersion.Require.RegexContent,
+			RegexVersion:      service.LatestVersion.Require.RegexVersion,
+			RegexNotesInclude: service.LatestVersion.Require.RegexNotesInclude,
+			RegexNotesExclude: service.LatestVersion.Require.RegexNotesExclude,
+			MinAge:            service.LatestVersion.Require.MinAge,
+			Assets:            assets,
+			Verify:            verify}
 	}
 
 	// DeployedVersionLookup
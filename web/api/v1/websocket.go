@@ -303,7 +303,14 @@ func (api *API) wsConfigDefaults(client *Client) {
 					Service: api_type.Service{
 						Options: &api_type.ServiceOptions{
 							Interval:           api.Config.Defaults.Service.Options.Interval,
-							SemanticVersioning: api.Config.Defaults.Service.Options.SemanticVersioning},
+							Schedule:           api.Config.Defaults.Service.Options.Schedule,
+							Jitter:             api.Config.Defaults.Service.Options.Jitter,
+							Backoff:            api.Config.Defaults.Service.Options.Backoff,
+							BackoffMax:         api.Config.Defaults.Service.Options.BackoffMax,
+							Priority:           api.Config.Defaults.Service.Options.Priority,
+							SemanticVersioning: api.Config.Defaults.Service.Options.SemanticVersioning,
+							VersionConstraint:  api.Config.Defaults.Service.Options.VersionConstraint,
+							VersionScheme:      api.Config.Defaults.Service.Options.VersionScheme},
 						DeployedVersionLookup: &api_type.DeployedVersionLookup{
 							AllowInvalidCerts: api.Config.Defaults.Service.DeployedVersionLookup.AllowInvalidCerts},
 						Dashboard: &api_type.DashboardOptions{
@@ -312,7 +319,8 @@ func (api *API) wsConfigDefaults(client *Client) {
 						LatestVersion: api_type.LatestVersion{
 							AccessToken:       util.DefaultOrValue(api.Config.Defaults.Service.LatestVersion.AccessToken, "<secret>"),
 							AllowInvalidCerts: api.Config.Defaults.Service.LatestVersion.AllowInvalidCerts,
-							UsePreRelease:     api.Config.Defaults.Service.LatestVersion.UsePreRelease},
+							UsePreRelease:     api.Config.Defaults.Service.LatestVersion.UsePreRelease,
+							UseGraphQL:        api.Config.Defaults.Service.LatestVersion.UseGraphQL},
 						Require: latestVersionRequireDefaults}},
 				Notify:  *notifyDefaults,
 				WebHook: *webhookDefaults}}}
@@ -72,6 +72,18 @@ var (
 		[]string{
 			"id",
 		})
+	LatestVersionQueryConsecutiveFailures = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "latest_version_query_consecutive_failures",
+		Help: "Number of latest version queries of this service that have failed in a row."},
+		[]string{
+			"id",
+		})
+	GitHubRateLimitRemaining = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "github_rate_limit_remaining",
+		Help: "Number of GitHub API requests remaining in the current rate limit window of this access token."},
+		[]string{
+			"id",
+		})
 	AckWaiting = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "ack_waiting",
 		Help: "Whether a new release is waiting to be acknowledged (skipped/approved; 0=no, 1=yes)."},
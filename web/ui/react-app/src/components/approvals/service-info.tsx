@@ -11,8 +11,11 @@ import { ModalType, ServiceSummaryType } from "types/summary";
 import {
   faArrowRotateRight,
   faCheck,
+  faFileLines,
+  faHourglassHalf,
   faInfo,
   faInfoCircle,
+  faLock,
   faSatelliteDish,
   faTimes,
 } from "@fortawesome/free-solid-svg-icons";
@@ -78,6 +81,81 @@ export const ServiceInfo: FC<Props> = ({
     </OverlayTrigger>
   ) : null;
 
+  const versionConstraintIcon = service.version_constraint ? (
+    <OverlayTrigger
+      key="version-constraint"
+      placement="top"
+      delay={{ show: 500, hide: 500 }}
+      overlay={
+        <Tooltip id={`tooltip-version-constraint`}>
+          Constrained to {service.version_constraint}
+        </Tooltip>
+      }
+    >
+      <FontAwesomeIcon
+        icon={faLock}
+        style={{ paddingLeft: "0.5rem", paddingBottom: "0.1rem" }}
+      />
+    </OverlayTrigger>
+  ) : null;
+
+  const pendingVersionIcon = service.status?.pending_version ? (
+    <OverlayTrigger
+      key="pending-version"
+      placement="top"
+      delay={{ show: 500, hide: 500 }}
+      overlay={
+        <Tooltip id={`tooltip-pending-version`}>
+          {service.status.pending_version} pending since{" "}
+          {service.status.pending_version_timestamp
+            ? formatRelative(
+                new Date(service.status.pending_version_timestamp),
+                new Date()
+              )
+            : "unknown"}
+        </Tooltip>
+      }
+    >
+      <FontAwesomeIcon
+        icon={faHourglassHalf}
+        style={{ paddingLeft: "0.5rem", paddingBottom: "0.1rem" }}
+      />
+    </OverlayTrigger>
+  ) : null;
+
+  const release = service.status?.latest_version_release;
+  const releaseNotesIcon = release?.notes ? (
+    <OverlayTrigger
+      key="release-notes"
+      placement="top"
+      delay={{ show: 500, hide: 500 }}
+      overlay={
+        <Tooltip
+          id={`tooltip-release-notes`}
+          style={{ whiteSpace: "pre-line" }}
+        >
+          {release.name && <strong>{release.name}</strong>}
+          {release.name && <br />}
+          {release.notes.length > 500
+            ? `${release.notes.slice(0, 500)}...`
+            : release.notes}
+        </Tooltip>
+      }
+    >
+      <a
+        href={release.url}
+        target="_blank"
+        rel="noreferrer noopener"
+        style={{ color: "inherit" }}
+      >
+        <FontAwesomeIcon
+          icon={faFileLines}
+          style={{ paddingLeft: "0.5rem", paddingBottom: "0.1rem" }}
+        />
+      </a>
+    </OverlayTrigger>
+  ) : null;
+
   const skippedVersionIcon =
     updateSkipped && service.status?.approved_version ? (
       <OverlayTrigger
@@ -143,6 +221,7 @@ export const ServiceInfo: FC<Props> = ({
               {updateApproved && (service.webhook || service.command)
                 ? `${service.webhook ? "WebHooks" : "Commands"} already sent:`
                 : "Update available:"}
+              {releaseNotesIcon}
             </ListGroup.Item>
             <ListGroup.Item
               key="update-buttons"
@@ -199,6 +278,8 @@ export const ServiceInfo: FC<Props> = ({
               <>
                 Current version:
                 {deployedVersionIcon}
+                {versionConstraintIcon}
+                {pendingVersionIcon}
                 {skippedVersionIcon}
               </>
               <br />
@@ -236,7 +317,9 @@ export const ServiceInfo: FC<Props> = ({
       </ListGroup>
       <Card.Footer
         className={
-          serviceWarning || !service?.status?.last_queried
+          serviceWarning ||
+          !service?.status?.last_queried ||
+          service?.status?.consecutive_failures
             ? "alert-warning rounded-bottom"
             : ""
         }
@@ -248,13 +331,48 @@ export const ServiceInfo: FC<Props> = ({
           }
         >
           {service?.status?.last_queried ? (
-            <>
-              queried{" "}
-              {formatRelative(
-                new Date(service.status.last_queried),
-                new Date()
-              )}
-            </>
+            <OverlayTrigger
+              key="next-query"
+              placement="top"
+              delay={{ show: 500, hide: 500 }}
+              overlay={
+                service.status.next_query ||
+                service.status.consecutive_failures ? (
+                  <Tooltip id={`tooltip-next-query`}>
+                    {service.status.consecutive_failures ? (
+                      <>
+                        failed {service.status.consecutive_failures} time
+                        {service.status.consecutive_failures === 1 ? "" : "s"}{" "}
+                        in a row
+                        {service.status.last_error
+                          ? `: ${service.status.last_error}`
+                          : ""}
+                        <br />
+                      </>
+                    ) : null}
+                    {service.status.next_query && (
+                      <>
+                        next query{" "}
+                        {formatRelative(
+                          new Date(service.status.next_query),
+                          new Date()
+                        )}
+                      </>
+                    )}
+                  </Tooltip>
+                ) : (
+                  <></>
+                )
+              }
+            >
+              <span>
+                queried{" "}
+                {formatRelative(
+                  new Date(service.status.last_queried),
+                  new Date()
+                )}
+              </span>
+            </OverlayTrigger>
           ) : service.loading ? (
             "loading"
           ) : (
@@ -7,12 +7,20 @@ import { FC, memo, useEffect, useMemo } from "react";
 import { FormItem, FormLabel, FormSelect } from "components/generic/form";
 import { useFormContext, useWatch } from "react-hook-form";
 
+import { BooleanWithDefault } from "components/generic";
 import Command from "./command";
 
+const VerifySignatureOptions = [
+  { label: "None", value: "" },
+  { label: "minisign", value: "minisign" },
+  { label: "OpenPGP", value: "openpgp" },
+];
+
 const DockerRegistryOptions = [
   { label: "Docker Hub", value: "hub" },
   { label: "GHCR", value: "ghcr" },
   { label: "Quay", value: "quay" },
+  { label: "Registry (OCI)", value: "registry" },
 ];
 
 type Props = {
@@ -52,7 +60,14 @@ const EditServiceLatestVersionRequire: FC<Props> = ({
     name: "latest_version.require.docker.type",
   });
   const selectedDockerRegistry = dockerRegistry || defaultDockerRegistry;
-  const showUsernameField = (dockerRegistry || defaultDockerRegistry) === "hub";
+  const verifySignatureType = useWatch({
+    name: "latest_version.require.verify.signature.type",
+  });
+  const showUsernameField = ["hub", "registry"].includes(
+    dockerRegistry || defaultDockerRegistry
+  );
+  const showRegistryFields =
+    (dockerRegistry || defaultDockerRegistry) === "registry";
 
   useEffect(() => {
     // Default to Docker Hub if no registry is selected and no default registry.
@@ -81,6 +96,98 @@ const EditServiceLatestVersionRequire: FC<Props> = ({
             isRegex
             onRight
           />
+          <FormItem
+            name="latest_version.require.regex_notes_include"
+            col_xs={6}
+            label={"RegEx Notes Include"}
+            tooltip="Release notes must match, e.g. '(?i)stable'. URL=webpage must match"
+            isRegex
+          />
+          <FormItem
+            name="latest_version.require.regex_notes_exclude"
+            col_xs={6}
+            label={"RegEx Notes Exclude"}
+            tooltip="Release notes mustn't match, e.g. '(?i)do not use|known regression'. URL=webpage mustn't match"
+            isRegex
+            onRight
+          />
+          <FormItem
+            name="latest_version.require.min_age"
+            col_xs={12}
+            label="Min age"
+            tooltip="How long a new version must have been released/visible before it's acted on, e.g. '48h'"
+          />
+
+          <FormLabel text="Assets" />
+          <FormItem
+            name="latest_version.require.assets.name"
+            col_xs={12}
+            label="Name"
+            tooltip="RegEx that release asset names must match, e.g. 'argus-{{ version }}.linux-amd64'"
+            isRegex
+          />
+          <FormItem
+            name="latest_version.require.assets.min_count"
+            col_xs={6}
+            label="Min count"
+            tooltip="Minimum number of (matching) assets"
+            type="number"
+          />
+          <FormItem
+            name="latest_version.require.assets.min_size"
+            col_xs={6}
+            label="Min size"
+            tooltip="Minimum size of the (matching) assets, e.g. '10MB'"
+            onRight
+          />
+
+          <FormLabel text="Verify" />
+          <FormItem
+            name="latest_version.require.verify.asset"
+            col_xs={6}
+            label="Asset"
+            tooltip="RegEx of the asset to verify, e.g. 'argus-{{ version }}.linux-amd64$'"
+            isRegex
+          />
+          <FormItem
+            name="latest_version.require.verify.checksums"
+            col_xs={6}
+            label="Checksums"
+            tooltip="RegEx of the asset holding the checksums, e.g. 'SHA256SUMS$'"
+            isRegex
+            onRight
+          />
+          <FormSelect
+            name="latest_version.require.verify.signature.type"
+            col_xs={6}
+            label="Signature type"
+            tooltip="Verify the checksums asset against a detached signature"
+            options={VerifySignatureOptions}
+          />
+          <FormItem
+            name="latest_version.require.verify.signature.asset"
+            col_xs={6}
+            label="Signature"
+            tooltip="RegEx of the asset holding the signature, e.g. 'SHA256SUMS\.minisig$'"
+            isRegex
+            onRight
+          />
+          {verifySignatureType === "minisign" && (
+            <FormItem
+              name="latest_version.require.verify.signature.public_key"
+              col_xs={12}
+              label="Public key"
+              tooltip="minisign public key, e.g. 'RWQ...'"
+            />
+          )}
+          {verifySignatureType === "openpgp" && (
+            <FormItem
+              name="latest_version.require.verify.signature.keyring"
+              col_xs={12}
+              label="Keyring"
+              tooltip="Path to the (binary or armored) OpenPGP keyring holding the signing key"
+            />
+          )}
 
           <Form.Group className="pt-1">
             <FormLabel
@@ -99,6 +206,30 @@ const EditServiceLatestVersionRequire: FC<Props> = ({
             label="Type"
             options={dockerRegistryOptions}
           />
+          {showRegistryFields && (
+            <>
+              <FormItem
+                key="registry"
+                name="latest_version.require.docker.registry"
+                col_sm={6}
+                label="Registry"
+                tooltip="Host of the registry, e.g. 'harbor.example.com'"
+              />
+              <FormItem
+                key="ca_file"
+                name="latest_version.require.docker.ca_file"
+                col_sm={6}
+                label="CA File"
+                tooltip="PEM file of CA certificates to trust for this registry"
+                onRight
+              />
+              <BooleanWithDefault
+                name="latest_version.require.docker.allow_invalid_certs"
+                label="Allow Invalid Certs"
+                defaultValue={false}
+              />
+            </>
+          )}
           <FormItem
             name="latest_version.require.docker.image"
             label="Image"
@@ -111,6 +242,12 @@ const EditServiceLatestVersionRequire: FC<Props> = ({
             label="Tag"
             onRight
           />
+          <FormItem
+            name="latest_version.require.docker.platforms"
+            col_xs={12}
+            label="Platforms"
+            tooltip="Comma-separated platforms the tag must be available for, e.g. 'linux/amd64, linux/arm64'"
+          />
           {showUsernameField && (
             <FormItem
               key="username"
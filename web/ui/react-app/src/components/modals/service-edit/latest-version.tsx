@@ -70,6 +70,14 @@ const EditServiceLatestVersion: FC<Props> = ({
                     defaults?.use_prerelease || hard_defaults?.use_prerelease
                   }
                 />
+                <BooleanWithDefault
+                  name="latest_version.use_graphql"
+                  label="Use GraphQL"
+                  tooltip="Query the releases with the other services of this access token in batched GraphQL requests"
+                  defaultValue={
+                    defaults?.use_graphql || hard_defaults?.use_graphql
+                  }
+                />
               </>
             </>
           ) : (
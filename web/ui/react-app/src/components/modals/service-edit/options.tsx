@@ -2,10 +2,26 @@ import { Accordion, Form, Row } from "react-bootstrap";
 import { FC, memo } from "react";
 
 import { BooleanWithDefault } from "components/generic";
-import { FormItem } from "components/generic/form";
+import { FormItem, FormSelect } from "components/generic/form";
 import { ServiceOptionsType } from "types/config";
 import { useFormContext } from "react-hook-form";
 
+const versionSchemeOptions = [
+  { label: "Default", value: "" },
+  { label: "SemVer - 1.2.3", value: "semver" },
+  { label: "Loose SemVer - v1.2, 1.2.3.4", value: "loose-semver" },
+  { label: "CalVer - 2024.10.1", value: "calver" },
+  { label: "PEP 440 - 1.0rc1, 1.0.post2", value: "pep440" },
+  { label: "Debian - 1:2.30-1ubuntu1", value: "debian" },
+];
+
+const priorityOptions = [
+  { label: "Default", value: "" },
+  { label: "Low", value: "low" },
+  { label: "Normal", value: "normal" },
+  { label: "High", value: "high" },
+];
+
 interface Props {
   defaults?: ServiceOptionsType;
   hard_defaults?: ServiceOptionsType;
@@ -25,6 +41,53 @@ const EditServiceOptions: FC<Props> = ({ defaults, hard_defaults }) => {
           label="Interval"
           defaultVal={defaults?.interval || hard_defaults?.interval}
         />
+        <Row>
+          <FormItem
+            key="schedule"
+            name="options.schedule"
+            col_sm={8}
+            label="Schedule"
+            tooltip="Cron expression of when to query instead of every interval, e.g. '*/15 9-17 * * mon-fri'"
+            defaultVal={defaults?.schedule || hard_defaults?.schedule}
+          />
+          <FormItem
+            key="jitter"
+            name="options.jitter"
+            col_sm={4}
+            label="Jitter"
+            tooltip="Random delay of up to this % of the interval/schedule period, e.g. '10%'"
+            defaultVal={defaults?.jitter || hard_defaults?.jitter}
+            onRight
+          />
+        </Row>
+        <Row>
+          <FormItem
+            key="backoff"
+            name="options.backoff"
+            col_sm={6}
+            label="Backoff"
+            tooltip="Multiply the wait between queries by this for each query that failed in a row, e.g. '2' (off when empty)"
+            defaultVal={defaults?.backoff || hard_defaults?.backoff}
+          />
+          <FormItem
+            key="backoff_max"
+            name="options.backoff_max"
+            col_sm={6}
+            label="Backoff max"
+            tooltip="Longest wait between queries when backing off, e.g. '12h' (at most '168h')"
+            defaultVal={defaults?.backoff_max || hard_defaults?.backoff_max}
+            onRight
+          />
+        </Row>
+        <FormSelect
+          name="options.priority"
+          col_sm={12}
+          label="Priority"
+          tooltip={`Priority of queries when API rate limits run low - lower priorities wait for the reset sooner (default: ${
+            defaults?.priority || hard_defaults?.priority || "normal"
+          })`}
+          options={priorityOptions}
+        />
         <Row>
           <BooleanWithDefault
             name="options.semantic_versioning"
@@ -35,7 +98,28 @@ const EditServiceOptions: FC<Props> = ({ defaults, hard_defaults }) => {
               hard_defaults?.semantic_versioning
             }
           />
+          <FormSelect
+            name="options.version_scheme"
+            col_sm={12}
+            label="Version scheme"
+            tooltip={`Scheme to parse and order versions with when semantic versioning (default: ${
+              defaults?.version_scheme ||
+              hard_defaults?.version_scheme ||
+              "semver"
+            })`}
+            options={versionSchemeOptions}
+          />
         </Row>
+        <FormItem
+          key="version_constraint"
+          name="options.version_constraint"
+          col_sm={12}
+          label="Version constraint"
+          tooltip="Versions must satisfy this, e.g. '~1.4', '^2', '<3.0.0', 'patch' or 'minor'"
+          defaultVal={
+            defaults?.version_constraint || hard_defaults?.version_constraint
+          }
+        />
       </Accordion.Body>
     </Accordion>
   );
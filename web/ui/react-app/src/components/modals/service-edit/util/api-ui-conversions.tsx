@@ -34,8 +34,16 @@ export const convertAPIServiceDataEditToUI = (
           ),
           docker: {
             type: serviceData?.latest_version?.require?.docker?.type || "",
+            registry: serviceData?.latest_version?.require?.docker?.registry,
+            ca_file: serviceData?.latest_version?.require?.docker?.ca_file,
+            allow_invalid_certs:
+              serviceData?.latest_version?.require?.docker?.allow_invalid_certs,
             image: serviceData?.latest_version?.require?.docker?.image,
             tag: serviceData?.latest_version?.require?.docker?.tag,
+            platforms:
+              serviceData?.latest_version?.require?.docker?.platforms?.join(
+                ", "
+              ),
             username: serviceData?.latest_version?.require?.docker?.username,
             token: serviceData?.latest_version?.require?.docker?.token,
           },
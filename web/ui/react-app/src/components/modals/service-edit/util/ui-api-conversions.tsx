@@ -24,7 +24,14 @@ export const convertUIServiceDataEditToAPI = (
   payload.options = {
     active: data.options?.active,
     interval: data.options?.interval,
+    schedule: data.options?.schedule,
+    jitter: data.options?.jitter,
+    backoff: data.options?.backoff,
+    backoff_max: data.options?.backoff_max,
+    priority: data.options?.priority,
     semantic_versioning: data.options?.semantic_versioning,
+    version_constraint: data.options?.version_constraint,
+    version_scheme: data.options?.version_scheme,
   };
 
   // Latest version
@@ -34,6 +41,7 @@ export const convertUIServiceDataEditToAPI = (
     access_token: data.latest_version?.access_token,
     allow_invalid_certs: data.latest_version?.allow_invalid_certs,
     use_prerelease: data.latest_version?.use_prerelease,
+    use_graphql: data.latest_version?.use_graphql,
     url_commands: data.latest_version?.url_commands?.map((command) => ({
       ...urlCommandTrim(command),
       index: command.index ? Number(command.index) : undefined,
@@ -44,16 +52,45 @@ export const convertUIServiceDataEditToAPI = (
     payload.latest_version.require = {
       regex_content: data.latest_version.require?.regex_content,
       regex_version: data.latest_version.require?.regex_version,
+      regex_notes_include: data.latest_version.require?.regex_notes_include,
+      regex_notes_exclude: data.latest_version.require?.regex_notes_exclude,
+      min_age: data.latest_version.require?.min_age,
       command: (data.latest_version.require.command || []).map(
         (obj) => (obj as ArgType).arg
       ),
       docker: {
         type: data.latest_version.require?.docker?.type,
+        registry: data.latest_version.require?.docker?.registry,
+        ca_file: data.latest_version.require?.docker?.ca_file,
+        allow_invalid_certs:
+          data.latest_version.require?.docker?.allow_invalid_certs,
         image: data.latest_version.require?.docker?.image,
         tag: data.latest_version.require?.docker?.tag,
+        platforms: data.latest_version.require?.docker?.platforms
+          ?.split(",")
+          .map((platform) => platform.trim())
+          .filter((platform) => platform !== ""),
         username: data.latest_version.require?.docker?.username,
         token: data.latest_version.require?.docker?.token,
       },
+      assets: {
+        name: data.latest_version.require?.assets?.name,
+        min_count: data.latest_version.require?.assets?.min_count
+          ? Number(data.latest_version.require.assets.min_count)
+          : undefined,
+        min_size: data.latest_version.require?.assets?.min_size,
+      },
+      verify: {
+        asset: data.latest_version.require?.verify?.asset,
+        checksums: data.latest_version.require?.verify?.checksums,
+        signature: {
+          type: data.latest_version.require?.verify?.signature?.type,
+          asset: data.latest_version.require?.verify?.signature?.asset,
+          public_key:
+            data.latest_version.require?.verify?.signature?.public_key,
+          keyring: data.latest_version.require?.verify?.signature?.keyring,
+        },
+      },
     };
   }
 
@@ -58,6 +58,19 @@ export default function reducerMonitor(
           // last_queried
           state.service[id].status!.last_queried =
             action.service_data?.status?.last_queried;
+          // next_query
+          state.service[id].status!.next_query =
+            action.service_data?.status?.next_query;
+          // pending_version
+          state.service[id].status!.pending_version =
+            action.service_data?.status?.pending_version;
+          state.service[id].status!.pending_version_timestamp =
+            action.service_data?.status?.pending_version_timestamp;
+          // consecutive_failures/last_error
+          state.service[id].status!.consecutive_failures =
+            action.service_data?.status?.consecutive_failures;
+          state.service[id].status!.last_error =
+            action.service_data?.status?.last_error;
           break;
 
         case "NEW":
@@ -74,6 +87,14 @@ export default function reducerMonitor(
             action.service_data?.status?.latest_version_timestamp;
           state.service[id].status!.last_queried =
             action.service_data?.status?.latest_version_timestamp;
+
+          // latest_version_release
+          state.service[id].status!.latest_version_release =
+            action.service_data?.status?.latest_version_release;
+
+          // pending_version (promoted)
+          state.service[id].status!.pending_version = undefined;
+          state.service[id].status!.pending_version_timestamp = undefined;
           break;
 
         case "UPDATED":
@@ -107,6 +128,10 @@ export default function reducerMonitor(
           state.service[id].status!.last_queried =
             action.service_data?.status?.latest_version_timestamp;
 
+          // latest_version_release
+          state.service[id].status!.latest_version_release =
+            action.service_data?.status?.latest_version_release;
+
           // url
           state.service[id].url =
             action.service_data?.url ?? state.service[id].url;
@@ -155,6 +180,9 @@ export default function reducerMonitor(
         service.has_deployed_version =
           action.service_data?.has_deployed_version ??
           service.has_deployed_version;
+        service.version_constraint =
+          action.service_data?.version_constraint ??
+          service.version_constraint;
         service.command = action.service_data?.command ?? service.command;
         service.webhook = action.service_data?.webhook ?? service.webhook;
         // status
@@ -176,6 +204,13 @@ export default function reducerMonitor(
         service.status!.last_queried =
           action.service_data?.status?.last_queried ??
           service.status!.last_queried;
+        // next_query/consecutive_failures/last_error (sent together when any changed)
+        if (action.service_data?.status?.next_query !== undefined) {
+          service.status!.next_query = action.service_data.status.next_query;
+          service.status!.consecutive_failures =
+            action.service_data.status.consecutive_failures;
+          service.status!.last_error = action.service_data.status.last_error;
+        }
         // create and the service already exists
       } else if (state.service[service.id] !== undefined) {
         console.log(`Service ${service.id} already exists`);
@@ -105,7 +105,14 @@ export interface ServiceOptionsType {
   [key: string]: string | boolean | undefined;
   active?: boolean;
   interval?: string;
+  schedule?: string;
+  jitter?: string;
+  backoff?: string;
+  backoff_max?: string;
+  priority?: string;
   semantic_versioning?: boolean;
+  version_constraint?: string;
+  version_scheme?: string;
 }
 
 export interface ServiceDashboardOptionsType {
@@ -115,10 +122,14 @@ export interface ServiceDashboardOptionsType {
   web_url?: string;
 }
 export interface DockerFilterType {
-  [key: string]: string | undefined;
+  [key: string]: string | string[] | boolean | undefined;
   type?: string;
+  registry?: string;
+  ca_file?: string;
+  allow_invalid_certs?: boolean;
   image?: string;
   tag?: string;
+  platforms?: string[];
   username?: string;
   token?: string;
 }
@@ -136,6 +147,7 @@ export interface BaseLatestVersionLookupType {
   access_token?: string;
   allow_invalid_certs?: boolean;
   use_prerelease?: boolean;
+  use_graphql?: boolean;
   url_commands?: URLCommandType[];
 }
 export interface DefaultLatestVersionLookupType
@@ -164,12 +176,45 @@ export interface DefaultDockerFilterRegistryType {
   username?: string;
 }
 
+export interface AssetsFilterType {
+  [key: string]: string | number | undefined;
+  name?: string;
+  min_count?: number;
+  min_size?: string;
+}
+
+export interface VerifySignatureFilterType {
+  [key: string]: string | undefined;
+  type?: string;
+  asset?: string;
+  public_key?: string;
+  keyring?: string;
+}
+
+export interface VerifyFilterType {
+  [key: string]: string | VerifySignatureFilterType | undefined;
+  asset?: string;
+  checksums?: string;
+  signature?: VerifySignatureFilterType;
+}
+
 export interface LatestVersionFiltersType {
-  [key: string]: string | CommandType | DockerFilterType | undefined;
+  [key: string]:
+    | string
+    | CommandType
+    | DockerFilterType
+    | AssetsFilterType
+    | VerifyFilterType
+    | undefined;
   regex_content?: string;
   regex_version?: string;
+  regex_notes_include?: string;
+  regex_notes_exclude?: string;
   command?: CommandType;
   docker?: DockerFilterType;
+  min_age?: string;
+  assets?: AssetsFilterType;
+  verify?: VerifyFilterType;
 }
 export interface DeployedVersionLookupType {
   [key: string]: string | boolean | undefined | BasicAuthType | HeaderType[];
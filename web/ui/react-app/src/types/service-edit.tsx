@@ -1,4 +1,5 @@
 import {
+  AssetsFilterType,
   BasicAuthType,
   DefaultsType,
   DockerFilterType,
@@ -9,6 +10,7 @@ import {
   NotifyType,
   NotifyTypes,
   ServiceDashboardOptionsType,
+  VerifyFilterType,
   ServiceDict,
   ServiceOptionsType,
   URLCommandType,
@@ -65,15 +67,32 @@ export interface LatestVersionLookupEditType {
   access_token?: string;
   allow_invalid_certs?: boolean;
   use_prerelease?: boolean;
+  use_graphql?: boolean;
   url_commands?: URLCommandType[];
   require?: LatestVersionFiltersEditType;
 }
 export interface LatestVersionFiltersEditType {
-  [key: string]: string | string[] | ArgType[] | DockerFilterType | undefined;
+  [key: string]:
+    | string
+    | string[]
+    | ArgType[]
+    | DockerFilterEditType
+    | AssetsFilterType
+    | VerifyFilterType
+    | undefined;
   command?: ArgType[] | string[];
-  docker?: DockerFilterType;
+  docker?: DockerFilterEditType;
   regex_content?: string;
   regex_version?: string;
+  regex_notes_include?: string;
+  regex_notes_exclude?: string;
+  min_age?: string;
+  assets?: AssetsFilterType;
+  verify?: VerifyFilterType;
+}
+export interface DockerFilterEditType
+  extends Omit<DockerFilterType, "platforms"> {
+  platforms?: string; // comma-separated
 }
 
 export interface DeployedVersionLookupEditType {
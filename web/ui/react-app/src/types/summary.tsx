@@ -15,6 +15,7 @@ export interface ServiceSummaryType {
   icon?: string;
   icon_link_to?: string;
   has_deployed_version?: boolean;
+  version_constraint?: string;
   notify?: boolean;
   webhook?: number;
   command?: number;
@@ -50,6 +51,20 @@ export interface StatusSummaryType {
   latest_version?: string;
   latest_version_timestamp?: string;
   last_queried?: string;
+  next_query?: string;
+  pending_version?: string;
+  pending_version_timestamp?: string;
+  consecutive_failures?: number;
+  last_error?: string;
+  latest_version_release?: StatusReleaseSummaryType;
+}
+
+export interface StatusReleaseSummaryType {
+  name?: string;
+  notes?: string;
+  url?: string;
+  author?: string;
+  published_at?: string;
 }
 
 export interface StatusFailsSummaryType {
@@ -194,6 +194,9 @@ func (w *WebHook) GetURL() (url string) {
 
 	url = util.TemplateString(
 		url,
-		util.ServiceInfo{LatestVersion: w.ServiceStatus.LatestVersion()})
+		util.ServiceInfo{
+			LatestVersion: w.ServiceStatus.LatestVersion(),
+			Asset:         w.ServiceStatus.LatestVersionAsset(),
+			Release:       w.ServiceStatus.LatestVersionRelease()})
 	return
 }
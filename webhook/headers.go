@@ -43,7 +43,9 @@ func (w *WebHook) setCustomHeaders(req *http.Request) {
 
 	serviceInfo := util.ServiceInfo{
 		ID:            *w.ServiceStatus.ServiceID,
-		LatestVersion: w.ServiceStatus.LatestVersion()}
+		LatestVersion: w.ServiceStatus.LatestVersion(),
+		Asset:         w.ServiceStatus.LatestVersionAsset(),
+		Release:       w.ServiceStatus.LatestVersionRelease()}
 	for _, header := range *customHeaders {
 		value := util.TemplateString(header.Value, serviceInfo)
 		req.Header[header.Key] = []string{value}